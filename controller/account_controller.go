@@ -0,0 +1,164 @@
+package controller
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/google/uuid"
+)
+
+type AccountController interface {
+	Export(c *gin.Context)
+	Delete(c *gin.Context)
+	SetStatus(c *gin.Context)
+	GetStatus(c *gin.Context)
+}
+
+type AccountControllerImpl struct {
+	Service 	service.AccountService
+	Translator	ut.Translator
+}
+
+func NewAccountController(service service.AccountService, translator ut.Translator) AccountController {
+	return &AccountControllerImpl{
+		Service: service,
+		Translator: translator,
+	}
+}
+
+func (a *AccountControllerImpl) Export(c *gin.Context) {
+
+	payload := c.MustGet("payload")
+
+	var req web.ExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("format must be json or zip"))
+		return
+	}
+
+	var res web.AccountExport
+	err := a.Service.Export(c, payload.(helper.Payload).UserId, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	if req.Format != "zip" {
+		helper.WriteData(c, res)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="secondhand-export-%s.zip"`, res.Account.Id))
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "application/zip")
+
+	archive := zip.NewWriter(c.Writer)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"account.json", res.Account},
+		{"profile.json", res.Profile},
+		{"products.json", res.Products},
+		{"offers.json", res.Offers},
+	}
+
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			log.Printf("failed to add %s to export archive, err : %v\n", file.name, err)
+			return
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(file.data); err != nil {
+			log.Printf("failed to write %s to export archive, err : %v\n", file.name, err)
+			return
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		log.Printf("failed to finish export archive, err : %v\n", err)
+	}
+}
+
+func (a *AccountControllerImpl) Delete(c *gin.Context) {
+
+	payload := c.MustGet("payload")
+
+	err := a.Service.Delete(c, payload.(helper.Payload).UserId)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	c.SetCookie("token", "", -1, "/", "localhost", false, true)
+
+	helper.WriteMessage(c, "account successfully deleted")
+}
+
+func (a *AccountControllerImpl) SetStatus(c *gin.Context) {
+
+	payload := c.MustGet("payload")
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.AccountStatusRequest
+	if ok := helper.BindData(c, a.Translator, &req); !ok {
+		return
+	}
+
+	req.AccountId = id
+	req.AdminId = payload.(helper.Payload).UserId
+
+	err = a.Service.SetStatus(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("account %s is now %s", id, req.Status))
+}
+
+func (a *AccountControllerImpl) GetStatus(c *gin.Context) {
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var res web.AccountStatusResponse
+	err = a.Service.GetStatus(c, id, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+)
+
+type AuctionController interface {
+	Create(c *gin.Context)
+	GetOpen(c *gin.Context)
+	GetById(c *gin.Context)
+	Bid(c *gin.Context)
+	Cancel(c *gin.Context)
+}
+
+type AuctionControllerImpl struct {
+	Service 	service.AuctionService
+	Translator 	ut.Translator
+}
+
+func NewAuctionController(service service.AuctionService, translator ut.Translator) AuctionController {
+	return &AuctionControllerImpl{
+		Service: service,
+		Translator: translator,
+	}
+}
+
+func (a *AuctionControllerImpl) Create(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	var req web.AuctionRequest
+	if ok := helper.BindData(c, a.Translator, &req); !ok {
+		return
+	}
+
+	req.ProductId = id
+	req.SellerId = payload.UserId
+
+	var res web.AuctionResponse
+	err := a.Service.Create(c, req, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (a *AuctionControllerImpl) GetOpen(c *gin.Context) {
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var res []web.AuctionResponse
+	var meta helper.Meta
+	err := a.Service.GetOpen(c, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (a *AuctionControllerImpl) GetById(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	var res web.AuctionResponse
+	err := a.Service.GetById(c, payload, id, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (a *AuctionControllerImpl) Bid(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	var req web.BidRequest
+	if ok := helper.BindData(c, a.Translator, &req); !ok {
+		return
+	}
+
+	req.AuctionId = id
+	req.BidderId = payload.UserId
+
+	var res web.AuctionResponse
+	err := a.Service.Bid(c, req, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (a *AuctionControllerImpl) Cancel(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	err := a.Service.Cancel(c, payload, id)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "auction successfully cancelled")
+}
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"fmt"
-	"net/http"
 
 	"github.com/RuhullahReza/SecondHand/helper"
 	"github.com/RuhullahReza/SecondHand/model/web"
@@ -15,10 +14,16 @@ import (
 type DataController interface {
 	AddCity(c *gin.Context)
 	GetAllCity(c *gin.Context)
+	GetAllProvince(c *gin.Context)
 	DeleteCity(c *gin.Context)
+	RenameCity(c *gin.Context)
+	CityUsage(c *gin.Context)
 	AddCategory(c *gin.Context)
 	GetAllCategory(c *gin.Context)
 	DeleteCategory(c *gin.Context)
+	RenameCategory(c *gin.Context)
+	SetCategoryApproval(c *gin.Context)
+	CategoryUsage(c *gin.Context)
 }
 
 type DataControllerImpl struct {
@@ -34,7 +39,7 @@ func NewDataController(service service.DataService, translator ut.Translator) Da
 }
 
 func (d *DataControllerImpl) AddCity(c *gin.Context) {
-	var req web.CreateDataRequest
+	var req web.CreateCityRequest
 
 	if ok := helper.BindData(c, d.Translator, &req); !ok {
 		return
@@ -42,33 +47,35 @@ func (d *DataControllerImpl) AddCity(c *gin.Context) {
 
 	err := d.DataService.CreateCity(c, req)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("data City : %s successfully created", req.Name),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("data City : %s successfully created", req.Name))
 }
 
 func (d *DataControllerImpl) GetAllCity(c *gin.Context) {
 
+	var res []web.CityResponse
+	err := d.DataService.GetAllCity(c, c.Query("province"), &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (d *DataControllerImpl) GetAllProvince(c *gin.Context) {
+
 	var res []web.DataResponse
-	err := d.DataService.GetAllCity(c, &res)
+	err := d.DataService.GetAllProvince(c, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 func (d *DataControllerImpl) DeleteCity(c *gin.Context) {
@@ -80,30 +87,27 @@ func (d *DataControllerImpl) DeleteCity(c *gin.Context) {
 	
 	id, err := uuid.Parse(req.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
 
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+	reassignTo, err := reassignTarget(c)
+	if err != nil {
+		helper.WriteError(c, err)
 		return
 	}
 
-	err = d.DataService.DeleteCity(c, id)
+	err = d.DataService.DeleteCity(c, id, reassignTo)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Successfully delete city with Id %s", id),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("Successfully delete city with Id %s", id))
 }
 
 func (d *DataControllerImpl) AddCategory(c *gin.Context) {
-	var req web.CreateDataRequest
+	var req web.CreateCategoryRequest
 
 	if ok := helper.BindData(c, d.Translator, &req); !ok {
 		return
@@ -111,33 +115,23 @@ func (d *DataControllerImpl) AddCategory(c *gin.Context) {
 
 	err := d.DataService.CreateCategory(c, req)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("data Category : %s successfully created", req.Name),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("data Category : %s successfully created", req.Name))
 }
 
 func (d *DataControllerImpl) GetAllCategory(c *gin.Context) {
 
-	var res []web.DataResponse
+	var res []web.CategoryResponse
 	err := d.DataService.GetAllCategory(c, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 func (d *DataControllerImpl) DeleteCategory(c *gin.Context) {
@@ -149,24 +143,166 @@ func (d *DataControllerImpl) DeleteCategory(c *gin.Context) {
 	
 	id, err := uuid.Parse(req.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
 
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+	reassignTo, err := reassignTarget(c)
+	if err != nil {
+		helper.WriteError(c, err)
 		return
 	}
 
-	err = d.DataService.DeleteCategory(c, id)
+	err = d.DataService.DeleteCategory(c, id, reassignTo)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Successfully delete category with Id %s", id))
+}
+
+func (d *DataControllerImpl) RenameCity(c *gin.Context) {
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+	
+	id, err := uuid.Parse(uri.ID)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Successfully delete category with Id %s", id),
-	})
+	var req web.CreateDataRequest
+	if ok := helper.BindData(c, d.Translator, &req); !ok {
+		return
+	}
+
+	err = d.DataService.RenameCity(c, id, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Successfully rename city with Id %s", id))
+}
+
+func (d *DataControllerImpl) CityUsage(c *gin.Context) {
+
+	var req web.GetByIdRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		return
+	}
+	
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var res web.CityUsageResponse
+	err = d.DataService.CityUsage(c, id, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (d *DataControllerImpl) RenameCategory(c *gin.Context) {
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+	
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.RenameCategoryRequest
+	if ok := helper.BindData(c, d.Translator, &req); !ok {
+		return
+	}
+	req.Id = id
+
+	err = d.DataService.RenameCategory(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Successfully rename category with Id %s", id))
+}
+
+func (d *DataControllerImpl) SetCategoryApproval(c *gin.Context) {
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+	
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.CategoryApprovalRequest
+	if ok := helper.BindData(c, d.Translator, &req); !ok {
+		return
+	}
+	req.Id = id
+
+	err = d.DataService.SetCategoryApproval(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Successfully set approval of category with Id %s to : %v", id, *req.RequiresApproval))
+}
+
+func (d *DataControllerImpl) CategoryUsage(c *gin.Context) {
+
+	var req web.GetByIdRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		return
+	}
+	
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var res web.CategoryUsageResponse
+	err = d.DataService.CategoryUsage(c, id, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+// reassignTarget reads the optional reassign_to query parameter of a delete
+func reassignTarget(c *gin.Context) (*uuid.UUID, error) {
+
+	param := c.Query("reassign_to")
+	if param == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(param)
+	if err != nil {
+		return nil, helper.NewBadRequest("invalid uuid")
+	}
+
+	return &id, nil
 }
@@ -0,0 +1,234 @@
+package controller
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/google/uuid"
+)
+
+type DisputeController interface {
+	Open(c *gin.Context)
+	GetMine(c *gin.Context)
+	GetById(c *gin.Context)
+	AddMessage(c *gin.Context)
+	AddEvidence(c *gin.Context)
+	GetQueue(c *gin.Context)
+	Resolve(c *gin.Context)
+}
+
+type DisputeControllerImpl struct {
+	Service 	service.DisputeService
+	Translator 	ut.Translator
+}
+
+func NewDisputeController(service service.DisputeService, translator ut.Translator) DisputeController {
+	return &DisputeControllerImpl{
+		Service: service,
+		Translator: translator,
+	}
+}
+
+func (d *DisputeControllerImpl) Open(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	var req web.DisputeRequest
+	if ok := helper.BindData(c, d.Translator, &req); !ok {
+		return
+	}
+
+	req.TransactionId = id
+	req.BuyerId = payload.UserId
+
+	var res web.DisputeResponse
+	err := d.Service.Open(c, req, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (d *DisputeControllerImpl) GetMine(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var filter web.DisputeFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid dispute filter"))
+		return
+	}
+
+	var res []web.DisputeResponse
+	var meta helper.Meta
+	err := d.Service.GetMine(c, payload.UserId, filter, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (d *DisputeControllerImpl) GetById(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	var res web.DisputeResponse
+	err := d.Service.GetById(c, payload, id, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (d *DisputeControllerImpl) AddMessage(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	var req web.DisputeMessageRequest
+	if ok := helper.BindData(c, d.Translator, &req); !ok {
+		return
+	}
+
+	req.DisputeId = id
+	req.AuthorId = payload.UserId
+
+	err := d.Service.AddMessage(c, payload, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "message successfully sent")
+}
+
+func (d *DisputeControllerImpl) AddEvidence(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 4194304)
+
+	imageFileHeader, err := c.FormFile("imageFile")
+	if err != nil {
+		log.Printf("Unable parse multipart/form-data: %+v", err)
+
+		if err.Error() == "http: request body too large" {
+			helper.WriteError(c, helper.NewPayloadTooLarge(4194304, c.Request.ContentLength))
+			return
+		}
+		helper.WriteError(c, helper.NewBadRequest("Unable to parse multipart/form-data"))
+		return
+	}
+
+	err = d.Service.AddEvidence(c, payload, id, imageFileHeader)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "evidence successfully uploaded")
+}
+
+func (d *DisputeControllerImpl) GetQueue(c *gin.Context) {
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var filter web.DisputeFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid dispute filter"))
+		return
+	}
+
+	var res []web.DisputeResponse
+	var meta helper.Meta
+	err := d.Service.GetQueue(c, filter, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (d *DisputeControllerImpl) Resolve(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	id, ok := uriId(c)
+	if !ok {
+		return
+	}
+
+	var req web.ResolveDisputeRequest
+	if ok := helper.BindData(c, d.Translator, &req); !ok {
+		return
+	}
+
+	req.Id = id
+	req.AdminId = payload.UserId
+
+	err := d.Service.Resolve(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "dispute successfully resolved")
+}
+
+// uriId parses the :id of the route, it writes the error when it is invalid
+func uriId(c *gin.Context) (uuid.UUID, bool) {
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/RuhullahReza/SecondHand/docs"
+	"github.com/gin-gonic/gin"
+)
+
+type DocsController interface {
+	OpenAPI(c *gin.Context)
+	SwaggerUI(c *gin.Context)
+}
+
+type DocsControllerImpl struct {
+	Document *docs.Document
+}
+
+func NewDocsController(document *docs.Document) DocsController {
+	return &DocsControllerImpl{
+		Document: document,
+	}
+}
+
+func (d *DocsControllerImpl) OpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, d.Document)
+}
+
+func (d *DocsControllerImpl) SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerPage))
+}
+
+const swaggerPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>SecondHand API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui", withCredentials: true });
+    };
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController interface {
+	Liveness(c *gin.Context)
+	Readiness(c *gin.Context)
+}
+
+type HealthControllerImpl struct {
+	HealthService service.HealthService
+}
+
+func NewHealthController(service service.HealthService) HealthController {
+	return &HealthControllerImpl{
+		HealthService: service,
+	}
+}
+
+func (h *HealthControllerImpl) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"Status":"Success",
+		"Message":"alive",
+	})
+}
+
+func (h *HealthControllerImpl) Readiness(c *gin.Context) {
+
+	var res web.ReadinessResponse
+	h.HealthService.Readiness(c, &res)
+
+	if !res.Ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"Status":"Fail",
+			"Data":res,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"Status":"Success",
+		"Data":res,
+	})
+}
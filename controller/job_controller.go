@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+)
+
+type JobController interface {
+	GetRuns(c *gin.Context)
+}
+
+type JobControllerImpl struct {
+	Service service.JobService
+}
+
+func NewJobController(service service.JobService) JobController {
+	return &JobControllerImpl{
+		Service: service,
+	}
+}
+
+func (j *JobControllerImpl) GetRuns(c *gin.Context) {
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var filter web.JobRunFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid job run filter"))
+		return
+	}
+
+	var res []web.JobRunResponse
+	var meta helper.Meta
+	err := j.Service.GetRuns(c, filter, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
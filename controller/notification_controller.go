@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationController interface {
+	GetMine(c *gin.Context)
+}
+
+type NotificationControllerImpl struct {
+	Service service.NotificationService
+}
+
+func NewNotificationController(service service.NotificationService) NotificationController {
+	return &NotificationControllerImpl{
+		Service: service,
+	}
+}
+
+func (n *NotificationControllerImpl) GetMine(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var res []web.NotificationResponse
+	var meta helper.Meta
+	err := n.Service.GetByAccount(c, payload.UserId, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
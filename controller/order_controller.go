@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/google/uuid"
+)
+
+type OrderController interface {
+	GetMine(c *gin.Context)
+	GetById(c *gin.Context)
+	UpdateStatus(c *gin.Context)
+}
+
+type OrderControllerImpl struct {
+	Service 	service.OrderService
+	Translator	ut.Translator
+}
+
+func NewOrderController(service service.OrderService, translator ut.Translator) OrderController {
+	return &OrderControllerImpl{
+		Service: service,
+		Translator: translator,
+	}
+}
+
+func (o *OrderControllerImpl) GetMine(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var filter web.OrderFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid order filter"))
+		return
+	}
+
+	var res []web.OrderResponse
+	var meta helper.Meta
+	err := o.Service.GetMine(c, payload.UserId, filter, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (o *OrderControllerImpl) GetById(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var res web.OrderResponse
+	err = o.Service.GetById(c, payload, id, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (o *OrderControllerImpl) UpdateStatus(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.OrderStatusRequest
+	if ok := helper.BindData(c, o.Translator, &req); !ok {
+		return
+	}
+
+	req.Id = id
+	req.AccountId = payload.UserId
+
+	err = o.Service.UpdateStatus(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Successfully updated order with Id : %s to : %s", id, req.Status))
+}
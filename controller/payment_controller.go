@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PaymentController interface {
+	Pay(c *gin.Context)
+	Webhook(c *gin.Context)
+}
+
+type PaymentControllerImpl struct {
+	Service service.PaymentService
+}
+
+func NewPaymentController(service service.PaymentService) PaymentController {
+	return &PaymentControllerImpl{
+		Service: service,
+	}
+}
+
+func (p *PaymentControllerImpl) Pay(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var res web.PaymentResponse
+	err = p.Service.Pay(c, payload, id, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+// Webhook takes events from the payment provider, the body is read as is
+// because the signature covers its exact bytes
+func (p *PaymentControllerImpl) Webhook(c *gin.Context) {
+
+	body, err := c.GetRawData()
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid payment event"))
+		return
+	}
+
+	err = p.Service.HandleWebhook(c, body, c.GetHeader("X-Signature"))
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "event received")
+}
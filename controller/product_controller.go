@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
 	"github.com/RuhullahReza/SecondHand/model/web"
 	"github.com/RuhullahReza/SecondHand/service"
 	"github.com/gin-gonic/gin"
@@ -28,7 +29,9 @@ type ProductController interface {
 	DeleteProductImage(c *gin.Context)
 	UpdatePublishStatus(c *gin.Context)
 	UpdateSoldStatus(c *gin.Context)
-	
+	ReleaseReservation(c *gin.Context)
+	GetPendingReview(c *gin.Context)
+	ReviewProduct(c *gin.Context)
 }
 
 type ProductControllerImpl struct {
@@ -57,33 +60,36 @@ func (p *ProductControllerImpl) AddProduct(c *gin.Context) {
 
 	err := p.ProductService.CreateProduct(c, req, account_id)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Product : %s successfully created", req.Name),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("Product : %s successfully created", req.Name))
 }
 
 func (p *ProductControllerImpl) GetAllProduct(c *gin.Context) {
 
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var filter web.ProductFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid product filter"))
+		return
+	}
+
 	var res []web.ProductResponse
-	err := p.ProductService.GetAllProduct(c, &res)
+	var meta helper.Meta
+	err := p.ProductService.GetAllProduct(c, filter, page, &res, &meta)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WritePage(c, res, meta)
 }
 
 func (p *ProductControllerImpl) GetByCategory(c *gin.Context) {
@@ -93,19 +99,21 @@ func (p *ProductControllerImpl) GetByCategory(c *gin.Context) {
 		return
 	}
 
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
 	var res []web.ProductResponse
-	err := p.ProductService.GetByCategory(c, param.Path, &res)
+	var meta helper.Meta
+	err := p.ProductService.GetByCategory(c, param.Path, page, &res, &meta)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WritePage(c, res, meta)
 }
 
 func (p *ProductControllerImpl) GetMyProduct(c *gin.Context) {
@@ -128,16 +136,11 @@ func (p *ProductControllerImpl) GetMyProduct(c *gin.Context) {
 	var res []web.ProductResponse
 	err = p.ProductService.GetByAccount(c, id, sold, published, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 
@@ -150,27 +153,18 @@ func (p *ProductControllerImpl) GetProductByAccount(c *gin.Context) {
 
 	account_id, err := uuid.Parse(param.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
 	var res []web.ProductResponse
 	err = p.ProductService.GetByAccount(c, account_id, false, true, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 func (p *ProductControllerImpl) GetProductById(c *gin.Context) {
@@ -184,27 +178,18 @@ func (p *ProductControllerImpl) GetProductById(c *gin.Context) {
 
 	product_id, err := uuid.Parse(param.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
 	var res web.ProductDetailResponse
 	err = p.ProductService.GetProductById(c, payload, product_id, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 func (p *ProductControllerImpl) UpdateProduct(c *gin.Context) {
@@ -218,11 +203,7 @@ func (p *ProductControllerImpl) UpdateProduct(c *gin.Context) {
 
 	productId, err := uuid.Parse(param.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
@@ -237,16 +218,11 @@ func (p *ProductControllerImpl) UpdateProduct(c *gin.Context) {
 
 	err = p.ProductService.UpdateProduct(c, req)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Product id: %s successfully updated", req.Id),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("Product id: %s successfully updated", req.Id))
 }
 
 func (p *ProductControllerImpl) AddProductImage(c *gin.Context) {
@@ -259,11 +235,7 @@ func (p *ProductControllerImpl) AddProductImage(c *gin.Context) {
 
 	productId, err := uuid.Parse(param.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
@@ -275,30 +247,20 @@ func (p *ProductControllerImpl) AddProductImage(c *gin.Context) {
 		log.Printf("Unable parse multipart/form-data: %+v", err)
 
 		if err.Error() == "http: request body too large" {
-			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
-				"error": fmt.Sprintf("Max request body size is %v bytes\n", 4194304),
-			})
+			helper.WriteError(c, helper.NewPayloadTooLarge(4194304, c.Request.ContentLength))
 			return
 		}
-		e := helper.NewBadRequest("Unable to parse multipart/form-data")
-		c.JSON(e.Status(), gin.H{
-			"error": e,
-		})
+		helper.WriteError(c, helper.NewBadRequest("Unable to parse multipart/form-data"))
 		return
 	}
 	
 	err = p.ProductService.AddProductImage(c, payload, productId, imageFileHeader)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("successfuly upload image for product id : %s", productId),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("successfuly upload image for product id : %s", productId))
 }
 
 func (p *ProductControllerImpl) UpdateProductThumbnail(c *gin.Context) {
@@ -316,16 +278,11 @@ func (p *ProductControllerImpl) UpdateProductThumbnail(c *gin.Context) {
 
 	err := p.ProductService.SetThumbnail(c, AccountId, req.ProductId, req.ImageId)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Product id: %s thumbnail successfully updated", req.ProductId),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("Product id: %s thumbnail successfully updated", req.ProductId))
 }
 
 func (p *ProductControllerImpl) DeleteProductImage(c *gin.Context) {
@@ -343,16 +300,11 @@ func (p *ProductControllerImpl) DeleteProductImage(c *gin.Context) {
 
 	err := p.ProductService.DeleteImageProduct(c, AccountId, req.ProductId, req.ImageId)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Image id: %s from Product Id: %s successfully deleted", req.ImageId, req.ProductId),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("Image id: %s from Product Id: %s successfully deleted", req.ImageId, req.ProductId))
 }
 
 func (p *ProductControllerImpl) DeleteProduct(c *gin.Context) {
@@ -366,26 +318,17 @@ func (p *ProductControllerImpl) DeleteProduct(c *gin.Context) {
 	
 	id, err := uuid.Parse(req.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
 	err = p.ProductService.DeleteProduct(c, payload, id)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Successfully delete product with Id : %s", id),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("Successfully delete product with Id : %s", id))
 }
 
 func (p *ProductControllerImpl) UpdatePublishStatus(c *gin.Context) {
@@ -399,27 +342,23 @@ func (p *ProductControllerImpl) UpdatePublishStatus(c *gin.Context) {
 	
 	id, err := uuid.Parse(req.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
-	var res bool
+	var res web.PublishResponse
 	err = p.ProductService.UpdatePublished(c, payload, id, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
+		return
+	}
+
+	if res.ApprovalStatus == entity.ApprovalPending {
+		helper.WriteMessage(c, fmt.Sprintf("Product with Id : %s submitted for review, it will be published once approved", id))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Successfully updated publish status product with Id : %s to : %v", id, res),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("Successfully updated publish status product with Id : %s to : %v", id, res.Published))
 }
 
 func (p *ProductControllerImpl) UpdateSoldStatus(c *gin.Context) {
@@ -433,25 +372,91 @@ func (p *ProductControllerImpl) UpdateSoldStatus(c *gin.Context) {
 	
 	id, err := uuid.Parse(req.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
 	var res bool
 	err = p.ProductService.UpdateSold(c, payload, id, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Successfully updated sold status product with Id : %s to : %v", id, res))
+}
+
+func (p *ProductControllerImpl) ReleaseReservation(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var req web.GetByIdRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		return
+	}
+	
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Successfully updated sold status product with Id : %s to : %v", id, res),
-	})
-}
\ No newline at end of file
+	err = p.ProductService.ReleaseReservation(c, payload, id)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Successfully released the reservation of product with Id : %s", id))
+}
+
+func (p *ProductControllerImpl) GetPendingReview(c *gin.Context) {
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var res []web.PendingProductResponse
+	var meta helper.Meta
+	err := p.ProductService.GetPendingReview(c, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (p *ProductControllerImpl) ReviewProduct(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+	
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.ProductReviewRequest
+	if ok := helper.BindData(c, p.Translator, &req); !ok {
+		return
+	}
+
+	req.Id = id
+	req.AdminId = payload.UserId
+
+	err = p.ProductService.Review(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Successfully reviewed product with Id : %s", id))
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/google/uuid"
+)
+
+type ReportController interface {
+	ReportProduct(c *gin.Context)
+	ReportProfile(c *gin.Context)
+	ReportTransaction(c *gin.Context)
+	GetQueue(c *gin.Context)
+	Resolve(c *gin.Context)
+}
+
+type ReportControllerImpl struct {
+	Service 	service.ReportService
+	Translator	ut.Translator
+}
+
+func NewReportController(service service.ReportService, translator ut.Translator) ReportController {
+	return &ReportControllerImpl{
+		Service: service,
+		Translator: translator,
+	}
+}
+
+func (r *ReportControllerImpl) ReportProduct(c *gin.Context) {
+	r.report(c, entity.ReportProduct)
+}
+
+func (r *ReportControllerImpl) ReportProfile(c *gin.Context) {
+	r.report(c, entity.ReportProfile)
+}
+
+func (r *ReportControllerImpl) ReportTransaction(c *gin.Context) {
+	r.report(c, entity.ReportTransaction)
+}
+
+func (r *ReportControllerImpl) report(c *gin.Context, targetType string) {
+
+	payload := c.MustGet("payload")
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.ReportRequest
+	if ok := helper.BindData(c, r.Translator, &req); !ok {
+		return
+	}
+
+	req.ReporterId = payload.(helper.Payload).UserId
+	req.TargetType = targetType
+	req.TargetId = id
+
+	err = r.Service.Create(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "report received, a moderator will review it")
+}
+
+func (r *ReportControllerImpl) GetQueue(c *gin.Context) {
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var filter web.ReportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid report filter"))
+		return
+	}
+
+	var res []web.ReportResponse
+	var meta helper.Meta
+	err := r.Service.GetQueue(c, filter, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (r *ReportControllerImpl) Resolve(c *gin.Context) {
+
+	payload := c.MustGet("payload")
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.ResolveReportRequest
+	if ok := helper.BindData(c, r.Translator, &req); !ok {
+		return
+	}
+
+	req.Id = id
+	req.AdminId = payload.(helper.Payload).UserId
+
+	err = r.Service.Resolve(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "report successfully resolved")
+}
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"fmt"
-	"net/http"
 
 	"github.com/RuhullahReza/SecondHand/helper"
 	"github.com/RuhullahReza/SecondHand/model/web"
@@ -21,6 +20,8 @@ type TransactionController interface {
 	GetMyTransaction(c *gin.Context)
 	UpdatePriceOffer(c *gin.Context)
 	UpdateTransactionStatus(c *gin.Context)
+	CounterOffer(c *gin.Context)
+	AcceptCounter(c *gin.Context)
 }
 
 type TransactionControllerImpl struct {
@@ -46,18 +47,24 @@ func (t *TransactionControllerImpl) AddTransaction(c *gin.Context) {
 
 	req.BuyerId = payload.UserId
 
-	err := t.TransactionService.Create(c, req)
+	var res web.OfferResponse
+	err := t.TransactionService.Create(c, req, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Transaction for product id: %s successfully created", req.ProductId),
-	})
+	if res.Accepted {
+		helper.WriteMessage(c, fmt.Sprintf("Your offer for product id: %s was accepted, the product is reserved for you", req.ProductId))
+		return
+	}
+
+	if !res.Created {
+		helper.WriteMessage(c, fmt.Sprintf("Your open offer for product id: %s was updated to %d", req.ProductId, req.Price))
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Transaction for product id: %s successfully created", req.ProductId))
 }
 
 func (t *TransactionControllerImpl) GetTransactionDetail(c *gin.Context) {
@@ -71,27 +78,18 @@ func (t *TransactionControllerImpl) GetTransactionDetail(c *gin.Context) {
 
 	transaction_id, err := uuid.Parse(param.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
 	var res web.TransactionDetailResponse
 	err = t.TransactionService.GetTransactionDetail(c, payload, transaction_id, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 func (t *TransactionControllerImpl) GetTransactionByProduct(c *gin.Context) {
@@ -105,27 +103,18 @@ func (t *TransactionControllerImpl) GetTransactionByProduct(c *gin.Context) {
 
 	id, err := uuid.Parse(param.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
 	var res web.OfferByProduct
 	err = t.TransactionService.GetOfferByProduct(c, payload, id, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 func (t *TransactionControllerImpl) GetTransactionByBuyer(c *gin.Context) {
@@ -139,27 +128,18 @@ func (t *TransactionControllerImpl) GetTransactionByBuyer(c *gin.Context) {
 
 	id, err := uuid.Parse(param.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
 	var res web.OfferByBuyer
 	err = t.TransactionService.GetOfferByBuyer(c, payload, id, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 
@@ -175,16 +155,11 @@ func (t *TransactionControllerImpl) GetTransactionByAccount(c *gin.Context) {
 	var res []web.OfferWithAccount
 	err := t.TransactionService.GetOfferByAccount(c, payload.UserId, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 func (t *TransactionControllerImpl) GetMyTransaction(c *gin.Context) {
@@ -199,16 +174,11 @@ func (t *TransactionControllerImpl) GetMyTransaction(c *gin.Context) {
 	var res []web.OfferWithAccount
 	err := t.TransactionService.GetMyTransaction(c, payload.UserId, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Data":res,
-	})
+	helper.WriteData(c, res)
 }
 
 func (t *TransactionControllerImpl) UpdatePriceOffer(c *gin.Context) {
@@ -222,18 +192,19 @@ func (t *TransactionControllerImpl) UpdatePriceOffer(c *gin.Context) {
 
 	req.BuyerId = payload.UserId
 
-	err := t.TransactionService.UpdatePriceOffer(c, req)
+	var res web.OfferResponse
+	err := t.TransactionService.UpdatePriceOffer(c, req, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Transaction id: %s successfully updated", req.TransactionId),
-	})
+	if res.Accepted {
+		helper.WriteMessage(c, fmt.Sprintf("Transaction id: %s was accepted, the product is reserved for you", req.TransactionId))
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Transaction id: %s successfully updated", req.TransactionId))
 }
 
 func (t *TransactionControllerImpl) UpdateTransactionStatus(c *gin.Context) {
@@ -247,25 +218,72 @@ func (t *TransactionControllerImpl) UpdateTransactionStatus(c *gin.Context) {
 
 	transactionId, err := uuid.Parse(param.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
 	var res bool
 	err = t.TransactionService.UpdateStatus(c, transactionId, payload.UserId, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Transaction id: %s status set to: %v", transactionId, res))
+}
+
+func (t *TransactionControllerImpl) CounterOffer(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var param web.GetByIdRequest
+	if err := c.ShouldBindUri(&param); err != nil {
+		return
+	}
+
+	transactionId, err := uuid.Parse(param.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.CounterOfferRequest
+	if ok := helper.BindData(c, t.Translator, &req); !ok {
+		return
+	}
+
+	req.TransactionId = transactionId
+	req.SellerId = payload.UserId
+
+	err = t.TransactionService.CounterOffer(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, fmt.Sprintf("Transaction id: %s countered with price: %d", transactionId, req.Price))
+}
+
+func (t *TransactionControllerImpl) AcceptCounter(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var param web.GetByIdRequest
+	if err := c.ShouldBindUri(&param); err != nil {
+		return
+	}
+
+	transactionId, err := uuid.Parse(param.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	err = t.TransactionService.AcceptCounter(c, transactionId, payload.UserId)
+	if err != nil {
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("Transaction id: %s status set to: %v", transactionId, res),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("Transaction id: %s counter-offer accepted", transactionId))
 }
@@ -19,6 +19,7 @@ type UserController interface {
 	RegisterAdmin(c *gin.Context)
 	Update(c *gin.Context)
 	MyProfile(c *gin.Context)
+	UpdatePrivacy(c *gin.Context)
 	Profile(c *gin.Context)
 	UpdateImage(c *gin.Context)
 }
@@ -46,15 +47,13 @@ func (u *UserControllerImpl) Login(c *gin.Context) {
 
 	err := u.Service.Login(c, req, &res)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
 	c.SetCookie("token", res.Token, 3600, "/", "localhost", false, true)
 
-	c.JSON(http.StatusOK, res)
+	helper.WriteDataAs(c, "", res)
 }
 
 func (u *UserControllerImpl) Register(c *gin.Context) {
@@ -66,16 +65,11 @@ func (u *UserControllerImpl) Register(c *gin.Context) {
 	
 	err := u.Service.Register(c, req, "USER")
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("account with email : %s successfully created", req.Email),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("account with email : %s successfully created", req.Email))
 }
 
 func (u *UserControllerImpl) RegisterAdmin(c *gin.Context) {
@@ -87,16 +81,11 @@ func (u *UserControllerImpl) RegisterAdmin(c *gin.Context) {
 
 	err := u.Service.Register(c, req, "ADMIN")
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status":"Success",
-		"Message":fmt.Sprintf("account with email : %s successfully created", req.Email),
-	})
+	helper.WriteMessage(c, fmt.Sprintf("account with email : %s successfully created", req.Email))
 }
 
 func (u *UserControllerImpl) Update(c *gin.Context) {
@@ -113,16 +102,11 @@ func (u *UserControllerImpl) Update(c *gin.Context) {
  
 	err := u.Service.UpdateProfile(c, req)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status" : "Success",
-		"Message" : "profile successfully updated",
-	})
+	helper.WriteMessage(c, "profile successfully updated")
 }
 
 func (u *UserControllerImpl) MyProfile(c *gin.Context) {
@@ -133,18 +117,13 @@ func (u *UserControllerImpl) MyProfile(c *gin.Context) {
 
 	var res web.GetProfileResponse
 
-	err := u.Service.GetProfile(c, &res, id)
+	err := u.Service.GetProfile(c, &res, id, payload.(helper.Payload))
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status" : "Success",
-		"Message" : res,
-	})
+	helper.WriteDataAs(c, "Message", res)
 }
 
 func (u *UserControllerImpl) Profile(c *gin.Context) {
@@ -156,28 +135,25 @@ func (u *UserControllerImpl) Profile(c *gin.Context) {
 	
 	id, err := uuid.Parse(req.ID)
 	if err != nil {
-		err := helper.NewBadRequest("invalid uuid")
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
 		return
 	}
 
+	// the route is public, the payload is only set for logged in viewers
+	var viewer helper.Payload
+	if payload, ok := c.Get("payload"); ok {
+		viewer = payload.(helper.Payload)
+	}
+
 	var res web.GetProfileResponse
 
-	err = u.Service.GetProfile(c, &res, id)
+	err = u.Service.GetProfile(c, &res, id, viewer)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err,
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status" : "Success",
-		"Message" : res,
-	})
+	helper.WriteDataAs(c, "Message", res)
 }
 
 func (u *UserControllerImpl) UpdateImage(c *gin.Context) {
@@ -193,28 +169,38 @@ func (u *UserControllerImpl) UpdateImage(c *gin.Context) {
 		log.Printf("Unable parse multipart/form-data: %+v", err)
 
 		if err.Error() == "http: request body too large" {
-			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
-				"error": fmt.Sprintf("Max request body size is %v bytes\n", 4194304),
-			})
+			helper.WriteError(c, helper.NewPayloadTooLarge(4194304, c.Request.ContentLength))
 			return
 		}
-		e := helper.NewBadRequest("Unable to parse multipart/form-data")
-		c.JSON(e.Status(), gin.H{
-			"error": e,
-		})
+		helper.WriteError(c, helper.NewBadRequest("Unable to parse multipart/form-data"))
 		return
 	}
 
 	err = u.Service.UpdateImage(c, id, imageFileHeader)
 	if err != nil {
-		c.JSON(helper.Status(err), gin.H{
-			"error": err.Error(),
-		})
+		helper.WriteError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"Status" : "Success",
-		"Message" : "Successfuly update image",
-	})
-}
\ No newline at end of file
+	helper.WriteMessage(c, "Successfuly update image")
+}
+
+func (u *UserControllerImpl) UpdatePrivacy(c *gin.Context) {
+
+	payload := c.MustGet("payload")
+
+	var req web.PrivacySettings
+	if ok := helper.BindData(c, u.Translator, &req); !ok {
+		return
+	}
+
+	req.Id = payload.(helper.Payload).UserId
+
+	err := u.Service.UpdatePrivacy(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "privacy settings successfully updated")
+}
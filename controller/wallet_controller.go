@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/google/uuid"
+)
+
+type WalletController interface {
+	GetWallet(c *gin.Context)
+	GetStatement(c *gin.Context)
+	RequestPayout(c *gin.Context)
+	GetMyPayouts(c *gin.Context)
+	GetPayouts(c *gin.Context)
+	ReviewPayout(c *gin.Context)
+}
+
+type WalletControllerImpl struct {
+	Service 	service.LedgerService
+	Translator	ut.Translator
+}
+
+func NewWalletController(service service.LedgerService, translator ut.Translator) WalletController {
+	return &WalletControllerImpl{
+		Service: service,
+		Translator: translator,
+	}
+}
+
+func (w *WalletControllerImpl) GetWallet(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var res web.WalletResponse
+	err := w.Service.GetWallet(c, payload.UserId, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (w *WalletControllerImpl) GetStatement(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var res []web.StatementLine
+	var meta helper.Meta
+	err := w.Service.GetStatement(c, payload.UserId, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (w *WalletControllerImpl) RequestPayout(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var req web.PayoutRequest
+	if ok := helper.BindData(c, w.Translator, &req); !ok {
+		return
+	}
+
+	req.SellerId = payload.UserId
+
+	var res web.PayoutResponse
+	err := w.Service.RequestPayout(c, req, &res)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteData(c, res)
+}
+
+func (w *WalletControllerImpl) GetMyPayouts(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var res []web.PayoutResponse
+	var meta helper.Meta
+	err := w.Service.GetMyPayouts(c, payload.UserId, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (w *WalletControllerImpl) GetPayouts(c *gin.Context) {
+
+	var page web.PageRequest
+	if err := c.ShouldBindQuery(&page); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("page and per_page must be numbers"))
+		return
+	}
+
+	var filter web.PayoutFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid payout filter"))
+		return
+	}
+
+	var res []web.PayoutResponse
+	var meta helper.Meta
+	err := w.Service.GetPayouts(c, filter, page, &res, &meta)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WritePage(c, res, meta)
+}
+
+func (w *WalletControllerImpl) ReviewPayout(c *gin.Context) {
+
+	payload := c.MustGet("payload").(helper.Payload)
+
+	var uri web.GetByIdRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		return
+	}
+
+	id, err := uuid.Parse(uri.ID)
+	if err != nil {
+		helper.WriteError(c, helper.NewBadRequest("invalid uuid"))
+		return
+	}
+
+	var req web.PayoutReviewRequest
+	if ok := helper.BindData(c, w.Translator, &req); !ok {
+		return
+	}
+
+	req.Id = id
+	req.AdminId = payload.UserId
+
+	err = w.Service.ReviewPayout(c, req)
+	if err != nil {
+		helper.WriteError(c, err)
+		return
+	}
+
+	helper.WriteMessage(c, "payout successfully reviewed")
+}
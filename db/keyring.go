@@ -0,0 +1,29 @@
+package db
+
+import (
+	"encoding/base64"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/RuhullahReza/SecondHand/util/config"
+)
+
+func NewKeyring() *util.Keyring {
+
+	keys, err := util.ParseKeys(config.EncryptionKeys())
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(config.BlindIndexKey())
+	if err != nil {
+		log.Fatalln("BLIND_INDEX_KEY is not valid base64")
+	}
+
+	keyring, err := util.NewKeyring(keys, config.EncryptionActiveKey(), indexKey)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	return keyring
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"embed"
+	"strconv"
+	"strings"
+)
+
+//go:embed migration/*.sql
+var migrations embed.FS
+
+// LatestMigrationVersion returns the highest version shipped in db/migration,
+// which is the schema version this build expects to run against
+func LatestMigrationVersion() uint {
+
+	entries, err := migrations.ReadDir("migration")
+	if err != nil {
+		return 0
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		prefix := strings.SplitN(entry.Name(), "_", 2)[0]
+
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+
+	return latest
+}
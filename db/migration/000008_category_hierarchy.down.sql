@@ -0,0 +1,14 @@
+ALTER TABLE "products" ADD COLUMN "category" VARCHAR;
+
+UPDATE "products" SET "category" = "categories"."name"
+FROM "categories"
+WHERE "categories"."id" = "products"."category_id";
+
+ALTER TABLE "products" ALTER COLUMN "category" SET NOT NULL;
+CREATE INDEX ON "products" ("category");
+
+ALTER TABLE "products" DROP COLUMN "category_id";
+
+ALTER TABLE "categories" DROP COLUMN "parent_id";
+ALTER TABLE "categories" DROP COLUMN "sort_order";
+ALTER TABLE "categories" DROP COLUMN "slug";
@@ -0,0 +1,32 @@
+ALTER TABLE "categories" ADD COLUMN "slug" VARCHAR;
+ALTER TABLE "categories" ADD COLUMN "parent_id" uuid;
+ALTER TABLE "categories" ADD COLUMN "sort_order" INT NOT NULL DEFAULT 0;
+
+INSERT INTO "categories" ("name")
+SELECT DISTINCT "category" FROM "products"
+WHERE "category" NOT IN (SELECT "name" FROM "categories");
+
+UPDATE "categories"
+SET "slug" = trim(both '-' from lower(regexp_replace(trim("name"), '[^a-zA-Z0-9]+', '-', 'g')));
+
+UPDATE "categories" SET "slug" = "slug" || '-' || left("id"::text, 8)
+WHERE "slug" = '' OR "slug" IN (
+  SELECT "slug" FROM "categories" GROUP BY "slug" HAVING count(*) > 1
+);
+
+ALTER TABLE "categories" ALTER COLUMN "slug" SET NOT NULL;
+ALTER TABLE "categories" ADD CONSTRAINT "categories_slug_key" UNIQUE ("slug");
+ALTER TABLE "categories" ADD FOREIGN KEY ("parent_id") REFERENCES "categories" ("id");
+CREATE INDEX ON "categories" ("parent_id");
+
+ALTER TABLE "products" ADD COLUMN "category_id" uuid;
+
+UPDATE "products" SET "category_id" = "categories"."id"
+FROM "categories"
+WHERE "categories"."name" = "products"."category";
+
+ALTER TABLE "products" ALTER COLUMN "category_id" SET NOT NULL;
+ALTER TABLE "products" ADD FOREIGN KEY ("category_id") REFERENCES "categories" ("id");
+CREATE INDEX ON "products" ("category_id");
+
+ALTER TABLE "products" DROP COLUMN "category";
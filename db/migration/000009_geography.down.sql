@@ -0,0 +1,6 @@
+ALTER TABLE "profiles" DROP CONSTRAINT "profiles_city_fkey";
+DROP INDEX IF EXISTS "profiles_city_idx";
+
+-- seeded cities are kept, profiles may already refer to them
+ALTER TABLE "cities" DROP COLUMN "province_id";
+DROP TABLE IF EXISTS "provinces";
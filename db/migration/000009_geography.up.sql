@@ -0,0 +1,581 @@
+CREATE TABLE "provinces" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "name" VARCHAR NOT NULL UNIQUE,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "cities" ADD COLUMN "province_id" uuid;
+ALTER TABLE "cities" ADD FOREIGN KEY ("province_id") REFERENCES "provinces" ("id");
+CREATE INDEX ON "cities" ("province_id");
+
+-- Indonesian provinces and their regencies (kabupaten) and cities (kota)
+INSERT INTO "provinces" ("name") VALUES
+  ('Aceh'),
+  ('Sumatera Utara'),
+  ('Sumatera Barat'),
+  ('Riau'),
+  ('Jambi'),
+  ('Sumatera Selatan'),
+  ('Bengkulu'),
+  ('Lampung'),
+  ('Kepulauan Bangka Belitung'),
+  ('Kepulauan Riau'),
+  ('DKI Jakarta'),
+  ('Jawa Barat'),
+  ('Jawa Tengah'),
+  ('DI Yogyakarta'),
+  ('Jawa Timur'),
+  ('Banten'),
+  ('Bali'),
+  ('Nusa Tenggara Barat'),
+  ('Nusa Tenggara Timur'),
+  ('Kalimantan Barat'),
+  ('Kalimantan Tengah'),
+  ('Kalimantan Selatan'),
+  ('Kalimantan Timur'),
+  ('Kalimantan Utara'),
+  ('Sulawesi Utara'),
+  ('Sulawesi Tengah'),
+  ('Sulawesi Selatan'),
+  ('Sulawesi Tenggara'),
+  ('Gorontalo'),
+  ('Sulawesi Barat'),
+  ('Maluku'),
+  ('Maluku Utara'),
+  ('Papua'),
+  ('Papua Barat'),
+  ('Papua Selatan'),
+  ('Papua Tengah'),
+  ('Papua Pegunungan'),
+  ('Papua Barat Daya');
+
+INSERT INTO "cities" ("name", "province_id")
+SELECT "seed"."name", "provinces"."id"
+FROM (VALUES
+  ('Aceh', 'Kabupaten Aceh Selatan'),
+  ('Aceh', 'Kabupaten Aceh Tenggara'),
+  ('Aceh', 'Kabupaten Aceh Timur'),
+  ('Aceh', 'Kabupaten Aceh Tengah'),
+  ('Aceh', 'Kabupaten Aceh Barat'),
+  ('Aceh', 'Kabupaten Aceh Besar'),
+  ('Aceh', 'Kabupaten Pidie'),
+  ('Aceh', 'Kabupaten Aceh Utara'),
+  ('Aceh', 'Kabupaten Simeulue'),
+  ('Aceh', 'Kabupaten Aceh Singkil'),
+  ('Aceh', 'Kabupaten Bireuen'),
+  ('Aceh', 'Kabupaten Aceh Barat Daya'),
+  ('Aceh', 'Kabupaten Gayo Lues'),
+  ('Aceh', 'Kabupaten Aceh Jaya'),
+  ('Aceh', 'Kabupaten Nagan Raya'),
+  ('Aceh', 'Kabupaten Aceh Tamiang'),
+  ('Aceh', 'Kabupaten Bener Meriah'),
+  ('Aceh', 'Kabupaten Pidie Jaya'),
+  ('Aceh', 'Kota Banda Aceh'),
+  ('Aceh', 'Kota Sabang'),
+  ('Aceh', 'Kota Lhokseumawe'),
+  ('Aceh', 'Kota Langsa'),
+  ('Aceh', 'Kota Subulussalam'),
+  ('Sumatera Utara', 'Kabupaten Tapanuli Tengah'),
+  ('Sumatera Utara', 'Kabupaten Tapanuli Utara'),
+  ('Sumatera Utara', 'Kabupaten Tapanuli Selatan'),
+  ('Sumatera Utara', 'Kabupaten Nias'),
+  ('Sumatera Utara', 'Kabupaten Langkat'),
+  ('Sumatera Utara', 'Kabupaten Karo'),
+  ('Sumatera Utara', 'Kabupaten Deli Serdang'),
+  ('Sumatera Utara', 'Kabupaten Simalungun'),
+  ('Sumatera Utara', 'Kabupaten Asahan'),
+  ('Sumatera Utara', 'Kabupaten Labuhanbatu'),
+  ('Sumatera Utara', 'Kabupaten Dairi'),
+  ('Sumatera Utara', 'Kabupaten Toba'),
+  ('Sumatera Utara', 'Kabupaten Mandailing Natal'),
+  ('Sumatera Utara', 'Kabupaten Nias Selatan'),
+  ('Sumatera Utara', 'Kabupaten Pakpak Bharat'),
+  ('Sumatera Utara', 'Kabupaten Humbang Hasundutan'),
+  ('Sumatera Utara', 'Kabupaten Samosir'),
+  ('Sumatera Utara', 'Kabupaten Serdang Bedagai'),
+  ('Sumatera Utara', 'Kabupaten Batu Bara'),
+  ('Sumatera Utara', 'Kabupaten Padang Lawas Utara'),
+  ('Sumatera Utara', 'Kabupaten Padang Lawas'),
+  ('Sumatera Utara', 'Kabupaten Labuhanbatu Selatan'),
+  ('Sumatera Utara', 'Kabupaten Labuhanbatu Utara'),
+  ('Sumatera Utara', 'Kabupaten Nias Utara'),
+  ('Sumatera Utara', 'Kabupaten Nias Barat'),
+  ('Sumatera Utara', 'Kota Medan'),
+  ('Sumatera Utara', 'Kota Pematangsiantar'),
+  ('Sumatera Utara', 'Kota Sibolga'),
+  ('Sumatera Utara', 'Kota Tanjungbalai'),
+  ('Sumatera Utara', 'Kota Binjai'),
+  ('Sumatera Utara', 'Kota Tebing Tinggi'),
+  ('Sumatera Utara', 'Kota Padangsidimpuan'),
+  ('Sumatera Utara', 'Kota Gunungsitoli'),
+  ('Sumatera Barat', 'Kabupaten Pesisir Selatan'),
+  ('Sumatera Barat', 'Kabupaten Solok'),
+  ('Sumatera Barat', 'Kabupaten Sijunjung'),
+  ('Sumatera Barat', 'Kabupaten Tanah Datar'),
+  ('Sumatera Barat', 'Kabupaten Padang Pariaman'),
+  ('Sumatera Barat', 'Kabupaten Agam'),
+  ('Sumatera Barat', 'Kabupaten Lima Puluh Kota'),
+  ('Sumatera Barat', 'Kabupaten Pasaman'),
+  ('Sumatera Barat', 'Kabupaten Kepulauan Mentawai'),
+  ('Sumatera Barat', 'Kabupaten Dharmasraya'),
+  ('Sumatera Barat', 'Kabupaten Solok Selatan'),
+  ('Sumatera Barat', 'Kabupaten Pasaman Barat'),
+  ('Sumatera Barat', 'Kota Padang'),
+  ('Sumatera Barat', 'Kota Solok'),
+  ('Sumatera Barat', 'Kota Sawahlunto'),
+  ('Sumatera Barat', 'Kota Padang Panjang'),
+  ('Sumatera Barat', 'Kota Bukittinggi'),
+  ('Sumatera Barat', 'Kota Payakumbuh'),
+  ('Sumatera Barat', 'Kota Pariaman'),
+  ('Riau', 'Kabupaten Kampar'),
+  ('Riau', 'Kabupaten Indragiri Hulu'),
+  ('Riau', 'Kabupaten Bengkalis'),
+  ('Riau', 'Kabupaten Indragiri Hilir'),
+  ('Riau', 'Kabupaten Pelalawan'),
+  ('Riau', 'Kabupaten Rokan Hulu'),
+  ('Riau', 'Kabupaten Rokan Hilir'),
+  ('Riau', 'Kabupaten Siak'),
+  ('Riau', 'Kabupaten Kuantan Singingi'),
+  ('Riau', 'Kabupaten Kepulauan Meranti'),
+  ('Riau', 'Kota Pekanbaru'),
+  ('Riau', 'Kota Dumai'),
+  ('Jambi', 'Kabupaten Kerinci'),
+  ('Jambi', 'Kabupaten Merangin'),
+  ('Jambi', 'Kabupaten Sarolangun'),
+  ('Jambi', 'Kabupaten Batanghari'),
+  ('Jambi', 'Kabupaten Muaro Jambi'),
+  ('Jambi', 'Kabupaten Tanjung Jabung Barat'),
+  ('Jambi', 'Kabupaten Tanjung Jabung Timur'),
+  ('Jambi', 'Kabupaten Bungo'),
+  ('Jambi', 'Kabupaten Tebo'),
+  ('Jambi', 'Kota Jambi'),
+  ('Jambi', 'Kota Sungai Penuh'),
+  ('Sumatera Selatan', 'Kabupaten Ogan Komering Ulu'),
+  ('Sumatera Selatan', 'Kabupaten Ogan Komering Ilir'),
+  ('Sumatera Selatan', 'Kabupaten Muara Enim'),
+  ('Sumatera Selatan', 'Kabupaten Lahat'),
+  ('Sumatera Selatan', 'Kabupaten Musi Rawas'),
+  ('Sumatera Selatan', 'Kabupaten Musi Banyuasin'),
+  ('Sumatera Selatan', 'Kabupaten Banyuasin'),
+  ('Sumatera Selatan', 'Kabupaten Ogan Komering Ulu Timur'),
+  ('Sumatera Selatan', 'Kabupaten Ogan Komering Ulu Selatan'),
+  ('Sumatera Selatan', 'Kabupaten Ogan Ilir'),
+  ('Sumatera Selatan', 'Kabupaten Empat Lawang'),
+  ('Sumatera Selatan', 'Kabupaten Penukal Abab Lematang Ilir'),
+  ('Sumatera Selatan', 'Kabupaten Musi Rawas Utara'),
+  ('Sumatera Selatan', 'Kota Palembang'),
+  ('Sumatera Selatan', 'Kota Prabumulih'),
+  ('Sumatera Selatan', 'Kota Lubuklinggau'),
+  ('Sumatera Selatan', 'Kota Pagar Alam'),
+  ('Bengkulu', 'Kabupaten Bengkulu Selatan'),
+  ('Bengkulu', 'Kabupaten Rejang Lebong'),
+  ('Bengkulu', 'Kabupaten Bengkulu Utara'),
+  ('Bengkulu', 'Kabupaten Kaur'),
+  ('Bengkulu', 'Kabupaten Seluma'),
+  ('Bengkulu', 'Kabupaten Mukomuko'),
+  ('Bengkulu', 'Kabupaten Lebong'),
+  ('Bengkulu', 'Kabupaten Kepahiang'),
+  ('Bengkulu', 'Kabupaten Bengkulu Tengah'),
+  ('Bengkulu', 'Kota Bengkulu'),
+  ('Lampung', 'Kabupaten Lampung Selatan'),
+  ('Lampung', 'Kabupaten Lampung Tengah'),
+  ('Lampung', 'Kabupaten Lampung Utara'),
+  ('Lampung', 'Kabupaten Lampung Barat'),
+  ('Lampung', 'Kabupaten Tulang Bawang'),
+  ('Lampung', 'Kabupaten Tanggamus'),
+  ('Lampung', 'Kabupaten Lampung Timur'),
+  ('Lampung', 'Kabupaten Way Kanan'),
+  ('Lampung', 'Kabupaten Pesawaran'),
+  ('Lampung', 'Kabupaten Pringsewu'),
+  ('Lampung', 'Kabupaten Mesuji'),
+  ('Lampung', 'Kabupaten Tulang Bawang Barat'),
+  ('Lampung', 'Kabupaten Pesisir Barat'),
+  ('Lampung', 'Kota Bandar Lampung'),
+  ('Lampung', 'Kota Metro'),
+  ('Kepulauan Bangka Belitung', 'Kabupaten Bangka'),
+  ('Kepulauan Bangka Belitung', 'Kabupaten Belitung'),
+  ('Kepulauan Bangka Belitung', 'Kabupaten Bangka Selatan'),
+  ('Kepulauan Bangka Belitung', 'Kabupaten Bangka Tengah'),
+  ('Kepulauan Bangka Belitung', 'Kabupaten Bangka Barat'),
+  ('Kepulauan Bangka Belitung', 'Kabupaten Belitung Timur'),
+  ('Kepulauan Bangka Belitung', 'Kota Pangkalpinang'),
+  ('Kepulauan Riau', 'Kabupaten Bintan'),
+  ('Kepulauan Riau', 'Kabupaten Karimun'),
+  ('Kepulauan Riau', 'Kabupaten Natuna'),
+  ('Kepulauan Riau', 'Kabupaten Lingga'),
+  ('Kepulauan Riau', 'Kabupaten Kepulauan Anambas'),
+  ('Kepulauan Riau', 'Kota Batam'),
+  ('Kepulauan Riau', 'Kota Tanjungpinang'),
+  ('DKI Jakarta', 'Kabupaten Kepulauan Seribu'),
+  ('DKI Jakarta', 'Kota Jakarta Selatan'),
+  ('DKI Jakarta', 'Kota Jakarta Timur'),
+  ('DKI Jakarta', 'Kota Jakarta Pusat'),
+  ('DKI Jakarta', 'Kota Jakarta Barat'),
+  ('DKI Jakarta', 'Kota Jakarta Utara'),
+  ('Jawa Barat', 'Kabupaten Bogor'),
+  ('Jawa Barat', 'Kabupaten Sukabumi'),
+  ('Jawa Barat', 'Kabupaten Cianjur'),
+  ('Jawa Barat', 'Kabupaten Bandung'),
+  ('Jawa Barat', 'Kabupaten Garut'),
+  ('Jawa Barat', 'Kabupaten Tasikmalaya'),
+  ('Jawa Barat', 'Kabupaten Ciamis'),
+  ('Jawa Barat', 'Kabupaten Kuningan'),
+  ('Jawa Barat', 'Kabupaten Cirebon'),
+  ('Jawa Barat', 'Kabupaten Majalengka'),
+  ('Jawa Barat', 'Kabupaten Sumedang'),
+  ('Jawa Barat', 'Kabupaten Indramayu'),
+  ('Jawa Barat', 'Kabupaten Subang'),
+  ('Jawa Barat', 'Kabupaten Purwakarta'),
+  ('Jawa Barat', 'Kabupaten Karawang'),
+  ('Jawa Barat', 'Kabupaten Bekasi'),
+  ('Jawa Barat', 'Kabupaten Bandung Barat'),
+  ('Jawa Barat', 'Kabupaten Pangandaran'),
+  ('Jawa Barat', 'Kota Bogor'),
+  ('Jawa Barat', 'Kota Sukabumi'),
+  ('Jawa Barat', 'Kota Bandung'),
+  ('Jawa Barat', 'Kota Cirebon'),
+  ('Jawa Barat', 'Kota Bekasi'),
+  ('Jawa Barat', 'Kota Depok'),
+  ('Jawa Barat', 'Kota Cimahi'),
+  ('Jawa Barat', 'Kota Tasikmalaya'),
+  ('Jawa Barat', 'Kota Banjar'),
+  ('Jawa Tengah', 'Kabupaten Cilacap'),
+  ('Jawa Tengah', 'Kabupaten Banyumas'),
+  ('Jawa Tengah', 'Kabupaten Purbalingga'),
+  ('Jawa Tengah', 'Kabupaten Banjarnegara'),
+  ('Jawa Tengah', 'Kabupaten Kebumen'),
+  ('Jawa Tengah', 'Kabupaten Purworejo'),
+  ('Jawa Tengah', 'Kabupaten Wonosobo'),
+  ('Jawa Tengah', 'Kabupaten Magelang'),
+  ('Jawa Tengah', 'Kabupaten Boyolali'),
+  ('Jawa Tengah', 'Kabupaten Klaten'),
+  ('Jawa Tengah', 'Kabupaten Sukoharjo'),
+  ('Jawa Tengah', 'Kabupaten Wonogiri'),
+  ('Jawa Tengah', 'Kabupaten Karanganyar'),
+  ('Jawa Tengah', 'Kabupaten Sragen'),
+  ('Jawa Tengah', 'Kabupaten Grobogan'),
+  ('Jawa Tengah', 'Kabupaten Blora'),
+  ('Jawa Tengah', 'Kabupaten Rembang'),
+  ('Jawa Tengah', 'Kabupaten Pati'),
+  ('Jawa Tengah', 'Kabupaten Kudus'),
+  ('Jawa Tengah', 'Kabupaten Jepara'),
+  ('Jawa Tengah', 'Kabupaten Demak'),
+  ('Jawa Tengah', 'Kabupaten Semarang'),
+  ('Jawa Tengah', 'Kabupaten Temanggung'),
+  ('Jawa Tengah', 'Kabupaten Kendal'),
+  ('Jawa Tengah', 'Kabupaten Batang'),
+  ('Jawa Tengah', 'Kabupaten Pekalongan'),
+  ('Jawa Tengah', 'Kabupaten Pemalang'),
+  ('Jawa Tengah', 'Kabupaten Tegal'),
+  ('Jawa Tengah', 'Kabupaten Brebes'),
+  ('Jawa Tengah', 'Kota Magelang'),
+  ('Jawa Tengah', 'Kota Surakarta'),
+  ('Jawa Tengah', 'Kota Salatiga'),
+  ('Jawa Tengah', 'Kota Semarang'),
+  ('Jawa Tengah', 'Kota Pekalongan'),
+  ('Jawa Tengah', 'Kota Tegal'),
+  ('DI Yogyakarta', 'Kabupaten Kulon Progo'),
+  ('DI Yogyakarta', 'Kabupaten Bantul'),
+  ('DI Yogyakarta', 'Kabupaten Gunungkidul'),
+  ('DI Yogyakarta', 'Kabupaten Sleman'),
+  ('DI Yogyakarta', 'Kota Yogyakarta'),
+  ('Jawa Timur', 'Kabupaten Pacitan'),
+  ('Jawa Timur', 'Kabupaten Ponorogo'),
+  ('Jawa Timur', 'Kabupaten Trenggalek'),
+  ('Jawa Timur', 'Kabupaten Tulungagung'),
+  ('Jawa Timur', 'Kabupaten Blitar'),
+  ('Jawa Timur', 'Kabupaten Kediri'),
+  ('Jawa Timur', 'Kabupaten Malang'),
+  ('Jawa Timur', 'Kabupaten Lumajang'),
+  ('Jawa Timur', 'Kabupaten Jember'),
+  ('Jawa Timur', 'Kabupaten Banyuwangi'),
+  ('Jawa Timur', 'Kabupaten Bondowoso'),
+  ('Jawa Timur', 'Kabupaten Situbondo'),
+  ('Jawa Timur', 'Kabupaten Probolinggo'),
+  ('Jawa Timur', 'Kabupaten Pasuruan'),
+  ('Jawa Timur', 'Kabupaten Sidoarjo'),
+  ('Jawa Timur', 'Kabupaten Mojokerto'),
+  ('Jawa Timur', 'Kabupaten Jombang'),
+  ('Jawa Timur', 'Kabupaten Nganjuk'),
+  ('Jawa Timur', 'Kabupaten Madiun'),
+  ('Jawa Timur', 'Kabupaten Magetan'),
+  ('Jawa Timur', 'Kabupaten Ngawi'),
+  ('Jawa Timur', 'Kabupaten Bojonegoro'),
+  ('Jawa Timur', 'Kabupaten Tuban'),
+  ('Jawa Timur', 'Kabupaten Lamongan'),
+  ('Jawa Timur', 'Kabupaten Gresik'),
+  ('Jawa Timur', 'Kabupaten Bangkalan'),
+  ('Jawa Timur', 'Kabupaten Sampang'),
+  ('Jawa Timur', 'Kabupaten Pamekasan'),
+  ('Jawa Timur', 'Kabupaten Sumenep'),
+  ('Jawa Timur', 'Kota Kediri'),
+  ('Jawa Timur', 'Kota Blitar'),
+  ('Jawa Timur', 'Kota Malang'),
+  ('Jawa Timur', 'Kota Probolinggo'),
+  ('Jawa Timur', 'Kota Pasuruan'),
+  ('Jawa Timur', 'Kota Mojokerto'),
+  ('Jawa Timur', 'Kota Madiun'),
+  ('Jawa Timur', 'Kota Surabaya'),
+  ('Jawa Timur', 'Kota Batu'),
+  ('Banten', 'Kabupaten Pandeglang'),
+  ('Banten', 'Kabupaten Lebak'),
+  ('Banten', 'Kabupaten Tangerang'),
+  ('Banten', 'Kabupaten Serang'),
+  ('Banten', 'Kota Tangerang'),
+  ('Banten', 'Kota Cilegon'),
+  ('Banten', 'Kota Serang'),
+  ('Banten', 'Kota Tangerang Selatan'),
+  ('Bali', 'Kabupaten Jembrana'),
+  ('Bali', 'Kabupaten Tabanan'),
+  ('Bali', 'Kabupaten Badung'),
+  ('Bali', 'Kabupaten Gianyar'),
+  ('Bali', 'Kabupaten Klungkung'),
+  ('Bali', 'Kabupaten Bangli'),
+  ('Bali', 'Kabupaten Karangasem'),
+  ('Bali', 'Kabupaten Buleleng'),
+  ('Bali', 'Kota Denpasar'),
+  ('Nusa Tenggara Barat', 'Kabupaten Lombok Barat'),
+  ('Nusa Tenggara Barat', 'Kabupaten Lombok Tengah'),
+  ('Nusa Tenggara Barat', 'Kabupaten Lombok Timur'),
+  ('Nusa Tenggara Barat', 'Kabupaten Sumbawa'),
+  ('Nusa Tenggara Barat', 'Kabupaten Dompu'),
+  ('Nusa Tenggara Barat', 'Kabupaten Bima'),
+  ('Nusa Tenggara Barat', 'Kabupaten Sumbawa Barat'),
+  ('Nusa Tenggara Barat', 'Kabupaten Lombok Utara'),
+  ('Nusa Tenggara Barat', 'Kota Mataram'),
+  ('Nusa Tenggara Barat', 'Kota Bima'),
+  ('Nusa Tenggara Timur', 'Kabupaten Kupang'),
+  ('Nusa Tenggara Timur', 'Kabupaten Timor Tengah Selatan'),
+  ('Nusa Tenggara Timur', 'Kabupaten Timor Tengah Utara'),
+  ('Nusa Tenggara Timur', 'Kabupaten Belu'),
+  ('Nusa Tenggara Timur', 'Kabupaten Alor'),
+  ('Nusa Tenggara Timur', 'Kabupaten Flores Timur'),
+  ('Nusa Tenggara Timur', 'Kabupaten Sikka'),
+  ('Nusa Tenggara Timur', 'Kabupaten Ende'),
+  ('Nusa Tenggara Timur', 'Kabupaten Ngada'),
+  ('Nusa Tenggara Timur', 'Kabupaten Manggarai'),
+  ('Nusa Tenggara Timur', 'Kabupaten Sumba Timur'),
+  ('Nusa Tenggara Timur', 'Kabupaten Sumba Barat'),
+  ('Nusa Tenggara Timur', 'Kabupaten Lembata'),
+  ('Nusa Tenggara Timur', 'Kabupaten Rote Ndao'),
+  ('Nusa Tenggara Timur', 'Kabupaten Manggarai Barat'),
+  ('Nusa Tenggara Timur', 'Kabupaten Nagekeo'),
+  ('Nusa Tenggara Timur', 'Kabupaten Sumba Tengah'),
+  ('Nusa Tenggara Timur', 'Kabupaten Sumba Barat Daya'),
+  ('Nusa Tenggara Timur', 'Kabupaten Manggarai Timur'),
+  ('Nusa Tenggara Timur', 'Kabupaten Sabu Raijua'),
+  ('Nusa Tenggara Timur', 'Kabupaten Malaka'),
+  ('Nusa Tenggara Timur', 'Kota Kupang'),
+  ('Kalimantan Barat', 'Kabupaten Sambas'),
+  ('Kalimantan Barat', 'Kabupaten Mempawah'),
+  ('Kalimantan Barat', 'Kabupaten Sanggau'),
+  ('Kalimantan Barat', 'Kabupaten Ketapang'),
+  ('Kalimantan Barat', 'Kabupaten Sintang'),
+  ('Kalimantan Barat', 'Kabupaten Kapuas Hulu'),
+  ('Kalimantan Barat', 'Kabupaten Bengkayang'),
+  ('Kalimantan Barat', 'Kabupaten Landak'),
+  ('Kalimantan Barat', 'Kabupaten Sekadau'),
+  ('Kalimantan Barat', 'Kabupaten Melawi'),
+  ('Kalimantan Barat', 'Kabupaten Kayong Utara'),
+  ('Kalimantan Barat', 'Kabupaten Kubu Raya'),
+  ('Kalimantan Barat', 'Kota Pontianak'),
+  ('Kalimantan Barat', 'Kota Singkawang'),
+  ('Kalimantan Tengah', 'Kabupaten Kotawaringin Barat'),
+  ('Kalimantan Tengah', 'Kabupaten Kotawaringin Timur'),
+  ('Kalimantan Tengah', 'Kabupaten Kapuas'),
+  ('Kalimantan Tengah', 'Kabupaten Barito Selatan'),
+  ('Kalimantan Tengah', 'Kabupaten Barito Utara'),
+  ('Kalimantan Tengah', 'Kabupaten Katingan'),
+  ('Kalimantan Tengah', 'Kabupaten Seruyan'),
+  ('Kalimantan Tengah', 'Kabupaten Sukamara'),
+  ('Kalimantan Tengah', 'Kabupaten Lamandau'),
+  ('Kalimantan Tengah', 'Kabupaten Gunung Mas'),
+  ('Kalimantan Tengah', 'Kabupaten Pulang Pisau'),
+  ('Kalimantan Tengah', 'Kabupaten Murung Raya'),
+  ('Kalimantan Tengah', 'Kabupaten Barito Timur'),
+  ('Kalimantan Tengah', 'Kota Palangka Raya'),
+  ('Kalimantan Selatan', 'Kabupaten Tanah Laut'),
+  ('Kalimantan Selatan', 'Kabupaten Kotabaru'),
+  ('Kalimantan Selatan', 'Kabupaten Banjar'),
+  ('Kalimantan Selatan', 'Kabupaten Barito Kuala'),
+  ('Kalimantan Selatan', 'Kabupaten Tapin'),
+  ('Kalimantan Selatan', 'Kabupaten Hulu Sungai Selatan'),
+  ('Kalimantan Selatan', 'Kabupaten Hulu Sungai Tengah'),
+  ('Kalimantan Selatan', 'Kabupaten Hulu Sungai Utara'),
+  ('Kalimantan Selatan', 'Kabupaten Tabalong'),
+  ('Kalimantan Selatan', 'Kabupaten Tanah Bumbu'),
+  ('Kalimantan Selatan', 'Kabupaten Balangan'),
+  ('Kalimantan Selatan', 'Kota Banjarmasin'),
+  ('Kalimantan Selatan', 'Kota Banjarbaru'),
+  ('Kalimantan Timur', 'Kabupaten Paser'),
+  ('Kalimantan Timur', 'Kabupaten Kutai Kartanegara'),
+  ('Kalimantan Timur', 'Kabupaten Berau'),
+  ('Kalimantan Timur', 'Kabupaten Kutai Barat'),
+  ('Kalimantan Timur', 'Kabupaten Kutai Timur'),
+  ('Kalimantan Timur', 'Kabupaten Penajam Paser Utara'),
+  ('Kalimantan Timur', 'Kabupaten Mahakam Ulu'),
+  ('Kalimantan Timur', 'Kota Balikpapan'),
+  ('Kalimantan Timur', 'Kota Samarinda'),
+  ('Kalimantan Timur', 'Kota Bontang'),
+  ('Kalimantan Utara', 'Kabupaten Bulungan'),
+  ('Kalimantan Utara', 'Kabupaten Malinau'),
+  ('Kalimantan Utara', 'Kabupaten Nunukan'),
+  ('Kalimantan Utara', 'Kabupaten Tana Tidung'),
+  ('Kalimantan Utara', 'Kota Tarakan'),
+  ('Sulawesi Utara', 'Kabupaten Bolaang Mongondow'),
+  ('Sulawesi Utara', 'Kabupaten Minahasa'),
+  ('Sulawesi Utara', 'Kabupaten Kepulauan Sangihe'),
+  ('Sulawesi Utara', 'Kabupaten Kepulauan Talaud'),
+  ('Sulawesi Utara', 'Kabupaten Minahasa Selatan'),
+  ('Sulawesi Utara', 'Kabupaten Minahasa Utara'),
+  ('Sulawesi Utara', 'Kabupaten Minahasa Tenggara'),
+  ('Sulawesi Utara', 'Kabupaten Bolaang Mongondow Utara'),
+  ('Sulawesi Utara', 'Kabupaten Kepulauan Siau Tagulandang Biaro'),
+  ('Sulawesi Utara', 'Kabupaten Bolaang Mongondow Timur'),
+  ('Sulawesi Utara', 'Kabupaten Bolaang Mongondow Selatan'),
+  ('Sulawesi Utara', 'Kota Manado'),
+  ('Sulawesi Utara', 'Kota Bitung'),
+  ('Sulawesi Utara', 'Kota Tomohon'),
+  ('Sulawesi Utara', 'Kota Kotamobagu'),
+  ('Sulawesi Tengah', 'Kabupaten Banggai'),
+  ('Sulawesi Tengah', 'Kabupaten Poso'),
+  ('Sulawesi Tengah', 'Kabupaten Donggala'),
+  ('Sulawesi Tengah', 'Kabupaten Toli-Toli'),
+  ('Sulawesi Tengah', 'Kabupaten Buol'),
+  ('Sulawesi Tengah', 'Kabupaten Morowali'),
+  ('Sulawesi Tengah', 'Kabupaten Banggai Kepulauan'),
+  ('Sulawesi Tengah', 'Kabupaten Parigi Moutong'),
+  ('Sulawesi Tengah', 'Kabupaten Tojo Una-Una'),
+  ('Sulawesi Tengah', 'Kabupaten Sigi'),
+  ('Sulawesi Tengah', 'Kabupaten Banggai Laut'),
+  ('Sulawesi Tengah', 'Kabupaten Morowali Utara'),
+  ('Sulawesi Tengah', 'Kota Palu'),
+  ('Sulawesi Selatan', 'Kabupaten Kepulauan Selayar'),
+  ('Sulawesi Selatan', 'Kabupaten Bulukumba'),
+  ('Sulawesi Selatan', 'Kabupaten Bantaeng'),
+  ('Sulawesi Selatan', 'Kabupaten Jeneponto'),
+  ('Sulawesi Selatan', 'Kabupaten Takalar'),
+  ('Sulawesi Selatan', 'Kabupaten Gowa'),
+  ('Sulawesi Selatan', 'Kabupaten Sinjai'),
+  ('Sulawesi Selatan', 'Kabupaten Bone'),
+  ('Sulawesi Selatan', 'Kabupaten Maros'),
+  ('Sulawesi Selatan', 'Kabupaten Pangkajene dan Kepulauan'),
+  ('Sulawesi Selatan', 'Kabupaten Barru'),
+  ('Sulawesi Selatan', 'Kabupaten Soppeng'),
+  ('Sulawesi Selatan', 'Kabupaten Wajo'),
+  ('Sulawesi Selatan', 'Kabupaten Sidenreng Rappang'),
+  ('Sulawesi Selatan', 'Kabupaten Pinrang'),
+  ('Sulawesi Selatan', 'Kabupaten Enrekang'),
+  ('Sulawesi Selatan', 'Kabupaten Luwu'),
+  ('Sulawesi Selatan', 'Kabupaten Tana Toraja'),
+  ('Sulawesi Selatan', 'Kabupaten Luwu Utara'),
+  ('Sulawesi Selatan', 'Kabupaten Luwu Timur'),
+  ('Sulawesi Selatan', 'Kabupaten Toraja Utara'),
+  ('Sulawesi Selatan', 'Kota Makassar'),
+  ('Sulawesi Selatan', 'Kota Parepare'),
+  ('Sulawesi Selatan', 'Kota Palopo'),
+  ('Sulawesi Tenggara', 'Kabupaten Kolaka'),
+  ('Sulawesi Tenggara', 'Kabupaten Konawe'),
+  ('Sulawesi Tenggara', 'Kabupaten Muna'),
+  ('Sulawesi Tenggara', 'Kabupaten Buton'),
+  ('Sulawesi Tenggara', 'Kabupaten Konawe Selatan'),
+  ('Sulawesi Tenggara', 'Kabupaten Bombana'),
+  ('Sulawesi Tenggara', 'Kabupaten Wakatobi'),
+  ('Sulawesi Tenggara', 'Kabupaten Kolaka Utara'),
+  ('Sulawesi Tenggara', 'Kabupaten Konawe Utara'),
+  ('Sulawesi Tenggara', 'Kabupaten Buton Utara'),
+  ('Sulawesi Tenggara', 'Kabupaten Kolaka Timur'),
+  ('Sulawesi Tenggara', 'Kabupaten Konawe Kepulauan'),
+  ('Sulawesi Tenggara', 'Kabupaten Muna Barat'),
+  ('Sulawesi Tenggara', 'Kabupaten Buton Tengah'),
+  ('Sulawesi Tenggara', 'Kabupaten Buton Selatan'),
+  ('Sulawesi Tenggara', 'Kota Kendari'),
+  ('Sulawesi Tenggara', 'Kota Baubau'),
+  ('Gorontalo', 'Kabupaten Gorontalo'),
+  ('Gorontalo', 'Kabupaten Boalemo'),
+  ('Gorontalo', 'Kabupaten Bone Bolango'),
+  ('Gorontalo', 'Kabupaten Pohuwato'),
+  ('Gorontalo', 'Kabupaten Gorontalo Utara'),
+  ('Gorontalo', 'Kota Gorontalo'),
+  ('Sulawesi Barat', 'Kabupaten Pasangkayu'),
+  ('Sulawesi Barat', 'Kabupaten Mamuju'),
+  ('Sulawesi Barat', 'Kabupaten Mamasa'),
+  ('Sulawesi Barat', 'Kabupaten Polewali Mandar'),
+  ('Sulawesi Barat', 'Kabupaten Majene'),
+  ('Sulawesi Barat', 'Kabupaten Mamuju Tengah'),
+  ('Maluku', 'Kabupaten Maluku Tengah'),
+  ('Maluku', 'Kabupaten Maluku Tenggara'),
+  ('Maluku', 'Kabupaten Kepulauan Tanimbar'),
+  ('Maluku', 'Kabupaten Buru'),
+  ('Maluku', 'Kabupaten Seram Bagian Timur'),
+  ('Maluku', 'Kabupaten Seram Bagian Barat'),
+  ('Maluku', 'Kabupaten Kepulauan Aru'),
+  ('Maluku', 'Kabupaten Maluku Barat Daya'),
+  ('Maluku', 'Kabupaten Buru Selatan'),
+  ('Maluku', 'Kota Ambon'),
+  ('Maluku', 'Kota Tual'),
+  ('Maluku Utara', 'Kabupaten Halmahera Barat'),
+  ('Maluku Utara', 'Kabupaten Halmahera Tengah'),
+  ('Maluku Utara', 'Kabupaten Halmahera Utara'),
+  ('Maluku Utara', 'Kabupaten Halmahera Selatan'),
+  ('Maluku Utara', 'Kabupaten Kepulauan Sula'),
+  ('Maluku Utara', 'Kabupaten Halmahera Timur'),
+  ('Maluku Utara', 'Kabupaten Pulau Morotai'),
+  ('Maluku Utara', 'Kabupaten Pulau Taliabu'),
+  ('Maluku Utara', 'Kota Ternate'),
+  ('Maluku Utara', 'Kota Tidore Kepulauan'),
+  ('Papua', 'Kabupaten Jayapura'),
+  ('Papua', 'Kabupaten Kepulauan Yapen'),
+  ('Papua', 'Kabupaten Biak Numfor'),
+  ('Papua', 'Kabupaten Sarmi'),
+  ('Papua', 'Kabupaten Keerom'),
+  ('Papua', 'Kabupaten Waropen'),
+  ('Papua', 'Kabupaten Supiori'),
+  ('Papua', 'Kabupaten Mamberamo Raya'),
+  ('Papua', 'Kota Jayapura'),
+  ('Papua Barat', 'Kabupaten Fakfak'),
+  ('Papua Barat', 'Kabupaten Kaimana'),
+  ('Papua Barat', 'Kabupaten Teluk Wondama'),
+  ('Papua Barat', 'Kabupaten Teluk Bintuni'),
+  ('Papua Barat', 'Kabupaten Manokwari'),
+  ('Papua Barat', 'Kabupaten Manokwari Selatan'),
+  ('Papua Barat', 'Kabupaten Pegunungan Arfak'),
+  ('Papua Selatan', 'Kabupaten Merauke'),
+  ('Papua Selatan', 'Kabupaten Boven Digoel'),
+  ('Papua Selatan', 'Kabupaten Mappi'),
+  ('Papua Selatan', 'Kabupaten Asmat'),
+  ('Papua Tengah', 'Kabupaten Nabire'),
+  ('Papua Tengah', 'Kabupaten Puncak Jaya'),
+  ('Papua Tengah', 'Kabupaten Paniai'),
+  ('Papua Tengah', 'Kabupaten Mimika'),
+  ('Papua Tengah', 'Kabupaten Puncak'),
+  ('Papua Tengah', 'Kabupaten Dogiyai'),
+  ('Papua Tengah', 'Kabupaten Intan Jaya'),
+  ('Papua Tengah', 'Kabupaten Deiyai'),
+  ('Papua Pegunungan', 'Kabupaten Jayawijaya'),
+  ('Papua Pegunungan', 'Kabupaten Pegunungan Bintang'),
+  ('Papua Pegunungan', 'Kabupaten Yahukimo'),
+  ('Papua Pegunungan', 'Kabupaten Tolikara'),
+  ('Papua Pegunungan', 'Kabupaten Mamberamo Tengah'),
+  ('Papua Pegunungan', 'Kabupaten Yalimo'),
+  ('Papua Pegunungan', 'Kabupaten Lanny Jaya'),
+  ('Papua Pegunungan', 'Kabupaten Nduga'),
+  ('Papua Barat Daya', 'Kabupaten Sorong'),
+  ('Papua Barat Daya', 'Kabupaten Sorong Selatan'),
+  ('Papua Barat Daya', 'Kabupaten Raja Ampat'),
+  ('Papua Barat Daya', 'Kabupaten Tambrauw'),
+  ('Papua Barat Daya', 'Kabupaten Maybrat'),
+  ('Papua Barat Daya', 'Kota Sorong')
+) AS "seed" ("province", "name")
+JOIN "provinces" ON "provinces"."name" = "seed"."province"
+ON CONFLICT ("name") DO UPDATE SET "province_id" = EXCLUDED."province_id";
+
+-- keep free text cities already stored on profiles so the foreign key holds,
+-- they have no province until an admin reassigns them
+INSERT INTO "cities" ("name")
+SELECT DISTINCT "city" FROM "profiles"
+WHERE "city" IS NOT NULL AND "city" NOT IN (SELECT "name" FROM "cities");
+
+ALTER TABLE "profiles" ADD CONSTRAINT "profiles_city_fkey"
+  FOREIGN KEY ("city") REFERENCES "cities" ("name") ON UPDATE CASCADE;
+CREATE INDEX ON "profiles" ("city");
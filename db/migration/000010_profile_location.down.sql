@@ -0,0 +1,3 @@
+ALTER TABLE "profiles" DROP CONSTRAINT "profiles_location_check";
+ALTER TABLE "profiles" DROP COLUMN "longitude";
+ALTER TABLE "profiles" DROP COLUMN "latitude";
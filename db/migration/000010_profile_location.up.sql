@@ -0,0 +1,9 @@
+ALTER TABLE "profiles" ADD COLUMN "latitude" DOUBLE PRECISION;
+ALTER TABLE "profiles" ADD COLUMN "longitude" DOUBLE PRECISION;
+
+ALTER TABLE "profiles" ADD CONSTRAINT "profiles_location_check" CHECK (
+  ("latitude" IS NULL AND "longitude" IS NULL) OR
+  ("latitude" BETWEEN -90 AND 90 AND "longitude" BETWEEN -180 AND 180)
+);
+
+CREATE INDEX ON "profiles" ("latitude", "longitude");
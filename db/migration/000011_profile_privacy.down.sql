@@ -0,0 +1,2 @@
+ALTER TABLE "profiles" DROP COLUMN "address_visibility";
+ALTER TABLE "profiles" DROP COLUMN "phone_visibility";
@@ -0,0 +1,7 @@
+ALTER TABLE "profiles" ADD COLUMN "phone_visibility" VARCHAR NOT NULL DEFAULT 'ACCEPTED_BUYERS';
+ALTER TABLE "profiles" ADD COLUMN "address_visibility" VARCHAR NOT NULL DEFAULT 'ACCEPTED_BUYERS';
+
+ALTER TABLE "profiles" ADD CONSTRAINT "profiles_phone_visibility_check"
+  CHECK ("phone_visibility" IN ('PUBLIC', 'ACCEPTED_BUYERS', 'NOBODY'));
+ALTER TABLE "profiles" ADD CONSTRAINT "profiles_address_visibility_check"
+  CHECK ("address_visibility" IN ('PUBLIC', 'ACCEPTED_BUYERS', 'NOBODY'));
@@ -0,0 +1,4 @@
+-- run with the keyring still configured values would stay encrypted, decrypt
+-- them first if the application is rolled back past this version
+DROP INDEX IF EXISTS "accounts_email_index_key";
+ALTER TABLE "accounts" DROP COLUMN "email_index";
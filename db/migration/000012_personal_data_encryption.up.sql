@@ -0,0 +1,5 @@
+-- email, profiles.phone_number and profiles.address hold "enc:<key id>:..."
+-- values written by the application. Rows stay readable as plaintext until
+-- "go run . reencrypt" has processed them.
+ALTER TABLE "accounts" ADD COLUMN "email_index" VARCHAR;
+CREATE UNIQUE INDEX "accounts_email_index_key" ON "accounts" ("email_index");
@@ -0,0 +1,3 @@
+-- deleted accounts keep their row so transactions still reference the
+-- anonymized profile, the email is replaced and cannot log in again
+ALTER TABLE "accounts" ADD COLUMN "deleted_at" timestamptz;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "account_status_changes";
+
+ALTER TABLE "accounts" DROP CONSTRAINT "accounts_suspended_until_check";
+ALTER TABLE "accounts" DROP CONSTRAINT "accounts_status_check";
+ALTER TABLE "accounts" DROP COLUMN "status_reason";
+ALTER TABLE "accounts" DROP COLUMN "suspended_until";
+ALTER TABLE "accounts" DROP COLUMN "status";
@@ -0,0 +1,20 @@
+ALTER TABLE "accounts" ADD COLUMN "status" VARCHAR NOT NULL DEFAULT 'ACTIVE';
+ALTER TABLE "accounts" ADD COLUMN "suspended_until" timestamptz;
+ALTER TABLE "accounts" ADD COLUMN "status_reason" VARCHAR;
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_status_check"
+  CHECK ("status" IN ('ACTIVE', 'SUSPENDED', 'BANNED'));
+ALTER TABLE "accounts" ADD CONSTRAINT "accounts_suspended_until_check"
+  CHECK (("status" = 'SUSPENDED') = ("suspended_until" IS NOT NULL));
+
+-- every change an admin makes, the current state lives on accounts
+CREATE TABLE "account_status_changes" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "account_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "status" VARCHAR NOT NULL,
+  "suspended_until" timestamptz,
+  "reason" VARCHAR NOT NULL DEFAULT '',
+  "admin_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "account_status_changes" ("account_id", "created_at");
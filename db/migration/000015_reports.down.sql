@@ -0,0 +1,3 @@
+ALTER TABLE "products" DROP COLUMN "hidden";
+
+DROP TABLE IF EXISTS "reports";
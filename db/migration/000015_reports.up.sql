@@ -0,0 +1,29 @@
+CREATE TABLE "reports" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "reporter_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "target_type" VARCHAR NOT NULL,
+  "target_id" uuid NOT NULL,
+  "reported_account_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "reason" VARCHAR NOT NULL,
+  "details" VARCHAR NOT NULL DEFAULT '',
+  "status" VARCHAR NOT NULL DEFAULT 'OPEN',
+  "action" VARCHAR,
+  "note" VARCHAR NOT NULL DEFAULT '',
+  "resolved_by" uuid REFERENCES "accounts" ("id"),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "due_at" timestamptz NOT NULL,
+  "resolved_at" timestamptz,
+  CONSTRAINT "reports_target_type_check" CHECK ("target_type" IN ('PRODUCT', 'PROFILE', 'TRANSACTION')),
+  CONSTRAINT "reports_reason_check"
+    CHECK ("reason" IN ('SCAM', 'PROHIBITED_ITEM', 'MISLEADING', 'HARASSMENT', 'SPAM', 'OTHER')),
+  CONSTRAINT "reports_status_check" CHECK ("status" IN ('OPEN', 'DISMISSED', 'ACTIONED')),
+  CONSTRAINT "reports_action_check" CHECK ("action" IN ('DISMISS', 'HIDE_LISTING', 'SUSPEND_USER'))
+);
+
+-- one open report per reporter and target, so the threshold counts people
+CREATE UNIQUE INDEX "reports_open_reporter_key" ON "reports" ("reporter_id", "target_type", "target_id") WHERE "status" = 'OPEN';
+CREATE INDEX ON "reports" ("status", "due_at");
+CREATE INDEX ON "reports" ("target_type", "target_id");
+
+-- set by moderators, a hidden listing cannot be published again by its owner
+ALTER TABLE "products" ADD COLUMN "hidden" BOOLEAN NOT NULL DEFAULT FALSE;
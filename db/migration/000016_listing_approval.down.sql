@@ -0,0 +1,9 @@
+ALTER TABLE "products" DROP CONSTRAINT "products_published_approved_check";
+ALTER TABLE "products" DROP CONSTRAINT "products_approval_status_check";
+ALTER TABLE "products" DROP COLUMN "reviewed_at";
+ALTER TABLE "products" DROP COLUMN "reviewed_by";
+ALTER TABLE "products" DROP COLUMN "submitted_at";
+ALTER TABLE "products" DROP COLUMN "rejection_reason";
+ALTER TABLE "products" DROP COLUMN "approval_status";
+
+ALTER TABLE "categories" DROP COLUMN "requires_approval";
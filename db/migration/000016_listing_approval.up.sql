@@ -0,0 +1,17 @@
+ALTER TABLE "categories" ADD COLUMN "requires_approval" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- listings in categories that require approval start as DRAFT, go to PENDING
+-- when the seller publishes and are published by an admin approving them.
+-- Existing listings count as approved.
+ALTER TABLE "products" ADD COLUMN "approval_status" VARCHAR NOT NULL DEFAULT 'APPROVED';
+ALTER TABLE "products" ADD COLUMN "rejection_reason" VARCHAR;
+ALTER TABLE "products" ADD COLUMN "submitted_at" timestamptz;
+ALTER TABLE "products" ADD COLUMN "reviewed_by" uuid REFERENCES "accounts" ("id");
+ALTER TABLE "products" ADD COLUMN "reviewed_at" timestamptz;
+
+ALTER TABLE "products" ADD CONSTRAINT "products_approval_status_check"
+  CHECK ("approval_status" IN ('DRAFT', 'PENDING', 'APPROVED', 'REJECTED'));
+ALTER TABLE "products" ADD CONSTRAINT "products_published_approved_check"
+  CHECK (NOT "published" OR "approval_status" = 'APPROVED');
+
+CREATE INDEX ON "products" ("submitted_at") WHERE "approval_status" = 'PENDING';
@@ -0,0 +1,4 @@
+DROP INDEX "transactions_open_offer_key";
+ALTER TABLE "transactions" DROP CONSTRAINT "transactions_price_offer_check";
+ALTER TABLE "transactions" ALTER COLUMN "deleted" DROP NOT NULL;
+ALTER TABLE "transactions" ALTER COLUMN "accepted" DROP NOT NULL;
@@ -0,0 +1,20 @@
+UPDATE "transactions" SET "accepted" = FALSE WHERE "accepted" IS NULL;
+UPDATE "transactions" SET "deleted" = FALSE WHERE "deleted" IS NULL;
+ALTER TABLE "transactions" ALTER COLUMN "accepted" SET NOT NULL;
+ALTER TABLE "transactions" ALTER COLUMN "deleted" SET NOT NULL;
+
+-- keep only the latest offer of each buyer on a product, an accepted one wins
+UPDATE "transactions" t SET "deleted" = TRUE, "updated_at" = now()
+FROM (
+  SELECT "id", row_number() OVER (
+    PARTITION BY "product_id", "buyer_id" ORDER BY "accepted" DESC, "updated_at" DESC
+  ) AS "n"
+  FROM "transactions"
+  WHERE "deleted" = FALSE
+) ranked
+WHERE ranked."id" = t."id" AND ranked."n" > 1;
+
+-- older offers with a price of zero or less are left as they are
+ALTER TABLE "transactions" ADD CONSTRAINT "transactions_price_offer_check" CHECK ("price_offer" > 0) NOT VALID;
+
+CREATE UNIQUE INDEX "transactions_open_offer_key" ON "transactions" ("product_id", "buyer_id") WHERE "deleted" = FALSE;
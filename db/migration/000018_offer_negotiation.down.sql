@@ -0,0 +1,2 @@
+DROP TABLE "offer_proposals";
+DROP FUNCTION "offer_proposals_immutable"();
@@ -0,0 +1,28 @@
+-- every price proposed on an offer, transactions.price_offer is the latest
+CREATE TABLE "offer_proposals" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "transaction_id" uuid NOT NULL REFERENCES "transactions" ("id"),
+  "proposed_by" uuid NOT NULL REFERENCES "profiles" ("id"),
+  "role" VARCHAR NOT NULL CHECK ("role" IN ('BUYER', 'SELLER')),
+  "amount" BIGINT NOT NULL CHECK ("amount" > 0),
+  "note" VARCHAR NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "offer_proposals" ("transaction_id", "created_at");
+
+CREATE FUNCTION "offer_proposals_immutable"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'offer proposals cannot be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "offer_proposals_immutable"
+  BEFORE UPDATE OR DELETE ON "offer_proposals"
+  FOR EACH ROW EXECUTE FUNCTION "offer_proposals_immutable"();
+
+-- the current price of existing offers is all that is known of them
+INSERT INTO "offer_proposals" ("transaction_id", "proposed_by", "role", "amount", "created_at")
+SELECT "id", "buyer_id", 'BUYER', "price_offer", "updated_at"
+FROM "transactions"
+WHERE "price_offer" > 0;
@@ -0,0 +1,8 @@
+DROP TABLE "notifications";
+DROP TABLE "job_runs";
+
+DROP INDEX "products_updated_at_idx";
+DROP INDEX "transactions_updated_at_idx";
+
+ALTER TABLE "transactions" DROP COLUMN "reminded_at";
+ALTER TABLE "transactions" DROP COLUMN "expired_at";
@@ -0,0 +1,28 @@
+-- expired offers are withdrawn like any other, expired_at tells them apart
+ALTER TABLE "transactions" ADD COLUMN "expired_at" timestamptz;
+ALTER TABLE "transactions" ADD COLUMN "reminded_at" timestamptz;
+
+CREATE INDEX ON "transactions" ("updated_at") WHERE "accepted" = FALSE AND "deleted" = FALSE;
+CREATE INDEX ON "products" ("updated_at") WHERE "published" = TRUE;
+
+CREATE TABLE "job_runs" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "job" VARCHAR NOT NULL,
+  "status" VARCHAR NOT NULL DEFAULT 'RUNNING' CHECK ("status" IN ('RUNNING', 'SUCCEEDED', 'FAILED')),
+  "affected" BIGINT NOT NULL DEFAULT 0,
+  "error" VARCHAR,
+  "started_at" timestamptz NOT NULL DEFAULT (now()),
+  "finished_at" timestamptz
+);
+
+CREATE INDEX ON "job_runs" ("started_at");
+
+CREATE TABLE "notifications" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "account_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "kind" VARCHAR NOT NULL,
+  "message" VARCHAR NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "notifications" ("account_id", "created_at");
@@ -0,0 +1,4 @@
+ALTER TABLE "products" DROP COLUMN "reserved_for";
+ALTER TABLE "products" DROP CONSTRAINT "products_pricing_check";
+ALTER TABLE "products" DROP COLUMN "auto_accept_price";
+ALTER TABLE "products" DROP COLUMN "min_price";
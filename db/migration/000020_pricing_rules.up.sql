@@ -0,0 +1,8 @@
+-- hidden seller prices: offers below min_price are declined and offers at or
+-- above auto_accept_price are accepted right away
+ALTER TABLE "products" ADD COLUMN "min_price" BIGINT CHECK ("min_price" > 0);
+ALTER TABLE "products" ADD COLUMN "auto_accept_price" BIGINT CHECK ("auto_accept_price" > 0);
+ALTER TABLE "products" ADD CONSTRAINT "products_pricing_check" CHECK ("auto_accept_price" >= "min_price");
+
+-- the accepted offer holding the product, other buyers cannot offer meanwhile
+ALTER TABLE "products" ADD COLUMN "reserved_for" uuid REFERENCES "transactions" ("id");
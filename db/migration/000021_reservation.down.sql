@@ -0,0 +1,3 @@
+DROP INDEX "products_reserved_until_idx";
+ALTER TABLE "products" DROP CONSTRAINT "products_reservation_check";
+ALTER TABLE "products" DROP COLUMN "reserved_until";
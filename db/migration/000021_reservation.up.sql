@@ -0,0 +1,10 @@
+-- a reserved product is held for the buyer of its accepted offer until
+-- reserved_until, then it is available again unless it was sold
+ALTER TABLE "products" ADD COLUMN "reserved_until" timestamptz;
+
+UPDATE "products" SET "reserved_until" = now() + interval '72 hours' WHERE "reserved_for" IS NOT NULL;
+
+ALTER TABLE "products" ADD CONSTRAINT "products_reservation_check"
+  CHECK (("reserved_for" IS NULL) = ("reserved_until" IS NULL));
+
+CREATE INDEX ON "products" ("reserved_until") WHERE "reserved_for" IS NOT NULL;
@@ -0,0 +1,37 @@
+-- an order follows an accepted offer until the product is handed over
+CREATE TABLE "orders" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "transaction_id" uuid NOT NULL REFERENCES "transactions" ("id"),
+  "product_id" uuid NOT NULL REFERENCES "products" ("id"),
+  "buyer_id" uuid NOT NULL REFERENCES "profiles" ("id"),
+  "seller_id" uuid NOT NULL REFERENCES "profiles" ("id"),
+  "price" BIGINT NOT NULL CHECK ("price" > 0),
+  "status" VARCHAR NOT NULL DEFAULT 'AWAITING_PAYMENT'
+    CHECK ("status" IN ('AWAITING_PAYMENT', 'PAID', 'SHIPPED', 'DELIVERED', 'COMPLETED', 'CANCELLED')),
+  "carrier" VARCHAR,
+  "tracking_number" VARCHAR,
+  "cancel_reason" VARCHAR,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "paid_at" timestamptz,
+  "shipped_at" timestamptz,
+  "delivered_at" timestamptz,
+  "completed_at" timestamptz,
+  "cancelled_at" timestamptz,
+  CONSTRAINT "orders_shipment_check" CHECK (("shipped_at" IS NULL) = ("tracking_number" IS NULL) AND ("carrier" IS NULL) = ("tracking_number" IS NULL))
+);
+
+-- an offer accepted again after its order was cancelled gets a new order
+CREATE UNIQUE INDEX "orders_open_transaction_key" ON "orders" ("transaction_id") WHERE "status" <> 'CANCELLED';
+CREATE INDEX ON "orders" ("buyer_id", "created_at");
+CREATE INDEX ON "orders" ("seller_id", "created_at");
+
+-- offers accepted so far were settled outside, only the unsold ones wait
+INSERT INTO "orders" ("transaction_id", "product_id", "buyer_id", "seller_id", "price", "status", "created_at", "completed_at")
+SELECT 
+  t."id", t."product_id", t."buyer_id", t."seller_id", t."price_offer",
+  CASE WHEN p."sold" THEN 'COMPLETED' ELSE 'AWAITING_PAYMENT' END,
+  t."updated_at", CASE WHEN p."sold" THEN p."updated_at" END
+FROM "transactions" t
+JOIN "products" p ON p."id" = t."product_id"
+WHERE t."accepted" = TRUE AND t."deleted" = FALSE AND t."price_offer" > 0;
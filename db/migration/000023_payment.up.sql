@@ -0,0 +1,34 @@
+-- in-app payments of orders. The money of a HELD payment is in escrow at the
+-- provider until it is RELEASED to the seller or REFUNDED to the buyer.
+CREATE TABLE "payments" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "transaction_id" uuid NOT NULL REFERENCES "transactions" ("id"),
+  "order_id" uuid NOT NULL REFERENCES "orders" ("id"),
+  "provider" VARCHAR NOT NULL,
+  "charge_id" VARCHAR NOT NULL,
+  "amount" BIGINT NOT NULL CHECK ("amount" > 0),
+  "status" VARCHAR NOT NULL DEFAULT 'PENDING'
+    CHECK ("status" IN ('PENDING', 'HELD', 'RELEASED', 'REFUNDED', 'FAILED', 'CANCELLED')),
+  "payment_url" VARCHAR NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "held_at" timestamptz,
+  "released_at" timestamptz,
+  "refunded_at" timestamptz,
+  CONSTRAINT "payments_charge_key" UNIQUE ("provider", "charge_id")
+);
+
+-- an order has at most one payment going on
+CREATE UNIQUE INDEX "payments_open_order_key" ON "payments" ("order_id") WHERE "status" IN ('PENDING', 'HELD');
+CREATE INDEX ON "payments" ("transaction_id");
+
+-- every webhook delivery handled, a provider delivering an event again finds
+-- it here and nothing happens twice
+CREATE TABLE "payment_events" (
+  "provider" VARCHAR NOT NULL,
+  "event_id" VARCHAR NOT NULL,
+  "type" VARCHAR NOT NULL,
+  "charge_id" VARCHAR NOT NULL,
+  "received_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("provider", "event_id")
+);
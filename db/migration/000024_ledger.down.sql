@@ -0,0 +1,9 @@
+DROP TABLE "payouts";
+DROP TABLE "postings";
+DROP FUNCTION "journal_entries_balanced"();
+DROP FUNCTION "postings_immutable"();
+DROP FUNCTION "postings_apply"();
+DROP TABLE "journal_entries";
+DROP TABLE "ledger_accounts";
+ALTER TABLE "payments" DROP CONSTRAINT "payments_fee_check";
+ALTER TABLE "payments" DROP COLUMN "fee";
@@ -0,0 +1,114 @@
+-- the platform's fee on a sale, fixed when the buyer pays
+ALTER TABLE "payments" ADD COLUMN "fee" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "payments" ADD CONSTRAINT "payments_fee_check" CHECK ("fee" >= 0 AND "fee" <= "amount");
+
+-- double-entry ledger. A positive posting credits its account, the postings
+-- of a journal entry add up to zero and balances never go below zero except
+-- for the GATEWAY account, the money at the payment provider.
+CREATE TABLE "ledger_accounts" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "kind" VARCHAR NOT NULL CHECK ("kind" IN ('GATEWAY', 'ESCROW', 'FEES', 'PAYOUTS_PENDING', 'SELLER_AVAILABLE')),
+  "owner_id" uuid REFERENCES "accounts" ("id"),
+  "balance" BIGINT NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "ledger_accounts_owner_check" CHECK (("owner_id" IS NULL) = ("kind" <> 'SELLER_AVAILABLE')),
+  CONSTRAINT "ledger_accounts_balance_check" CHECK ("kind" = 'GATEWAY' OR "balance" >= 0)
+);
+
+CREATE UNIQUE INDEX "ledger_accounts_kind_owner_key"
+  ON "ledger_accounts" ("kind", COALESCE("owner_id", '00000000-0000-0000-0000-000000000000'));
+
+INSERT INTO "ledger_accounts" ("kind") VALUES ('GATEWAY'), ('ESCROW'), ('FEES'), ('PAYOUTS_PENDING');
+
+-- reference_id is the payment or payout an entry records, so each is
+-- recorded once
+CREATE TABLE "journal_entries" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "kind" VARCHAR NOT NULL
+    CHECK ("kind" IN ('PAYMENT_HELD', 'SALE_RELEASED', 'PAYMENT_REFUNDED', 'PAYOUT_REQUESTED', 'PAYOUT_APPROVED', 'PAYOUT_REJECTED')),
+  "reference_id" uuid NOT NULL,
+  "description" VARCHAR NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "journal_entries_reference_key" UNIQUE ("kind", "reference_id")
+);
+
+CREATE TABLE "postings" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "entry_id" uuid NOT NULL REFERENCES "journal_entries" ("id"),
+  "account_id" uuid NOT NULL REFERENCES "ledger_accounts" ("id"),
+  "amount" BIGINT NOT NULL CHECK ("amount" <> 0),
+  "balance_after" BIGINT NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "postings" ("account_id", "created_at");
+CREATE INDEX ON "postings" ("entry_id");
+
+-- every posting moves the balance of its account, the balance check of the
+-- account refuses an overdraft right away
+CREATE FUNCTION "postings_apply"() RETURNS trigger AS $$
+BEGIN
+  UPDATE "ledger_accounts" SET "balance" = "balance" + NEW."amount"
+  WHERE "id" = NEW."account_id"
+  RETURNING "balance" INTO NEW."balance_after";
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "postings_apply"
+  BEFORE INSERT ON "postings"
+  FOR EACH ROW EXECUTE FUNCTION "postings_apply"();
+
+CREATE FUNCTION "postings_immutable"() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'postings cannot be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "postings_immutable"
+  BEFORE UPDATE OR DELETE ON "postings"
+  FOR EACH ROW EXECUTE FUNCTION "postings_immutable"();
+
+-- checked at commit, once every posting of the entry is in
+CREATE FUNCTION "journal_entries_balanced"() RETURNS trigger AS $$
+BEGIN
+  IF (SELECT COALESCE(SUM("amount"), 0) FROM "postings" WHERE "entry_id" = NEW."entry_id") <> 0 THEN
+    RAISE EXCEPTION 'journal entry % does not balance', NEW."entry_id";
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER "journal_entries_balanced"
+  AFTER INSERT ON "postings"
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION "journal_entries_balanced"();
+
+CREATE TABLE "payouts" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "seller_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "amount" BIGINT NOT NULL CHECK ("amount" > 0),
+  "destination" VARCHAR NOT NULL,
+  "status" VARCHAR NOT NULL DEFAULT 'REQUESTED' CHECK ("status" IN ('REQUESTED', 'APPROVED', 'REJECTED')),
+  "reason" VARCHAR,
+  "reviewed_by" uuid REFERENCES "accounts" ("id"),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "reviewed_at" timestamptz
+);
+
+CREATE INDEX ON "payouts" ("seller_id", "created_at");
+CREATE INDEX ON "payouts" ("created_at") WHERE "status" = 'REQUESTED';
+
+-- payments held before the ledger existed are in escrow already, so their
+-- release or refund finds the money there
+INSERT INTO "journal_entries" ("kind", "reference_id", "description", "created_at")
+SELECT 'PAYMENT_HELD', "id", 'payment of order ' || "order_id" || ' held in escrow', COALESCE("held_at", "updated_at")
+FROM "payments"
+WHERE "status" = 'HELD';
+
+INSERT INTO "postings" ("entry_id", "account_id", "amount", "balance_after", "created_at")
+SELECT e."id", a."id", CASE WHEN a."kind" = 'GATEWAY' THEN -p."amount" ELSE p."amount" END, 0, e."created_at"
+FROM "journal_entries" e
+JOIN "payments" p ON p."id" = e."reference_id"
+JOIN "ledger_accounts" a ON a."kind" IN ('GATEWAY', 'ESCROW')
+WHERE e."kind" = 'PAYMENT_HELD';
@@ -0,0 +1,4 @@
+ALTER TABLE "payments" DROP CONSTRAINT "payments_refunded_amount_check";
+ALTER TABLE "payments" DROP COLUMN "refunded_amount";
+DROP TABLE "dispute_messages";
+DROP TABLE "disputes";
@@ -0,0 +1,60 @@
+-- a buyer disputes a paid order whose item did not arrive as described. The
+-- awaited party has to answer before respond_by or the dispute is escalated
+-- to an admin, who resolves it with a refund, a partial refund or a reject.
+CREATE TABLE "disputes" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "transaction_id" uuid NOT NULL REFERENCES "transactions" ("id"),
+  "order_id" uuid NOT NULL REFERENCES "orders" ("id"),
+  "product_id" uuid NOT NULL REFERENCES "products" ("id"),
+  "buyer_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "seller_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "reason" VARCHAR NOT NULL,
+  "details" VARCHAR NOT NULL,
+  "status" VARCHAR NOT NULL DEFAULT 'OPEN',
+  "awaiting" VARCHAR,
+  "respond_by" timestamptz,
+  "resolution" VARCHAR,
+  "refund_amount" BIGINT,
+  "resolution_note" VARCHAR NOT NULL DEFAULT '',
+  "resolved_by" uuid REFERENCES "accounts" ("id"),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "escalated_at" timestamptz,
+  "resolved_at" timestamptz,
+  CONSTRAINT "disputes_reason_check" CHECK ("reason" IN ('NOT_RECEIVED', 'NOT_AS_DESCRIBED', 'DAMAGED', 'OTHER')),
+  CONSTRAINT "disputes_status_check" CHECK ("status" IN ('OPEN', 'ESCALATED', 'RESOLVED')),
+  CONSTRAINT "disputes_awaiting_check" CHECK ("awaiting" IN ('BUYER', 'SELLER')),
+  CONSTRAINT "disputes_resolution_check" CHECK ("resolution" IN ('REFUND', 'PARTIAL_REFUND', 'REJECT')),
+  CONSTRAINT "disputes_refund_amount_check" CHECK ("refund_amount" > 0),
+  -- only an open dispute waits for a party, until a deadline
+  CONSTRAINT "disputes_deadline_check"
+    CHECK (("status" = 'OPEN') = ("awaiting" IS NOT NULL) AND ("awaiting" IS NULL) = ("respond_by" IS NULL)),
+  CONSTRAINT "disputes_resolved_check"
+    CHECK (("status" = 'RESOLVED') = ("resolution" IS NOT NULL)
+      AND ("resolution" IS NOT DISTINCT FROM 'PARTIAL_REFUND') = ("refund_amount" IS NOT NULL))
+);
+
+-- one unresolved dispute per offer, it freezes the product, offer and order
+CREATE UNIQUE INDEX "disputes_open_transaction_key" ON "disputes" ("transaction_id") WHERE "status" <> 'RESOLVED';
+CREATE INDEX ON "disputes" ("product_id") WHERE "status" <> 'RESOLVED';
+CREATE INDEX ON "disputes" ("respond_by") WHERE "status" = 'OPEN';
+CREATE INDEX ON "disputes" ("buyer_id");
+CREATE INDEX ON "disputes" ("seller_id");
+
+-- the message trail of a dispute, evidence is an uploaded image
+CREATE TABLE "dispute_messages" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "dispute_id" uuid NOT NULL REFERENCES "disputes" ("id"),
+  "author_id" uuid NOT NULL REFERENCES "accounts" ("id"),
+  "body" VARCHAR NOT NULL DEFAULT '',
+  "image_url" VARCHAR,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  CONSTRAINT "dispute_messages_content_check" CHECK ("body" <> '' OR "image_url" IS NOT NULL)
+);
+
+CREATE INDEX ON "dispute_messages" ("dispute_id", "created_at");
+
+-- a partial refund captures less than the buyer paid, the rest goes back
+ALTER TABLE "payments" ADD COLUMN "refunded_amount" BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE "payments" ADD CONSTRAINT "payments_refunded_amount_check"
+  CHECK ("refunded_amount" >= 0 AND "refunded_amount" < "amount");
@@ -0,0 +1,40 @@
+-- a seller can auction a published product instead of taking offers. A bid
+-- has to beat the high bid by min_increment, a bid close to ends_at pushes it
+-- back, and an ended auction whose high bid reaches the hidden reserve price
+-- becomes an accepted offer of the high bidder.
+CREATE TABLE "auctions" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "product_id" uuid NOT NULL REFERENCES "products" ("id"),
+  "seller_id" uuid NOT NULL REFERENCES "profiles" ("id"),
+  "start_price" BIGINT NOT NULL CHECK ("start_price" > 0),
+  "reserve_price" BIGINT,
+  "min_increment" BIGINT NOT NULL CHECK ("min_increment" > 0),
+  "status" VARCHAR NOT NULL DEFAULT 'OPEN',
+  "high_bid" BIGINT,
+  "high_bidder_id" uuid REFERENCES "profiles" ("id"),
+  "bid_count" INT NOT NULL DEFAULT 0,
+  "ends_at" timestamptz NOT NULL,
+  "transaction_id" uuid REFERENCES "transactions" ("id"),
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "closed_at" timestamptz,
+  CONSTRAINT "auctions_status_check" CHECK ("status" IN ('OPEN', 'SOLD', 'UNSOLD', 'CANCELLED')),
+  CONSTRAINT "auctions_reserve_price_check" CHECK ("reserve_price" >= "start_price"),
+  CONSTRAINT "auctions_high_bid_check"
+    CHECK (("high_bid" IS NULL) = ("high_bidder_id" IS NULL) AND "high_bid" >= "start_price"),
+  CONSTRAINT "auctions_sold_check" CHECK (("status" = 'SOLD') = ("transaction_id" IS NOT NULL))
+);
+
+-- one running auction per product, no offers are taken on it meanwhile
+CREATE UNIQUE INDEX "auctions_open_product_key" ON "auctions" ("product_id") WHERE "status" = 'OPEN';
+CREATE INDEX ON "auctions" ("ends_at") WHERE "status" = 'OPEN';
+
+CREATE TABLE "bids" (
+  "id" uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+  "auction_id" uuid NOT NULL REFERENCES "auctions" ("id"),
+  "bidder_id" uuid NOT NULL REFERENCES "profiles" ("id"),
+  "amount" BIGINT NOT NULL CHECK ("amount" > 0),
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "bids" ("auction_id", "created_at");
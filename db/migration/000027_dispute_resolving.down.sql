@@ -0,0 +1,3 @@
+UPDATE "disputes" SET "status" = 'ESCALATED' WHERE "status" = 'RESOLVING';
+ALTER TABLE "disputes" DROP CONSTRAINT "disputes_status_check";
+ALTER TABLE "disputes" ADD CONSTRAINT "disputes_status_check" CHECK ("status" IN ('OPEN', 'ESCALATED', 'RESOLVED'));
@@ -0,0 +1,5 @@
+-- an admin claims a dispute as RESOLVING before the payment provider refunds
+-- or captures, so two admins cannot both move the money
+ALTER TABLE "disputes" DROP CONSTRAINT "disputes_status_check";
+ALTER TABLE "disputes" ADD CONSTRAINT "disputes_status_check"
+  CHECK ("status" IN ('OPEN', 'ESCALATED', 'RESOLVING', 'RESOLVED'));
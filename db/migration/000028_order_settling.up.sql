@@ -0,0 +1,3 @@
+-- the status an order is claimed for while the payment provider captures or
+-- refunds its money, so two requests cannot both move the money
+ALTER TABLE "orders" ADD COLUMN "settling" VARCHAR;
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/RuhullahReza/SecondHand/util/config"
+	"github.com/XSAM/otelsql"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
 
@@ -23,11 +25,19 @@ func NewPostgresConnection() *sqlx.DB {
 		config.DatabaseMode(),
 	)
 
-	db, err := sqlx.Connect("postgres", dbConfig)
+	sqlDB, err := otelsql.Open("postgres", dbConfig,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true}),
+	)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
+	db := sqlx.NewDb(sqlDB, "postgres")
+	if err := db.Ping(); err != nil {
+		log.Fatalln(err)
+	}
+
 	db.SetMaxIdleConns(5)
 	db.SetMaxOpenConns(20)
 	db.SetConnMaxLifetime(60 * time.Minute)
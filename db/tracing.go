@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/RuhullahReza/SecondHand/util/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+// NewTracerProvider registers the global tracer provider. TRACING_EXPORTER
+// selects "otlp" or "stdout", anything else keeps spans in process only.
+func NewTracerProvider() *sdktrace.TracerProvider {
+
+	res := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.TracingServiceName()),
+	)
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+	}
+
+	switch config.TracingExporter() {
+	case "otlp":
+		clientOptions := []otlptracehttp.Option{}
+		if endpoint := config.TracingEndpoint(); endpoint != "" {
+			clientOptions = append(clientOptions, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if config.TracingInsecure() {
+			clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(context.Background(), clientOptions...)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case "stdout":
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			log.Fatalln(err)
+		}
+		options = append(options, sdktrace.WithSyncer(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(options...)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp
+}
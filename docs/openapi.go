@@ -0,0 +1,441 @@
+package docs
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+)
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	In          string `json:"in"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type PathItem map[string]*OperationObject
+
+type OperationObject struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary"`
+	Description string                `json:"description,omitempty"`
+	OperationId string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Required    bool    `json:"required"`
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+type Access int
+
+const (
+	Public Access = iota
+	User
+	Admin
+)
+
+// Query documents a query string parameter
+type Query struct {
+	Name        string
+	Type        string
+	Description string
+}
+
+// Route describes one endpoint registered in Inject. Request and Data take a
+// zero value of the web model so the schema follows the struct definition.
+type Route struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+	Access      Access
+	Request     interface{}
+	Upload      string
+	Query       []Query
+	Data        interface{}
+	DataKey     string
+	Status      []int
+	Raw         bool
+	Paged       bool
+	Unversioned bool
+}
+
+// V1Prefix is where the versioned copy of every non Unversioned route lives
+const V1Prefix = "/api/v1"
+
+var pathParam = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+// OpenAPIPath converts a gin route path such as /product/id/:id into the
+// OpenAPI form /product/id/{id}
+func OpenAPIPath(path string) string {
+	return pathParam.ReplaceAllString(path, "{$1}")
+}
+
+// Build assembles the OpenAPI document for the given routes
+func Build(routes []Route) *Document {
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "SecondHand API",
+			Version:     "1.0.0",
+			Description: "Marketplace API for buying and selling second hand goods.",
+		},
+		Paths: map[string]*PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+			SecuritySchemes: map[string]*SecurityScheme{
+				"cookieAuth": {
+					Type:        "apiKey",
+					In:          "cookie",
+					Name:        "token",
+					Description: "JWT returned by POST /login",
+				},
+			},
+		},
+	}
+
+	doc.Components.Schemas["Error"] = doc.structSchema(reflect.TypeOf(helper.Error{}))
+	doc.Components.Schemas["Error"].Properties["type"].Enum = []string{
+		string(helper.Authorization), string(helper.BadRequest), string(helper.Conflict), string(helper.Forbidden),
+		string(helper.Internal), string(helper.NotFound), string(helper.PayloadTooLarge),
+		string(helper.ServiceUnavailable), string(helper.UnsupportedMediaType),
+	}
+	doc.Components.Schemas["ErrorResponse"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"error": {Ref: "#/components/schemas/Error"},
+			"invalidArgs": {
+				Type:        "array",
+				Items:       &Schema{Type: "string"},
+				Description: "translated validation messages, present only on binding failures",
+			},
+		},
+		Required: []string{"error"},
+	}
+	doc.Components.Schemas["V1ErrorResponse"] = doc.structSchema(reflect.TypeOf(helper.Envelope{}))
+	delete(doc.Components.Schemas["V1ErrorResponse"].Properties, "data")
+	delete(doc.Components.Schemas["V1ErrorResponse"].Properties, "meta")
+	doc.Components.Schemas["ErrorDetail"].Properties["code"].Enum = doc.Components.Schemas["Error"].Properties["type"].Enum
+	doc.Components.Schemas["MessageResponse"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"Status":  {Type: "string"},
+			"Message": {Type: "string"},
+		},
+	}
+
+	for _, route := range routes {
+		if route.Unversioned {
+			doc.add(route.Path, route, doc.operation(route, false))
+			continue
+		}
+
+		legacy := doc.operation(route, false)
+		legacy.Deprecated = true
+		legacy.Description = strings.TrimSpace(legacy.Description + " Deprecated, use " + V1Prefix + OpenAPIPath(route.Path) + ".")
+		doc.add(route.Path, route, legacy)
+
+		doc.add(V1Prefix+route.Path, route, doc.operation(route, true))
+	}
+
+	return doc
+}
+
+func (d *Document) add(path string, route Route, op *OperationObject) {
+
+	path = OpenAPIPath(path)
+	if strings.HasPrefix(path, V1Prefix) {
+		op.OperationId = "v1" + strings.ToUpper(op.OperationId[:1]) + op.OperationId[1:]
+	}
+
+	item, ok := d.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		d.Paths[path] = item
+	}
+
+	(*item)[strings.ToLower(route.Method)] = op
+}
+
+func (d *Document) operation(route Route, v1 bool) *OperationObject {
+
+	op := &OperationObject{
+		Tags:        []string{route.Tag},
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationId: operationId(route),
+		Responses:   map[string]*Response{},
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	if route.Paged {
+		route.Query = append(route.Query,
+			Query{Name: "page", Type: "integer", Description: "1-based page number, defaults to 1"},
+			Query{Name: "per_page", Type: "integer", Description: "page size, defaults to 50 and is capped at 100"},
+		)
+	}
+
+	for _, query := range route.Query {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        query.Name,
+			In:          "query",
+			Description: query.Description,
+			Schema:      &Schema{Type: query.Type},
+		})
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"application/json": {Schema: d.schemaFor(reflect.TypeOf(route.Request))},
+			},
+		}
+		op.Responses["400"] = d.errorResponse(v1, "request body failed validation, see invalidArgs")
+		op.Responses["415"] = d.errorResponse(v1, "Content-Type is not application/json")
+	}
+
+	if route.Upload != "" {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]*MediaType{
+				"multipart/form-data": {Schema: &Schema{
+					Type: "object",
+					Properties: map[string]*Schema{
+						route.Upload: {Type: "string", Format: "binary"},
+					},
+					Required: []string{route.Upload},
+				}},
+			},
+		}
+		op.Responses["400"] = d.errorResponse(v1, "unable to parse multipart/form-data")
+		op.Responses["413"] = d.errorResponse(v1, "file exceeds 4 MB")
+	}
+
+	switch route.Access {
+	case User:
+		op.Security = []map[string][]string{{"cookieAuth": {}}}
+		op.Responses["401"] = d.errorResponse(v1, "missing or invalid token")
+		op.Responses["403"] = d.errorResponse(v1, "account suspended or banned")
+	case Admin:
+		op.Security = []map[string][]string{{"cookieAuth": {}}}
+		op.Description = strings.TrimSpace(op.Description + " Requires the ADMIN role.")
+		op.Responses["401"] = d.errorResponse(v1, "missing token, invalid token or not an admin")
+		op.Responses["403"] = d.errorResponse(v1, "account suspended or banned")
+	}
+
+	if v1 {
+		op.Responses["200"] = d.envelopeResponse(route)
+	} else {
+		op.Responses["200"] = d.successResponse(route)
+	}
+
+	for _, status := range route.Status {
+		op.Responses[fmt.Sprint(status)] = d.errorResponse(v1, http.StatusText(status))
+	}
+
+	if !route.Raw || v1 {
+		op.Responses["500"] = d.errorResponse(v1, "unexpected failure")
+	}
+
+	return op
+}
+
+func (d *Document) successResponse(route Route) *Response {
+
+	if route.Raw {
+		schema := &Schema{Type: "object"}
+		if route.Data != nil {
+			schema = d.schemaFor(reflect.TypeOf(route.Data))
+		}
+		return &Response{
+			Description: "OK",
+			Content:     map[string]*MediaType{"application/json": {Schema: schema}},
+		}
+	}
+
+	if route.Data == nil {
+		return &Response{
+			Description: "OK",
+			Content: map[string]*MediaType{
+				"application/json": {Schema: &Schema{Ref: "#/components/schemas/MessageResponse"}},
+			},
+		}
+	}
+
+	key := route.DataKey
+	if key == "" {
+		key = "Data"
+	}
+
+	return &Response{
+		Description: "OK",
+		Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"Status": {Type: "string"},
+					key:      d.schemaFor(reflect.TypeOf(route.Data)),
+				},
+			}},
+		},
+	}
+}
+
+// envelopeResponse is the /api/v1 success body: data, plus meta on paged lists
+func (d *Document) envelopeResponse(route Route) *Response {
+
+	data := d.schemaFor(reflect.TypeOf(helper.MessageData{}))
+	if route.Data != nil {
+		data = d.schemaFor(reflect.TypeOf(route.Data))
+	}
+
+	schema := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"data": data},
+		Required:   []string{"data"},
+	}
+	if route.Paged {
+		schema.Properties["meta"] = d.schemaFor(reflect.TypeOf(helper.Meta{}))
+		schema.Required = append(schema.Required, "meta")
+	}
+
+	return &Response{
+		Description: "OK",
+		Content:     map[string]*MediaType{"application/json": {Schema: schema}},
+	}
+}
+
+func (d *Document) errorResponse(v1 bool, description string) *Response {
+
+	schema := "ErrorResponse"
+	if v1 {
+		schema = "V1ErrorResponse"
+	}
+
+	return &Response{
+		Description: description,
+		Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{Ref: "#/components/schemas/" + schema}},
+		},
+	}
+}
+
+func operationId(route Route) string {
+
+	parts := []string{strings.ToLower(route.Method)}
+	for _, segment := range strings.Split(route.Path, "/") {
+		segment = strings.TrimPrefix(segment, ":")
+		segment = strings.NewReplacer("-", "", "_", "", ".", "").Replace(segment)
+		if segment == "" {
+			continue
+		}
+		parts = append(parts, strings.ToUpper(segment[:1])+segment[1:])
+	}
+
+	return strings.Join(parts, "")
+}
+
+// Undocumented returns the "METHOD path" pairs that are served but missing
+// from the document, and the documented ones that are no longer served
+func (d *Document) Undocumented(served map[string]bool) (missing []string, stale []string) {
+
+	documented := map[string]bool{}
+	for path, item := range d.Paths {
+		for method := range *item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for key := range served {
+		if !documented[key] {
+			missing = append(missing, key)
+		}
+	}
+	for key := range documented {
+		if !served[key] {
+			stale = append(stale, key)
+		}
+	}
+
+	sort.Strings(missing)
+	sort.Strings(stale)
+
+	return missing, stale
+}
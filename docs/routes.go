@@ -0,0 +1,478 @@
+package docs
+
+import (
+	"net/http"
+
+	"github.com/RuhullahReza/SecondHand/model/web"
+)
+
+// Routes documents every endpoint registered in Inject. TestOpenAPICoversRoutes
+// fails when the two drift apart.
+var Routes = []Route{
+	{Method: http.MethodGet, Path: "/healthz", Tag: "Health", Summary: "Liveness probe", Unversioned: true},
+	{
+		Method: http.MethodGet, Path: "/readyz", Tag: "Health", Summary: "Readiness probe",
+		Description: "Checks Postgres, image storage and the migration version. Responds 503 with the same body when any check fails.",
+		Data:        web.ReadinessResponse{}, Unversioned: true,
+	},
+	{Method: http.MethodGet, Path: "/openapi.json", Tag: "Docs", Summary: "This OpenAPI document", Raw: true, Unversioned: true},
+	{Method: http.MethodGet, Path: "/docs", Tag: "Docs", Summary: "Swagger UI", Raw: true, Unversioned: true},
+
+	{
+		Method: http.MethodPost, Path: "/register", Tag: "User", Summary: "Register a user account",
+		Request: web.CreateUserRequest{}, Status: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/admin/register", Tag: "User", Summary: "Register an admin account",
+		Request: web.CreateUserRequest{}, Status: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/login", Tag: "User", Summary: "Log in",
+		Description: "Sets the token cookie used by authenticated endpoints.",
+		Request:     web.LoginRequest{}, Data: web.LoginResponse{}, Raw: true,
+		Status: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/profile", Tag: "User", Summary: "Get my profile", Access: User,
+		Data: web.GetProfileResponse{}, DataKey: "Message", Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/profile/:id", Tag: "User", Summary: "Get a profile by account id",
+		Description: "latitude and longitude are rounded to two decimals. The phone number is masked and the address emptied " +
+			"unless the owner's privacy settings allow the viewer, identified by the optional token cookie, to see them.",
+		Data: web.GetProfileResponse{}, DataKey: "Message", Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/profile", Tag: "User", Summary: "Update my profile", Access: User,
+		Description: "city is a regency or city id or name and must belong to a province. latitude and longitude are optional but go together.",
+		Request:     web.UpdateProfileRequest{}, Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/profile/privacy", Tag: "User", Summary: "Update who can see my phone number and address", Access: User,
+		Description: "PUBLIC shows them to everyone, ACCEPTED_BUYERS only to counterparties of an accepted offer, NOBODY hides them. " +
+			"Admins always see them.",
+		Request: web.PrivacySettings{}, Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/profile_image", Tag: "User", Summary: "Replace my profile image", Access: User,
+		Upload: "imageFile",
+	},
+	{
+		Method: http.MethodGet, Path: "/account/export", Tag: "User", Summary: "Download a copy of my data", Access: User,
+		Description: "Profile, products with their images and offers made or received. With format=zip the same data " +
+			"is sent as an attachment holding one JSON file per section.",
+		Query: []Query{{Name: "format", Type: "string", Description: "json (default) or zip"}},
+		Data:  web.AccountExport{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodDelete, Path: "/account", Tag: "User", Summary: "Delete my account", Access: User,
+		Description: "The profile is anonymized, listings are deleted with their images and open offers are withdrawn. " +
+			"Accepted offers not paid yet are released with their product and order, completed ones stay visible to " +
+			"the other party under an anonymized name. Clears the token cookie. Refused while " +
+			"a paid order, a payment held in escrow, a wallet balance, a requested payout, an open auction or an open dispute " +
+			"of the account is left, all of which are listed.",
+		Status: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/account/:id/status", Tag: "User", Summary: "Get an account's status and its history", Access: Admin,
+		Description: "A suspension that has ended reads as ACTIVE.",
+		Data:        web.AccountStatusResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/account/:id/status", Tag: "User", Summary: "Suspend, ban or reinstate an account", Access: Admin,
+		Description: "SUSPENDED needs an until time in the future, SUSPENDED and BANNED need a reason. Blocked accounts cannot log in " +
+			"and their tokens stop working at once. Banning also hides the seller's listings and withdraws the offers they made.",
+		Request: web.AccountStatusRequest{}, Status: []int{http.StatusNotFound},
+	},
+
+	{Method: http.MethodGet, Path: "/data/province", Tag: "Data", Summary: "List provinces", Data: []web.DataResponse{}},
+	{
+		Method: http.MethodGet, Path: "/data/city", Tag: "Data", Summary: "List regencies and cities",
+		Query: []Query{{Name: "province", Type: "string", Description: "province id or name"}},
+		Data:  []web.CityResponse{}, Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/data/city", Tag: "Data", Summary: "Add a city", Access: Admin,
+		Request: web.CreateCityRequest{}, Status: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/data/city/:id", Tag: "Data", Summary: "Delete a city", Access: Admin,
+		Description: "Refused with 409 while profiles use the city, unless reassign_to names the city to move them to.",
+		Query:       []Query{{Name: "reassign_to", Type: "string", Description: "id of the city that takes over the profiles"}},
+		Status:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodPut, Path: "/data/city/:id", Tag: "Data", Summary: "Rename a city", Access: Admin,
+		Description: "Profiles using the old name are updated in the same transaction.",
+		Request:     web.CreateDataRequest{}, Status: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/data/city/:id/usage", Tag: "Data", Summary: "Count records using a city", Access: Admin,
+		Data: web.CityUsageResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/data/category", Tag: "Data", Summary: "List categories",
+		Description: "Ordered by sort_order then name. Use parent_id to build the tree.",
+		Data:        []web.CategoryResponse{},
+	},
+	{
+		Method: http.MethodPost, Path: "/data/category", Tag: "Data", Summary: "Add a category", Access: Admin,
+		Description: "The slug is derived from the name when omitted.",
+		Request:     web.CreateCategoryRequest{}, Status: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodDelete, Path: "/data/category/:id", Tag: "Data", Summary: "Delete a category", Access: Admin,
+		Description: "Refused with 409 while products or subcategories use the category, unless reassign_to names the category to move them to.",
+		Query:       []Query{{Name: "reassign_to", Type: "string", Description: "id of the category that takes over the products and subcategories"}},
+		Status:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodPut, Path: "/data/category/:id", Tag: "Data", Summary: "Rename a category", Access: Admin,
+		Description: "The slug is kept when omitted.",
+		Request:     web.RenameCategoryRequest{}, Status: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/data/category/:id/usage", Tag: "Data", Summary: "Count records using a category", Access: Admin,
+		Data: web.CategoryUsageResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/data/category/:id/approval", Tag: "Data", Summary: "Require review of new listings", Access: Admin,
+		Description: "Listings created or edited in a category that requires approval start as DRAFT and are only published once an admin approves them. Listings already approved stay published.",
+		Request:     web.CategoryApprovalRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	{
+		Method: http.MethodPost, Path: "/product", Tag: "Product", Summary: "Create a product", Access: User,
+		Description: "The seller profile must be complete and the category, given by slug or name, must exist.",
+		Request:     web.CreateProductRequest{}, Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/product", Tag: "Product", Summary: "List published products",
+		Query: []Query{
+			{Name: "province", Type: "string", Description: "seller province id or name"},
+			{Name: "city", Type: "string", Description: "seller regency or city id or name"},
+			{Name: "near", Type: "string", Description: "lat,lng, ranks by distance and adds distance_km"},
+			{Name: "radius", Type: "number", Description: "km around near, defaults to 10, at most 100"},
+		},
+		Data: []web.ProductResponse{}, Paged: true, Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/product/my-product", Tag: "Product", Summary: "List my products", Access: User,
+		Query: []Query{
+			{Name: "sold", Type: "boolean", Description: "defaults to false"},
+			{Name: "published", Type: "boolean", Description: "defaults to true"},
+		},
+		Description: "approval_status is DRAFT, PENDING, APPROVED or REJECTED, rejection_reason explains a rejection.",
+		Data:        []web.ProductResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/product/id/:id", Tag: "Product", Summary: "Get product detail", Access: User,
+		Data: web.ProductDetailResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/product/account/:id", Tag: "Product", Summary: "List a seller's published products", Access: User,
+		Data: []web.ProductResponse{}, Status: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/product/category/:path", Tag: "Product", Summary: "List products in a category", Access: User,
+		Description: "path is a category slug (or its name). Products in descendant categories are included.",
+		Data:        []web.ProductResponse{}, Paged: true, Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/product/:id", Tag: "Product", Summary: "Update my product", Access: User,
+		Description: "min_price and auto_accept_price are optional and cleared when omitted. Offers below min_price are declined and offers at or " +
+			"above auto_accept_price are accepted and reserve the product. Neither is shown to buyers.",
+		Request: web.UpdateProductRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/product/image/:id", Tag: "Product", Summary: "Upload a product image", Access: User,
+		Description: "The first image becomes the thumbnail.",
+		Upload:      "imageFile", Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/product/thumbnail", Tag: "Product", Summary: "Set the product thumbnail", Access: User,
+		Request: web.ProductImageRequest{}, Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/product/publish/:id", Tag: "Product", Summary: "Toggle the published flag", Access: User,
+		Description: "A thumbnail is required before publishing. A DRAFT or REJECTED listing in a category that requires approval is submitted for review instead and published once approved.",
+		Status:      []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/product/status/:id", Tag: "Product", Summary: "Toggle the sold flag", Access: User,
+		Description: "Marking a reserved product sold ends its reservation.",
+		Status:      []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/product/release/:id", Tag: "Product", Summary: "Release a reserved product", Access: User,
+		Description: "Owners release their own products, admins any product. The product is available again and the offer that reserved it " +
+			"is no longer accepted, its unpaid order is cancelled. Reservations also end by themselves RESERVATION_WINDOW after the offer " +
+			"was accepted unless the order was paid.",
+		Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodDelete, Path: "/product/:id", Tag: "Product", Summary: "Delete a product", Access: User,
+		Description: "Owners delete their own products, admins can delete any product.",
+		Status:      []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodDelete, Path: "/product/image", Tag: "Product", Summary: "Delete a product image", Access: User,
+		Description: "The current thumbnail cannot be deleted.",
+		Request:     web.ProductImageRequest{}, Status: []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/product/review", Tag: "Product", Summary: "Listings waiting for review", Access: Admin,
+		Description: "Ordered by submitted_at, the longest waiting first.",
+		Data:        []web.PendingProductResponse{}, Paged: true,
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/product/review/:id", Tag: "Product", Summary: "Approve or reject a listing", Access: Admin,
+		Description: "APPROVE publishes the listing. REJECT needs a reason, which the seller sees in their product list.",
+		Request:     web.ProductReviewRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	{
+		Method: http.MethodPost, Path: "/transaction", Tag: "Transaction", Summary: "Make an offer", Access: User,
+		Description: "Only published products that are not sold can receive offers and the price must be greater than zero. " +
+			"A buyer has one open offer per product, offering again changes its price. Refused with 409 once the offer was accepted. " +
+			"Prices below the seller's minimum are declined and prices at or above the seller's auto-accept price are accepted, which reserves the product.",
+		Request: web.TransactionRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/transaction/offer", Tag: "Transaction", Summary: "List offers received as seller", Access: User,
+		Data: []web.OfferWithAccount{},
+	},
+	{
+		Method: http.MethodGet, Path: "/transaction/my-transaction", Tag: "Transaction", Summary: "List offers made as buyer", Access: User,
+		Data: []web.OfferWithAccount{},
+	},
+	{
+		Method: http.MethodGet, Path: "/transaction/id/:id", Tag: "Transaction", Summary: "Get offer detail", Access: User,
+		Description: "Contact details of both parties follow their privacy settings, as on GET /profile/{id}. " +
+			"negotiation lists every price proposed by the buyer and the seller, oldest first.",
+		Data: web.TransactionDetailResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/transaction/product/:id", Tag: "Transaction", Summary: "List offers on a product", Access: User,
+		Data: web.OfferByProduct{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/transaction/buyer/:id", Tag: "Transaction", Summary: "List a buyer's offers on my products", Access: User,
+		Data: web.OfferByBuyer{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/transaction", Tag: "Transaction", Summary: "Change my offer price", Access: User,
+		Description: "Accepted offers and offers on products that are sold, deleted, unpublished or reserved cannot be changed. The seller's pricing rules apply as when making an offer.",
+		Request:     web.TransactionUpdateRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/transaction/id/:id", Tag: "Transaction", Summary: "Toggle offer acceptance", Access: User,
+		Description: "Accepting reserves the product for the buyer for RESERVATION_WINDOW, it is hidden from listings and takes no other offers, " +
+			"and opens an order. An offer whose latest price is the seller's counter-offer is accepted by the buyer instead. " +
+			"Taking the acceptance back releases the product and cancels the order, unless it was paid.",
+		Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/transaction/id/:id/counter", Tag: "Transaction", Summary: "Counter an offer", Access: User,
+		Description: "The seller proposes another price on an open offer. Every price proposed by either side is kept in the negotiation of the offer detail.",
+		Request:     web.CounterOfferRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/transaction/id/:id/counter/accept", Tag: "Transaction", Summary: "Accept a counter-offer", Access: User,
+		Description: "The buyer accepts the seller's counter-offer at its price, which reserves the product and opens an order as when the seller accepts.",
+		Status:      []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	{
+		Method: http.MethodPost, Path: "/report/product/:id", Tag: "Report", Summary: "Report a listing", Access: User,
+		Description: "One open report per person and target. A listing reported by REPORT_THRESHOLD people is unpublished " +
+			"until a moderator decides, and its owner cannot publish it meanwhile.",
+		Request: web.ReportRequest{}, Status: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/report/profile/:id", Tag: "Report", Summary: "Report a user", Access: User,
+		Request: web.ReportRequest{}, Status: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/report/transaction/:id", Tag: "Report", Summary: "Report the other party of an offer", Access: User,
+		Description: "Only the buyer and the seller of the offer can report it.",
+		Request:     web.ReportRequest{}, Status: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/report", Tag: "Report", Summary: "Moderation queue", Access: Admin,
+		Description: "Ordered by due_at, which is created_at plus REPORT_SLA. overdue marks open reports past their due time.",
+		Query: []Query{
+			{Name: "status", Type: "string", Description: "OPEN (default), DISMISSED or ACTIONED"},
+			{Name: "target_type", Type: "string", Description: "PRODUCT, PROFILE or TRANSACTION"},
+		},
+		Data: []web.ReportResponse{}, Paged: true,
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/report/:id", Tag: "Report", Summary: "Resolve a report", Access: Admin,
+		Description: "DISMISS closes it, HIDE_LISTING unpublishes a reported product for good and SUSPEND_USER suspends the " +
+			"reported account until the given time. Every open report on the same target is closed with the same decision.",
+		Request: web.ResolveReportRequest{}, Status: []int{http.StatusNotFound},
+	},
+
+	{
+		Method: http.MethodGet, Path: "/order", Tag: "Order", Summary: "List my orders", Access: User,
+		Description: "Latest first. Accepting an offer opens its order, the orders I buy and sell in are both listed unless role is given.",
+		Query: []Query{
+			{Name: "role", Type: "string", Description: "BUYER or SELLER"},
+			{Name: "status", Type: "string", Description: "AWAITING_PAYMENT, PAID, SHIPPED, DELIVERED, COMPLETED or CANCELLED"},
+		},
+		Data: []web.OrderResponse{}, Paged: true, Status: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/order/:id", Tag: "Order", Summary: "Get an order", Access: User,
+		Description: "Only the buyer, the seller and admins can see an order.",
+		Data:        web.OrderResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/order/:id", Tag: "Order", Summary: "Move an order forward", Access: User,
+		Description: "The seller confirms a payment made outside the app (PAID) and ships with a carrier and tracking number (SHIPPED), " +
+			"the buyer confirms the delivery (DELIVERED). Either party completes a delivered order, which marks the product sold and " +
+			"releases money paid in the app to the seller, or cancels an order that was not shipped yet with a reason, which refunds " +
+			"money paid in the app and makes the product available again. The other party is notified.",
+		Request: web.OrderStatusRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/order/:id/pay", Tag: "Order", Summary: "Pay an order in the app", Access: User,
+		Description: "The buyer pays at payment_url. The money is held in escrow once the provider reports the payment, the order is " +
+			"then PAID. Paying again while the payment is pending returns the same payment.",
+		Data: web.PaymentResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/payment/webhook", Tag: "Order", Summary: "Payment provider webhook",
+		Description: "The X-Signature header is the hex HMAC-SHA256 of the body with PAYMENT_WEBHOOK_SECRET. type is charge.authorized, " +
+			"charge.failed, charge.captured or charge.refunded. An event delivered again is acknowledged without effect. An event " +
+			"of a charge with no payment yet is refused with 404 so the provider delivers it again.",
+		Request: web.PaymentEvent{}, Status: []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+	},
+
+	{
+		Method: http.MethodGet, Path: "/wallet", Tag: "Wallet", Summary: "Get my balance", Access: User,
+		Description: "available is the money from completed sales that can be paid out, after the PLATFORM_FEE_PERCENT fee. " +
+			"pending_payout waits for an admin and held waits in escrow until orders are completed.",
+		Data: web.WalletResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/wallet/statement", Tag: "Wallet", Summary: "Get my statement", Access: User,
+		Description: "Latest first. Every change of the available balance with the ledger entry behind it, a positive amount is money in.",
+		Data:        []web.StatementLine{}, Paged: true,
+	},
+	{
+		Method: http.MethodPost, Path: "/wallet/payout", Tag: "Wallet", Summary: "Request a payout", Access: User,
+		Description: "The amount leaves the available balance right away and comes back if an admin rejects the payout.",
+		Request:     web.PayoutRequest{}, Data: web.PayoutResponse{}, Status: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/wallet/payout", Tag: "Wallet", Summary: "List my payouts", Access: User,
+		Description: "Latest first.",
+		Data:        []web.PayoutResponse{}, Paged: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/payout", Tag: "Wallet", Summary: "Payouts to review", Access: Admin,
+		Description: "Oldest first.",
+		Query:       []Query{{Name: "status", Type: "string", Description: "REQUESTED, APPROVED or REJECTED"}},
+		Data:        []web.PayoutResponse{}, Paged: true, Status: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/payout/:id", Tag: "Wallet", Summary: "Approve or reject a payout", Access: Admin,
+		Description: "Approve once the money was sent to the destination. Rejecting gives the amount back to the seller's balance. The seller is notified.",
+		Request:     web.PayoutReviewRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	{
+		Method: http.MethodPost, Path: "/transaction/id/:id/dispute", Tag: "Dispute", Summary: "Dispute an order", Access: User,
+		Description: "The buyer disputes the order of an accepted offer once it is paid and until it is completed. The seller has " +
+			"DISPUTE_RESPONSE_WINDOW to answer. Until an admin resolves the dispute the product, the offer and the order cannot change.",
+		Request: web.DisputeRequest{}, Data: web.DisputeResponse{},
+		Status: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/dispute", Tag: "Dispute", Summary: "List my disputes", Access: User,
+		Description: "Disputes I opened as a buyer or answer as a seller, latest first.",
+		Query:       []Query{{Name: "status", Type: "string", Description: "OPEN, ESCALATED, RESOLVING or RESOLVED"}},
+		Data:        []web.DisputeResponse{}, Paged: true, Status: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/dispute/:id", Tag: "Dispute", Summary: "Get a dispute and its messages", Access: User,
+		Description: "For the buyer, the seller and admins.",
+		Data:        web.DisputeResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/dispute/:id/message", Tag: "Dispute", Summary: "Send a message in a dispute", Access: User,
+		Description: "When the dispute awaits the author, it then awaits the other party for DISPUTE_RESPONSE_WINDOW. An open dispute " +
+			"nobody answered in time is escalated to the admins.",
+		Request: web.DisputeMessageRequest{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/dispute/:id/evidence", Tag: "Dispute", Summary: "Upload evidence to a dispute", Access: User,
+		Description: "The image is added to the messages like an answer.",
+		Upload:      "imageFile", Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/dispute", Tag: "Dispute", Summary: "Disputes to resolve", Access: Admin,
+		Description: "Unresolved disputes unless status is given, escalated ones first and then the oldest.",
+		Query:       []Query{{Name: "status", Type: "string", Description: "OPEN, ESCALATED, RESOLVING or RESOLVED"}},
+		Data:        []web.DisputeResponse{}, Paged: true, Status: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodPut, Path: "/admin/dispute/:id", Tag: "Dispute", Summary: "Resolve a dispute", Access: Admin,
+		Description: "REFUND gives the money held for the order back and cancels it. PARTIAL_REFUND gives refund_amount back, " +
+			"releases the rest to the seller and completes the order. REJECT lets the order go on. Money paid outside the app " +
+			"is settled by the parties, a delivered order whose money is not held in the app cannot be refunded. Both parties are notified. The dispute is RESOLVING while the payment provider moves the money, " +
+			"and ESCALATED again when the provider fails.",
+		Request: web.ResolveDisputeRequest{},
+		Status:  []int{http.StatusBadRequest, http.StatusNotFound, http.StatusServiceUnavailable},
+	},
+
+	{
+		Method: http.MethodPost, Path: "/product/auction/:id", Tag: "Auction", Summary: "Auction my product", Access: User,
+		Description: "The product must be able to take offers, and takes none until the auction ends. reserve_price, optional and " +
+			"only shown to the seller, is the lowest high bid the product is sold for.",
+		Request: web.AuctionRequest{}, Data: web.AuctionResponse{},
+		Status: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/auction", Tag: "Auction", Summary: "List running auctions",
+		Description: "The auction ending first comes first.",
+		Data:        []web.AuctionResponse{}, Paged: true, Status: []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodGet, Path: "/auction/:id", Tag: "Auction", Summary: "Get an auction and its bids", Access: User,
+		Description: "Bids are listed latest first. reserve_met tells bidders whether the high bid reaches the reserve price.",
+		Data:        web.AuctionResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/auction/:id/bid", Tag: "Auction", Summary: "Bid on an auction", Access: User,
+		Description: "A bid must reach minimum_bid, the start price or the high bid plus the minimum increment. A bid less than " +
+			"AUCTION_EXTENSION before the end pushes the end to AUCTION_EXTENSION after the bid. The outbid bidder is notified. " +
+			"Once ended, a high bid reaching the reserve price becomes an accepted offer of its bidder.",
+		Request: web.BidRequest{}, Data: web.AuctionResponse{}, Status: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPut, Path: "/auction/:id/cancel", Tag: "Auction", Summary: "Cancel an auction", Access: User,
+		Description: "Only the seller or an admin can cancel, and only before the first bid.",
+		Status:      []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+
+	{
+		Method: http.MethodGet, Path: "/notification", Tag: "Notification", Summary: "List my notifications", Access: User,
+		Description: "Latest first. Sellers are reminded of offers left unanswered for OFFER_REMINDER_AFTER, buyers hear of offers that expired " +
+			"after OFFER_TTL, sellers of listings unpublished after STALE_LISTING_DAYS without changes and bidders of being outbid " +
+			"and of ended auctions.",
+		Data: []web.NotificationResponse{}, Paged: true,
+	},
+	{
+		Method: http.MethodGet, Path: "/admin/job/run", Tag: "Job", Summary: "Background job runs", Access: Admin,
+		Description: "Latest first. The jobs run every JOB_INTERVAL on one replica at a time. affected counts the offers expired, " +
+			"the sellers reminded, the listings unpublished or the reservations ended.",
+		Query: []Query{
+			{Name: "job", Type: "string", Description: "expire_offers, remind_sellers, unpublish_stale_listings or end_reservations"},
+			{Name: "status", Type: "string", Description: "RUNNING, SUCCEEDED or FAILED"},
+		},
+		Data: []web.JobRunResponse{}, Paged: true, Status: []int{http.StatusBadRequest},
+	},
+}
@@ -0,0 +1,165 @@
+package docs
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	uuidType = reflect.TypeOf(uuid.UUID{})
+	timeType = reflect.TypeOf(time.Time{})
+)
+
+// schemaFor returns a reference to the component schema of t, registering it
+// and every struct it embeds in components on first use
+func (d *Document) schemaFor(t reflect.Type) *Schema {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaFor(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := d.Components.Schemas[name]; !ok {
+			d.Components.Schemas[name] = &Schema{}
+			d.Components.Schemas[name] = d.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	return &Schema{}
+}
+
+func (d *Document) structSchema(t reflect.Type) *Schema {
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("openapi") == "-" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := d.schemaFor(field.Type)
+		if property.Ref == "" {
+			if applyBinding(property, field.Tag.Get("binding")) {
+				schema.Required = append(schema.Required, name)
+			}
+		} else if strings.Contains(field.Tag.Get("binding"), "required") {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+
+	return schema
+}
+
+// applyBinding copies the validator constraints gin enforces through the
+// binding tag onto the schema and reports whether the field is required
+func applyBinding(schema *Schema, tag string) bool {
+
+	required := false
+
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+
+		switch key {
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				schema.Enum = append(schema.Enum, option)
+			}
+		case "gte", "gt", "min", "lte", "lt", "max", "len":
+			limit, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			applyLimit(schema, key, limit)
+		}
+	}
+
+	return required
+}
+
+func applyLimit(schema *Schema, key string, limit float64) {
+
+	lower := key == "gte" || key == "gt" || key == "min" || key == "len"
+	upper := key == "lte" || key == "lt" || key == "max" || key == "len"
+
+	if schema.Type == "string" || schema.Type == "array" {
+		n := int(limit)
+		if key == "gt" {
+			n++
+		}
+		if key == "lt" {
+			n--
+		}
+
+		switch {
+		case schema.Type == "string" && lower:
+			schema.MinLength = &n
+		case schema.Type == "string" && upper:
+			schema.MaxLength = &n
+		case lower:
+			schema.MinItems = &n
+		case upper:
+			schema.MaxItems = &n
+		}
+		if key == "len" {
+			if schema.Type == "string" {
+				schema.MaxLength = &n
+			} else {
+				schema.MaxItems = &n
+			}
+		}
+		return
+	}
+
+	if lower {
+		schema.Minimum = &limit
+		schema.ExclusiveMinimum = key == "gt"
+	}
+	if upper {
+		schema.Maximum = &limit
+		schema.ExclusiveMaximum = key == "lt"
+	}
+}
@@ -2,6 +2,8 @@ package helper
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -10,14 +12,12 @@ import (
 
 func BindData(c *gin.Context, ut ut.Translator, req interface{}) bool {
 
+	ut = Translator(c, ut)
+
 	if c.ContentType() != "application/json" {
 		msg := fmt.Sprintf("%s only accepts Content-Type application/json", c.FullPath())
 
-		err := NewUnsupportedMediaType(msg)
-
-		c.JSON(err.Status(), gin.H{
-			"error": err,
-		})
+		WriteError(c, NewUnsupportedMediaType(msg))
 		return false
 	}
 
@@ -25,29 +25,50 @@ func BindData(c *gin.Context, ut ut.Translator, req interface{}) bool {
 	if err := c.ShouldBind(req); err != nil {
 
 		if errs, ok := err.(validator.ValidationErrors); ok {
-			var invalidArgs []string
+			var fields []FieldError
 
 
 			for _, err := range errs {
 
-				invalidArgs = append(invalidArgs, err.Translate(ut))
+				fields = append(fields, FieldError{
+					Field:   jsonFieldName(req, err.StructField()),
+					Code:    err.Tag(),
+					Message: err.Translate(ut),
+				})
 				
 			}
 
-			err := NewBadRequest("Invalid request parameters. See invalidArgs")
-
-			c.JSON(err.Status(), gin.H{
-				"error":       err,
-				"invalidArgs": invalidArgs,
-			})
+			WriteValidationError(c, fields)
 			return false
 		}
 
-		fallBack := NewInternal()
-
-		c.JSON(fallBack.Status(), gin.H{"error": fallBack})
+		WriteError(c, NewInternal())
 		return false
 	}
 
 	return true
-}
\ No newline at end of file
+}
+
+func jsonFieldName(req interface{}, structField string) string {
+
+	t := reflect.TypeOf(req)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return structField
+	}
+
+	field, ok := t.FieldByName(structField)
+	if !ok {
+		return structField
+	}
+
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return structField
+	}
+
+	return name
+}
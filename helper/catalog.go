@@ -0,0 +1,129 @@
+package helper
+
+type message struct {
+	en string
+	id string
+}
+
+// messages are the templates behind the error factories, {0} and {1} are the
+// factory arguments
+var messages = map[string]message{
+	"authorization":          {"{0}", "{0}"},
+	"account_suspended":      {"account suspended until {0}. Reason: {1}", "akun ditangguhkan hingga {0}. Alasan: {1}"},
+	"account_banned":         {"account banned. Reason: {0}", "akun diblokir. Alasan: {0}"},
+	"bad_request":            {"Bad request. Reason: {0}", "Permintaan tidak valid. Alasan: {0}"},
+	"conflict":               {"resource: {0} with value: {1} already exists", "data: {0} dengan nilai: {1} sudah ada"},
+	"in_use":                 {"resource: {0} with value: {1} is still used by {2}", "data: {0} dengan nilai: {1} masih digunakan oleh {2}"},
+	"internal":               {"Internal server error.", "Terjadi kesalahan pada server."},
+	"not_found":              {"resource: {0} with value: {1} not found", "data: {0} dengan nilai: {1} tidak ditemukan"},
+	"payload_too_large":      {"Max payload size of {0} exceeded. Actual payload size: {1}", "Ukuran maksimal {0} terlampaui. Ukuran yang dikirim: {1}"},
+	"service_unavailable":    {"Service unavailable or timed out", "Layanan tidak tersedia atau waktu habis"},
+	"unsupported_media_type": {"{0}", "{0}"},
+}
+
+// phrases translates the reasons and resource names passed to the error
+// factories. Anything missing here is shown in English.
+var phrases = map[string]string{
+	"invalid uuid":                                                            "uuid tidak valid",
+	"page and per_page must be numbers":                                       "page dan per_page harus berupa angka",
+	"complete your profile first":                                             "lengkapi profil anda terlebih dahulu",
+	"Unable to parse multipart/form-data":                                     "Tidak dapat membaca multipart/form-data",
+	"only buyer, seller, and admin can access":                                "hanya pembeli, penjual, dan admin yang dapat mengakses",
+	"Invalid email and password combination":                                  "Kombinasi email dan kata sandi tidak valid",
+	"you cannot buy your own product":                                         "anda tidak dapat membeli produk anda sendiri",
+	"cannot delete thumbnail image":                                           "gambar thumbnail tidak dapat dihapus",
+	"add thumbnail before publish product":                                    "tambahkan thumbnail sebelum menerbitkan produk",
+	"token required":                                                          "token diperlukan",
+	"seller and admin can access":                                             "hanya penjual dan admin yang dapat mengakses",
+	"only admin can access":                                                   "hanya admin yang dapat mengakses",
+	"invalid token":                                                           "token tidak valid",
+	"Invalid request parameters. See invalidArgs":                             "Parameter permintaan tidak valid. Lihat invalidArgs",
+	"Invalid request parameters. See fields":                                  "Parameter permintaan tidak valid. Lihat fields",
+	"cannot reassign to the deleted item":                                     "tidak dapat memindahkan ke data yang dihapus",
+	"choose a regency or city that belongs to a province":                     "pilih kabupaten atau kota yang termasuk dalam suatu provinsi",
+	"invalid product filter":                                                  "filter produk tidak valid",
+	"coordinates must be lat,lng":                                             "koordinat harus berformat lat,lng",
+	"latitude must be between -90 and 90":                                     "latitude harus di antara -90 dan 90",
+	"longitude must be between -180 and 180":                                  "longitude harus di antara -180 dan 180",
+	"category slug cannot be empty":                                           "slug kategori tidak boleh kosong",
+	"format must be json or zip":                                              "format harus json atau zip",
+	"you cannot change the status of your own account":                        "anda tidak dapat mengubah status akun anda sendiri",
+	"a suspension must end in the future":                                     "penangguhan harus berakhir di masa depan",
+	"you cannot report yourself":                                              "anda tidak dapat melaporkan diri sendiri",
+	"only the buyer and seller can report a transaction":                      "hanya pembeli dan penjual yang dapat melaporkan transaksi",
+	"invalid report filter":                                                   "filter laporan tidak valid",
+	"the report was already resolved":                                         "laporan sudah ditangani",
+	"only a product report can hide a listing":                                "hanya laporan produk yang dapat menyembunyikan produk",
+	"this product was hidden by a moderator":                                  "produk ini disembunyikan oleh moderator",
+	"this product is under review after being reported":                       "produk ini sedang ditinjau karena dilaporkan",
+	"this product is already waiting for review":                              "produk ini sudah menunggu peninjauan",
+	"this product is not waiting for review":                                  "produk ini tidak sedang menunggu peninjauan",
+	"price offer must be greater than zero":                                   "harga tawaran harus lebih dari nol",
+	"this product was deleted":                                                "produk ini sudah dihapus",
+	"this product is already sold":                                            "produk ini sudah terjual",
+	"this product is not published":                                           "produk ini belum diterbitkan",
+	"the buyer has to accept your counter-offer":                              "pembeli harus menerima tawaran balik Anda",
+	"there is no counter-offer to accept":                                     "tidak ada tawaran balik untuk diterima",
+	"an accepted offer cannot be changed":                                     "tawaran yang sudah diterima tidak dapat diubah",
+	"invalid job run filter":                                                  "filter job tidak valid",
+	"this product is reserved for another buyer":                              "produk ini sedang dipesan oleh pembeli lain",
+	"this product is not reserved":                                            "produk ini tidak sedang dipesan",
+	"invalid order filter":                                                    "filter pesanan tidak valid",
+	"the order of this offer is already paid":                                 "pesanan dari tawaran ini sudah dibayar",
+	"the order cannot move to this status from its current status":            "pesanan tidak dapat diubah ke status ini dari status saat ini",
+	"only the seller can move the order to this status":                       "hanya penjual yang dapat mengubah pesanan ke status ini",
+	"only the buyer can move the order to this status":                        "hanya pembeli yang dapat mengubah pesanan ke status ini",
+	"the order is already being updated":                                      "pesanan sedang diperbarui",
+	"this order is being paid in the app":                                     "pesanan ini sedang dibayar melalui aplikasi",
+	"only an order awaiting payment can be paid":                              "hanya pesanan yang menunggu pembayaran yang dapat dibayar",
+	"invalid signature":                                                       "tanda tangan tidak valid",
+	"invalid payment event":                                                   "event pembayaran tidak valid",
+	"insufficient balance":                                                    "saldo tidak mencukupi",
+	"invalid payout filter":                                                   "filter penarikan dana tidak valid",
+	"the payout was already reviewed":                                         "penarikan dana sudah ditinjau",
+	"only the buyer can open a dispute":                                       "hanya pembeli yang dapat mengajukan sengketa",
+	"only an accepted offer can be disputed":                                  "hanya tawaran yang sudah diterima yang dapat disengketakan",
+	"only a paid order that is not completed can be disputed":                 "hanya pesanan yang sudah dibayar dan belum selesai yang dapat disengketakan",
+	"this product has an open dispute":                                        "produk ini sedang dalam sengketa",
+	"a delivered order whose money is not held in the app cannot be refunded": "pesanan yang sudah diterima tetapi dananya tidak ditahan di aplikasi tidak dapat dikembalikan dananya",
+	"the dispute is already being resolved":                                   "sengketa sedang diselesaikan",
+	"the dispute is not being resolved":                                       "sengketa tidak sedang diselesaikan",
+	"the dispute was already resolved":                                        "sengketa sudah diselesaikan",
+	"invalid dispute filter":                                                  "filter sengketa tidak valid",
+	"a partial refund must be more than zero and less than the price":         "pengembalian dana sebagian harus lebih dari nol dan kurang dari harga",
+	"this product is being auctioned":                                         "produk ini sedang dilelang",
+	"this product cannot be auctioned right now":                              "produk ini tidak dapat dilelang saat ini",
+	"the auction must end in the future":                                      "lelang harus berakhir di masa depan",
+	"the reserve price cannot be below the start price":                       "harga cadangan tidak boleh di bawah harga awal",
+	"this auction has ended":                                                  "lelang ini sudah berakhir",
+	"you cannot bid on your own auction":                                      "anda tidak dapat menawar lelang anda sendiri",
+	"a bid must be at least the minimum bid":                                  "tawaran lelang harus minimal sebesar tawaran minimum",
+	"only an open auction without bids can be cancelled":                      "hanya lelang yang masih berjalan dan belum ada tawaran yang dapat dibatalkan",
+	"the auto-accept price cannot be below the minimum price":                 "harga terima otomatis tidak boleh di bawah harga minimum",
+	"thank you for your offer, but the seller cannot accept this price. Please try a higher offer": "terima kasih atas tawaran anda, namun penjual belum dapat menerima harga ini. Silakan ajukan tawaran yang lebih tinggi",
+
+	"id":              "id",
+	"account":         "akun",
+	"product id":      "id produk",
+	"transaction id":  "id transaksi",
+	"email":           "email",
+	"city":            "kota",
+	"City":            "Kota",
+	"province":        "provinsi",
+	"city name":       "nama kota",
+	"category":        "kategori",
+	"Category":        "Kategori",
+	"category name":   "nama kategori",
+	"category slug":   "slug kategori",
+	"parent category": "kategori induk",
+	"report":          "laporan",
+	"accepted offer":  "tawaran yang diterima",
+	"order id":        "id pesanan",
+	"payment":         "pembayaran",
+	"charge id":       "id tagihan",
+	"payout id":       "id penarikan dana",
+	"dispute":         "sengketa",
+	"dispute id":      "id sengketa",
+	"auction":         "lelang",
+	"auction id":      "id lelang",
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
+
+	ut "github.com/go-playground/universal-translator"
 )
 
 type Type string
@@ -12,6 +15,7 @@ const (
 	Authorization        Type = "AUTHORIZATION"          
 	BadRequest           Type = "BAD_REQUEST"           
 	Conflict             Type = "CONFLICT"               
+	Forbidden            Type = "FORBIDDEN"
 	Internal             Type = "INTERNAL"               
 	NotFound             Type = "NOT_FOUND"              
 	PayloadTooLarge      Type = "PAYLOAD_TOO_LARGE"     
@@ -22,6 +26,8 @@ const (
 type Error struct {
 	Type    Type   `json:"type"`
 	Message string `json:"message"`
+	key     string
+	params  []string
 }
 
 func (e *Error) Error() string {
@@ -36,6 +42,8 @@ func (e *Error) Status() int {
 		return http.StatusBadRequest
 	case Conflict:
 		return http.StatusConflict
+	case Forbidden:
+		return http.StatusForbidden
 	case Internal:
 		return http.StatusInternalServerError
 	case NotFound:
@@ -51,6 +59,30 @@ func (e *Error) Status() int {
 	}
 }
 
+// Localize renders the message in the locale of trans, translating factory
+// arguments found in the phrase catalog and leaving the rest untouched
+func (e *Error) Localize(trans ut.Translator) *Error {
+	if e.key == "" || trans == nil {
+		return e
+	}
+
+	params := make([]string, len(e.params))
+	for i, param := range e.params {
+		translated, err := trans.T(param)
+		if err != nil {
+			translated = param
+		}
+		params[i] = translated
+	}
+
+	message, err := trans.T(e.key, params...)
+	if err != nil {
+		return e
+	}
+
+	return &Error{Type: e.Type, Message: message, key: e.key, params: e.params}
+}
+
 func Status(err error) int {
 	var e *Error
 	if errors.As(err, &e) {
@@ -68,6 +100,8 @@ func NewAuthorization(reason string) *Error {
 	return &Error{
 		Type:    Authorization,
 		Message: reason,
+		key:     "authorization",
+		params:  []string{reason},
 	}
 }
 
@@ -76,6 +110,8 @@ func NewBadRequest(reason string) *Error {
 	return &Error{
 		Type:    BadRequest,
 		Message: fmt.Sprintf("Bad request. Reason: %v", reason),
+		key:     "bad_request",
+		params:  []string{reason},
 	}
 }
 
@@ -84,6 +120,38 @@ func NewConflict(name string, value string) *Error {
 	return &Error{
 		Type:    Conflict,
 		Message: fmt.Sprintf("resource: %v with value: %v already exists", name, value),
+		key:     "conflict",
+		params:  []string{name, value},
+	}
+}
+
+// NewInUse to create a 409 for a resource that is still referenced elsewhere
+func NewInUse(name string, value string, usage string) *Error {
+	return &Error{
+		Type:    Conflict,
+		Message: fmt.Sprintf("resource: %v with value: %v is still used by %v", name, value, usage),
+		key:     "in_use",
+		params:  []string{name, value, usage},
+	}
+}
+
+// NewAccountSuspended to create a 403 for an account suspended until a time
+func NewAccountSuspended(until time.Time, reason string) *Error {
+	return &Error{
+		Type:    Forbidden,
+		Message: fmt.Sprintf("account suspended until %v. Reason: %v", until.Format(time.RFC3339), reason),
+		key:     "account_suspended",
+		params:  []string{until.Format(time.RFC3339), reason},
+	}
+}
+
+// NewAccountBanned to create a 403 for a banned account
+func NewAccountBanned(reason string) *Error {
+	return &Error{
+		Type:    Forbidden,
+		Message: fmt.Sprintf("account banned. Reason: %v", reason),
+		key:     "account_banned",
+		params:  []string{reason},
 	}
 }
 
@@ -92,6 +160,7 @@ func NewInternal() *Error {
 	return &Error{
 		Type:    Internal,
 		Message: "Internal server error.",
+		key:     "internal",
 	}
 }
 
@@ -100,6 +169,8 @@ func NewNotFound(name string, value string) *Error {
 	return &Error{
 		Type:    NotFound,
 		Message: fmt.Sprintf("resource: %v with value: %v not found", name, value),
+		key:     "not_found",
+		params:  []string{name, value},
 	}
 }
 
@@ -108,6 +179,8 @@ func NewPayloadTooLarge(maxBodySize int64, contentLength int64) *Error {
 	return &Error{
 		Type:    PayloadTooLarge,
 		Message: fmt.Sprintf("Max payload size of %v exceeded. Actual payload size: %v", maxBodySize, contentLength),
+		key:     "payload_too_large",
+		params:  []string{fmt.Sprint(maxBodySize), fmt.Sprint(contentLength)},
 	}
 }
 
@@ -116,6 +189,7 @@ func NewServiceUnavailable() *Error {
 	return &Error{
 		Type:    ServiceUnavailable,
 		Message: "Service unavailable or timed out",
+		key:     "service_unavailable",
 	}
 }
 
@@ -124,5 +198,7 @@ func NewUnsupportedMediaType(reason string) *Error {
 	return &Error{
 		Type:    UnsupportedMediaType,
 		Message: reason,
+		key:     "unsupported_media_type",
+		params:  []string{reason},
 	}
 }
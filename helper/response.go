@@ -0,0 +1,148 @@
+package helper
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+)
+
+// APIVersionKey is the gin context key set by middleware.APIVersion. Routes
+// without it are the legacy unversioned API and keep their original shapes.
+const APIVersionKey = "api_version"
+
+type Envelope struct {
+	Data  interface{}  `json:"data,omitempty"`
+	Meta  *Meta        `json:"meta,omitempty"`
+	Error *ErrorDetail `json:"error,omitempty"`
+}
+
+type Meta struct {
+	Page    int `json:"page"`
+	PerPage int `json:"per_page"`
+	Total   int `json:"total"`
+}
+
+type ErrorDetail struct {
+	Code    Type         `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type MessageData struct {
+	Message string `json:"message"`
+}
+
+// Translator returns the translator negotiated by middleware.Locale, or
+// fallback when the request did not go through it
+func Translator(c *gin.Context, fallback ut.Translator) ut.Translator {
+	if trans, ok := c.Get(TranslatorKey); ok {
+		return trans.(ut.Translator)
+	}
+	return fallback
+}
+
+func isLegacy(c *gin.Context) bool {
+	return c.GetString(APIVersionKey) == ""
+}
+
+func WriteError(c *gin.Context, err error) {
+
+	var e *Error
+	if !errors.As(err, &e) {
+		e = NewInternal()
+	}
+	e = e.Localize(Translator(c, nil))
+
+	if isLegacy(c) {
+		c.JSON(e.Status(), gin.H{
+			"error": e,
+		})
+		return
+	}
+
+	c.JSON(e.Status(), Envelope{
+		Error: &ErrorDetail{Code: e.Type, Message: e.Message},
+	})
+}
+
+func WriteValidationError(c *gin.Context, fields []FieldError) {
+
+	if isLegacy(c) {
+		err := NewBadRequest("Invalid request parameters. See invalidArgs").Localize(Translator(c, nil))
+
+		var invalidArgs []string
+		for _, field := range fields {
+			invalidArgs = append(invalidArgs, field.Message)
+		}
+
+		c.JSON(err.Status(), gin.H{
+			"error":       err,
+			"invalidArgs": invalidArgs,
+		})
+		return
+	}
+
+	err := NewBadRequest("Invalid request parameters. See fields").Localize(Translator(c, nil))
+
+	c.JSON(err.Status(), Envelope{
+		Error: &ErrorDetail{Code: err.Type, Message: err.Message, Fields: fields},
+	})
+}
+
+func WriteMessage(c *gin.Context, message string) {
+
+	if isLegacy(c) {
+		c.JSON(http.StatusOK, gin.H{
+			"Status":"Success",
+			"Message":message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Envelope{Data: MessageData{Message: message}})
+}
+
+func WriteData(c *gin.Context, data interface{}) {
+	WriteDataAs(c, "Data", data)
+}
+
+// WriteDataAs is WriteData for legacy routes that returned data under a key
+// other than "Data", or bare when legacyKey is empty
+func WriteDataAs(c *gin.Context, legacyKey string, data interface{}) {
+
+	if isLegacy(c) {
+		if legacyKey == "" {
+			c.JSON(http.StatusOK, data)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"Status":"Success",
+			legacyKey:data,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Envelope{Data: data})
+}
+
+func WritePage(c *gin.Context, data interface{}, meta Meta) {
+
+	if isLegacy(c) {
+		c.JSON(http.StatusOK, gin.H{
+			"Status":"Success",
+			"Data":data,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Envelope{Data: data, Meta: &meta})
+}
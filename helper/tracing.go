@@ -0,0 +1,15 @@
+package helper
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/RuhullahReza/SecondHand")
+
+// StartSpan starts a child of the span carried by ctx, the caller must end it
+func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name)
+}
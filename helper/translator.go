@@ -1,20 +1,69 @@
 package helper
 
 import (
+	"log"
+
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
 	"github.com/go-playground/validator/v10"
 	ut "github.com/go-playground/universal-translator"
 	en_translations "github.com/go-playground/validator/v10/translations/en"
+	id_translations "github.com/go-playground/validator/v10/translations/id"
+)
+
+// Context keys set by middleware.Locale
+const (
+	LocaleKey     = "locale"
+	TranslatorKey = "translator"
 )
 
-func InitTranslator() ut.Translator {
-	var translator ut.Translator
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		en := en.New()
-		uni := ut.New(en, en)
-		translator, _ = uni.GetTranslator("en")
-		en_translations.RegisterDefaultTranslations(v, translator)
+// InitTranslator registers the validator and error message catalogs for every
+// supported locale. English is the fallback.
+func InitTranslator() *ut.UniversalTranslator {
+	english := en.New()
+	uni := ut.New(english, english, id.New())
+
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return uni
+	}
+
+	enTranslator, _ := uni.GetTranslator("en")
+	if err := en_translations.RegisterDefaultTranslations(v, enTranslator); err != nil {
+		log.Printf("failed to register en validator translations, err : %v\n", err)
+	}
+	registerCatalog(enTranslator, "en")
+
+	idTranslator, _ := uni.GetTranslator("id")
+	if err := id_translations.RegisterDefaultTranslations(v, idTranslator); err != nil {
+		log.Printf("failed to register id validator translations, err : %v\n", err)
+	}
+	registerCatalog(idTranslator, "id")
+
+	return uni
+}
+
+func registerCatalog(trans ut.Translator, locale string) {
+
+	for key, text := range messages {
+		value := text.en
+		if locale == "id" {
+			value = text.id
+		}
+
+		if err := trans.Add(key, value, false); err != nil {
+			log.Printf("failed to register message %q for %s, err : %v\n", key, locale, err)
+		}
+	}
+
+	if locale == "en" {
+		return
+	}
+
+	for phrase, translated := range phrases {
+		if err := trans.Add(phrase, translated, false); err != nil {
+			log.Printf("failed to register phrase %q for %s, err : %v\n", phrase, locale, err)
+		}
 	}
-	return translator
-}
\ No newline at end of file
+}
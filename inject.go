@@ -2,77 +2,178 @@ package main
 
 import (
 	"github.com/RuhullahReza/SecondHand/controller"
+	dbmigration "github.com/RuhullahReza/SecondHand/db"
+	"github.com/RuhullahReza/SecondHand/docs"
 	"github.com/RuhullahReza/SecondHand/helper"
 	"github.com/RuhullahReza/SecondHand/middleware"
 	"github.com/RuhullahReza/SecondHand/repository"
 	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/RuhullahReza/SecondHand/util/config"
 	"github.com/cloudinary/cloudinary-go"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 )
 
-func Inject(db *sqlx.DB, cld *cloudinary.Cloudinary) *gin.Engine {
+func Inject(db *sqlx.DB, cld *cloudinary.Cloudinary, keyring *util.Keyring, gateway util.PaymentGateway) *gin.Engine {
 
-	accountRepository := repository.NewAccountRepository(db)
-	profileRepository := repository.NewProfileRepository(db)
+	accountRepository := repository.NewAccountRepository(db, keyring)
+	profileRepository := repository.NewProfileRepository(db, keyring)
 	productRepository := repository.NewProductRepository(db)
 	dataRepository := repository.NewDataRepository(db)
 	imageRepository := repository.NewImageRepository(cld,db)
 	transactionRepostory := repository.NewTransactionRepository(db)
+	healthRepository := repository.NewHealthRepository(db)
+	reportRepository := repository.NewReportRepository(db)
+	notificationRepository := repository.NewNotificationRepository(db)
+	orderRepository := repository.NewOrderRepository(db)
+	paymentRepository := repository.NewPaymentRepository(db)
+	ledgerRepository := repository.NewLedgerRepository(db)
+	disputeRepository := repository.NewDisputeRepository(db)
+	auctionRepository := repository.NewAuctionRepository(db)
 
 	userService := service.NewUserService(accountRepository, profileRepository, imageRepository, dataRepository)
 	dataService := service.NewDataService(dataRepository)
-	productService := service.NewProductService(productRepository, profileRepository, dataRepository, imageRepository)
-	transactionService := service.NewTransactionSerive(productRepository, profileRepository, transactionRepostory)
+	productService := service.NewProductService(productRepository, profileRepository, dataRepository, imageRepository, reportRepository, disputeRepository, config.ReportThreshold())
+	transactionService := service.NewTransactionSerive(productRepository, profileRepository, transactionRepostory, disputeRepository, config.ReservationWindow())
+	accountService := service.NewAccountService(accountRepository, profileRepository, productRepository, transactionRepostory, imageRepository)
+	reportService := service.NewReportService(reportRepository, productRepository, profileRepository, transactionRepostory, accountRepository,
+		config.ReportThreshold(), config.ReportSLA())
+	jobService := newJobService(db)
+	notificationService := service.NewNotificationService(notificationRepository)
+	orderService := service.NewOrderService(orderRepository, paymentRepository, disputeRepository, gateway)
+	paymentService := service.NewPaymentService(paymentRepository, orderRepository, gateway, config.PaymentWebhookSecret(),
+		config.PlatformFeePercent())
+	ledgerService := service.NewLedgerService(ledgerRepository)
+	disputeService := service.NewDisputeService(disputeRepository, transactionRepostory, orderRepository, paymentRepository, imageRepository,
+		gateway, config.DisputeResponseWindow())
+	auctionService := service.NewAuctionService(auctionRepository, productRepository, profileRepository, disputeRepository, config.AuctionExtension())
+	healthService := service.NewHealthService(healthRepository, imageRepository, dbmigration.LatestMigrationVersion())
 
-	translator := helper.InitTranslator()
+	translators := helper.InitTranslator()
+	translator := translators.GetFallback()
 
 	userController := controller.NewUserController(userService, translator)
 	dataController := controller.NewDataController(dataService, translator)
 	productController := controller.NewProductController(productService, translator)
 	transactionController := controller.NewTransactionController(transactionService, translator)
+	accountController := controller.NewAccountController(accountService, translator)
+	reportController := controller.NewReportController(reportService, translator)
+	notificationController := controller.NewNotificationController(notificationService)
+	orderController := controller.NewOrderController(orderService, translator)
+	paymentController := controller.NewPaymentController(paymentService)
+	walletController := controller.NewWalletController(ledgerService, translator)
+	disputeController := controller.NewDisputeController(disputeService, translator)
+	auctionController := controller.NewAuctionController(auctionService, translator)
+	jobController := controller.NewJobController(jobService)
+	healthController := controller.NewHealthController(healthService)
+	docsController := controller.NewDocsController(docs.Build(docs.Routes))
 
 	router := gin.Default()
+	router.ContextWithFallback = true
 
-	router.POST("/register", userController.Register)
-	router.POST("admin/register", userController.RegisterAdmin)
-	router.POST("/login", userController.Login)
-	router.GET("/profile", middleware.Auth(), userController.MyProfile)
-	router.GET("/profile/:id",userController.Profile)
-	router.PUT("/profile", middleware.Auth(), userController.Update)
-	router.PUT("/profile_image", middleware.Auth(), userController.UpdateImage)
-
-	router.GET("/data/city", dataController.GetAllCity)
-	router.POST("/data/city", middleware.Auth(), middleware.IsAdmin(), dataController.AddCity)
-	router.DELETE("/data/city/:id", middleware.Auth(), middleware.IsAdmin(), dataController.DeleteCity)
-	router.GET("/data/category", dataController.GetAllCategory)
-	router.POST("/data/category", middleware.Auth(), middleware.IsAdmin(), dataController.AddCategory)
-	router.DELETE("/data/category/:id", middleware.Auth(), middleware.IsAdmin(), dataController.DeleteCategory)
-
-	router.POST("/product", middleware.Auth(), productController.AddProduct)
-	router.GET("/product", productController.GetAllProduct)
-	router.GET("/product/my-product", middleware.Auth(), productController.GetMyProduct)
-	router.GET("/product/id/:id", middleware.Auth(), productController.GetProductById)
-	router.GET("/product/account/:id", middleware.Auth(), productController.GetProductByAccount)
-	router.GET("/product/category/:path", middleware.Auth(), productController.GetByCategory)
-	router.PUT("/product/:id", middleware.Auth(), productController.UpdateProduct)
-	router.POST("/product/image/:id", middleware.Auth(), productController.AddProductImage)
-	router.PUT("/product/thumbnail", middleware.Auth(), productController.UpdateProductThumbnail)
-	router.PUT("/product/publish/:id", middleware.Auth(), productController.UpdatePublishStatus)
-	router.PUT("/product/status/:id", middleware.Auth(), productController.UpdateSoldStatus)
-	router.DELETE("/product/:id", middleware.Auth(), productController.DeleteProduct)
-	router.DELETE("/product/image", middleware.Auth(), productController.DeleteProductImage)
-
-	
-	router.POST("/transaction", middleware.Auth(), transactionController.AddTransaction)
-	router.GET("/transaction/offer", middleware.Auth(), transactionController.GetTransactionByAccount)
-	router.GET("/transaction/my-transaction", middleware.Auth(), transactionController.GetMyTransaction)
-	router.GET("/transaction/id/:id", middleware.Auth(), transactionController.GetTransactionDetail)
-	router.GET("/transaction/product/:id", middleware.Auth(), transactionController.GetTransactionByProduct)
-	router.GET("/transaction/buyer/:id", middleware.Auth(), transactionController.GetTransactionByBuyer)
-	router.PUT("/transaction", middleware.Auth(), transactionController.UpdatePriceOffer)
-	router.PUT("/transaction/id/:id", middleware.Auth(), transactionController.UpdateTransactionStatus)
+	router.GET("/healthz", healthController.Liveness)
+	router.GET("/readyz", healthController.Readiness)
 
+	router.GET("/openapi.json", docsController.OpenAPI)
+	router.GET("/docs", docsController.SwaggerUI)
+
+	router.Use(middleware.Tracing(), middleware.Locale(translators))
+
+	routes := func(router *gin.RouterGroup) {
+		router.POST("/register", userController.Register)
+		router.POST("/admin/register", userController.RegisterAdmin)
+		router.POST("/login", userController.Login)
+		router.GET("/profile", middleware.Auth(accountService), userController.MyProfile)
+		router.GET("/profile/:id", middleware.OptionalAuth(accountService), userController.Profile)
+		router.PUT("/profile", middleware.Auth(accountService), userController.Update)
+		router.PUT("/profile/privacy", middleware.Auth(accountService), userController.UpdatePrivacy)
+		router.PUT("/profile_image", middleware.Auth(accountService), userController.UpdateImage)
+		router.GET("/account/export", middleware.Auth(accountService), accountController.Export)
+		router.DELETE("/account", middleware.Auth(accountService), accountController.Delete)
+		router.GET("/admin/account/:id/status", middleware.Auth(accountService), middleware.IsAdmin(), accountController.GetStatus)
+		router.PUT("/admin/account/:id/status", middleware.Auth(accountService), middleware.IsAdmin(), accountController.SetStatus)
+
+		router.GET("/data/province", dataController.GetAllProvince)
+		router.GET("/data/city", dataController.GetAllCity)
+		router.POST("/data/city", middleware.Auth(accountService), middleware.IsAdmin(), dataController.AddCity)
+		router.DELETE("/data/city/:id", middleware.Auth(accountService), middleware.IsAdmin(), dataController.DeleteCity)
+		router.PUT("/data/city/:id", middleware.Auth(accountService), middleware.IsAdmin(), dataController.RenameCity)
+		router.GET("/data/city/:id/usage", middleware.Auth(accountService), middleware.IsAdmin(), dataController.CityUsage)
+		router.GET("/data/category", dataController.GetAllCategory)
+		router.POST("/data/category", middleware.Auth(accountService), middleware.IsAdmin(), dataController.AddCategory)
+		router.DELETE("/data/category/:id", middleware.Auth(accountService), middleware.IsAdmin(), dataController.DeleteCategory)
+		router.PUT("/data/category/:id", middleware.Auth(accountService), middleware.IsAdmin(), dataController.RenameCategory)
+		router.GET("/data/category/:id/usage", middleware.Auth(accountService), middleware.IsAdmin(), dataController.CategoryUsage)
+		router.PUT("/data/category/:id/approval", middleware.Auth(accountService), middleware.IsAdmin(), dataController.SetCategoryApproval)
+
+		router.POST("/product", middleware.Auth(accountService), productController.AddProduct)
+		router.GET("/product", productController.GetAllProduct)
+		router.GET("/product/my-product", middleware.Auth(accountService), productController.GetMyProduct)
+		router.GET("/product/id/:id", middleware.Auth(accountService), productController.GetProductById)
+		router.GET("/product/account/:id", middleware.Auth(accountService), productController.GetProductByAccount)
+		router.GET("/product/category/:path", middleware.Auth(accountService), productController.GetByCategory)
+		router.PUT("/product/:id", middleware.Auth(accountService), productController.UpdateProduct)
+		router.POST("/product/image/:id", middleware.Auth(accountService), productController.AddProductImage)
+		router.PUT("/product/thumbnail", middleware.Auth(accountService), productController.UpdateProductThumbnail)
+		router.PUT("/product/publish/:id", middleware.Auth(accountService), productController.UpdatePublishStatus)
+		router.PUT("/product/status/:id", middleware.Auth(accountService), productController.UpdateSoldStatus)
+		router.PUT("/product/release/:id", middleware.Auth(accountService), productController.ReleaseReservation)
+		router.DELETE("/product/:id", middleware.Auth(accountService), productController.DeleteProduct)
+		router.DELETE("/product/image", middleware.Auth(accountService), productController.DeleteProductImage)
+		router.GET("/admin/product/review", middleware.Auth(accountService), middleware.IsAdmin(), productController.GetPendingReview)
+		router.PUT("/admin/product/review/:id", middleware.Auth(accountService), middleware.IsAdmin(), productController.ReviewProduct)
+
+		router.POST("/transaction", middleware.Auth(accountService), transactionController.AddTransaction)
+		router.GET("/transaction/offer", middleware.Auth(accountService), transactionController.GetTransactionByAccount)
+		router.GET("/transaction/my-transaction", middleware.Auth(accountService), transactionController.GetMyTransaction)
+		router.GET("/transaction/id/:id", middleware.Auth(accountService), transactionController.GetTransactionDetail)
+		router.GET("/transaction/product/:id", middleware.Auth(accountService), transactionController.GetTransactionByProduct)
+		router.GET("/transaction/buyer/:id", middleware.Auth(accountService), transactionController.GetTransactionByBuyer)
+		router.PUT("/transaction", middleware.Auth(accountService), transactionController.UpdatePriceOffer)
+		router.PUT("/transaction/id/:id", middleware.Auth(accountService), transactionController.UpdateTransactionStatus)
+		router.POST("/transaction/id/:id/counter", middleware.Auth(accountService), transactionController.CounterOffer)
+		router.POST("/transaction/id/:id/counter/accept", middleware.Auth(accountService), transactionController.AcceptCounter)
+
+		router.GET("/order", middleware.Auth(accountService), orderController.GetMine)
+		router.GET("/order/:id", middleware.Auth(accountService), orderController.GetById)
+		router.PUT("/order/:id", middleware.Auth(accountService), orderController.UpdateStatus)
+		router.POST("/order/:id/pay", middleware.Auth(accountService), paymentController.Pay)
+		router.POST("/payment/webhook", paymentController.Webhook)
+
+		router.GET("/wallet", middleware.Auth(accountService), walletController.GetWallet)
+		router.GET("/wallet/statement", middleware.Auth(accountService), walletController.GetStatement)
+		router.POST("/wallet/payout", middleware.Auth(accountService), walletController.RequestPayout)
+		router.GET("/wallet/payout", middleware.Auth(accountService), walletController.GetMyPayouts)
+		router.GET("/admin/payout", middleware.Auth(accountService), middleware.IsAdmin(), walletController.GetPayouts)
+		router.PUT("/admin/payout/:id", middleware.Auth(accountService), middleware.IsAdmin(), walletController.ReviewPayout)
+
+		router.POST("/transaction/id/:id/dispute", middleware.Auth(accountService), disputeController.Open)
+		router.GET("/dispute", middleware.Auth(accountService), disputeController.GetMine)
+		router.GET("/dispute/:id", middleware.Auth(accountService), disputeController.GetById)
+		router.POST("/dispute/:id/message", middleware.Auth(accountService), disputeController.AddMessage)
+		router.POST("/dispute/:id/evidence", middleware.Auth(accountService), disputeController.AddEvidence)
+		router.GET("/admin/dispute", middleware.Auth(accountService), middleware.IsAdmin(), disputeController.GetQueue)
+		router.PUT("/admin/dispute/:id", middleware.Auth(accountService), middleware.IsAdmin(), disputeController.Resolve)
+
+		router.POST("/product/auction/:id", middleware.Auth(accountService), auctionController.Create)
+		router.GET("/auction", auctionController.GetOpen)
+		router.GET("/auction/:id", middleware.Auth(accountService), auctionController.GetById)
+		router.POST("/auction/:id/bid", middleware.Auth(accountService), auctionController.Bid)
+		router.PUT("/auction/:id/cancel", middleware.Auth(accountService), auctionController.Cancel)
+
+		router.POST("/report/product/:id", middleware.Auth(accountService), reportController.ReportProduct)
+		router.POST("/report/profile/:id", middleware.Auth(accountService), reportController.ReportProfile)
+		router.POST("/report/transaction/:id", middleware.Auth(accountService), reportController.ReportTransaction)
+		router.GET("/admin/report", middleware.Auth(accountService), middleware.IsAdmin(), reportController.GetQueue)
+		router.PUT("/admin/report/:id", middleware.Auth(accountService), middleware.IsAdmin(), reportController.Resolve)
+
+		router.GET("/notification", middleware.Auth(accountService), notificationController.GetMine)
+		router.GET("/admin/job/run", middleware.Auth(accountService), middleware.IsAdmin(), jobController.GetRuns)
+	}
+
+	routes(router.Group("/", middleware.Deprecated("/api/v1/")))
+	routes(router.Group("/api/v1", middleware.APIVersion("v1")))
 
 	return router
 }
\ No newline at end of file
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/RuhullahReza/SecondHand/docs"
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/cloudinary/cloudinary-go"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	key := []byte(strings.Repeat("k", 32))
+	keyring, err := util.NewKeyring(map[string][]byte{"test": key}, "test", key)
+	if err != nil {
+		panic(err)
+	}
+
+	return Inject(sqlx.NewDb(&sql.DB{}, "postgres"), &cloudinary.Cloudinary{}, keyring, util.NewFakeGateway())
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	router := newTestRouter()
+
+	served := map[string]bool{}
+	for _, route := range router.Routes() {
+		served[route.Method+" "+docs.OpenAPIPath(route.Path)] = true
+	}
+
+	missing, stale := docs.Build(docs.Routes).Undocumented(served)
+	require.Empty(t, missing, "routes registered in Inject but missing from docs.Routes")
+	require.Empty(t, stale, "routes in docs.Routes that Inject no longer registers")
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	router := newTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var document docs.Document
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+	require.Equal(t, "3.0.3", document.OpenAPI)
+
+	register := (*document.Paths["/register"])["post"]
+	require.NotNil(t, register)
+
+	schema := document.Components.Schemas["CreateUserRequest"]
+	require.ElementsMatch(t, []string{"name", "email", "password"}, schema.Required)
+	require.Equal(t, "email", schema.Properties["email"].Format)
+	require.Equal(t, 5, *schema.Properties["password"].MinLength)
+	require.Equal(t, 255, *schema.Properties["password"].MaxLength)
+
+	_, documentedBuyer := document.Components.Schemas["TransactionRequest"].Properties["buyer_id"]
+	require.False(t, documentedBuyer)
+}
+
+func TestVersionedErrorEnvelope(t *testing.T) {
+	router := newTestRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Empty(t, w.Header().Get("Deprecation"))
+	require.JSONEq(t, `{"error":{"code":"AUTHORIZATION","message":"token required"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/profile", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Equal(t, "true", w.Header().Get("Deprecation"))
+	require.Equal(t, `</api/v1/profile>; rel="successor-version"`, w.Header().Get("Link"))
+	require.JSONEq(t, `{"error":{"type":"AUTHORIZATION","message":"token required"}}`, w.Body.String())
+}
+
+func TestLocalizedErrors(t *testing.T) {
+	router := newTestRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil)
+	req.Header.Set("Accept-Language", "id-ID,id;q=0.9,en;q=0.8")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, "id", w.Header().Get("Content-Language"))
+	require.JSONEq(t, `{"error":{"code":"AUTHORIZATION","message":"token diperlukan"}}`, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/register", strings.NewReader(`{"email":"ozza@gmail.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "id")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	var res struct {
+		Error helper.ErrorDetail `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Equal(t, "Permintaan tidak valid. Alasan: Parameter permintaan tidak valid. Lihat fields", res.Error.Message)
+	require.Equal(t, "name", res.Error.Fields[0].Field)
+	require.Equal(t, "required", res.Error.Fields[0].Code)
+	require.Equal(t, "Name wajib diisi", res.Error.Fields[0].Message)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil)
+	req.Header.Set("Accept-Language", "fr")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, "en", w.Header().Get("Content-Language"))
+	require.JSONEq(t, `{"error":{"code":"AUTHORIZATION","message":"token required"}}`, w.Body.String())
+}
+
+func TestWebhookRejectsBadSignature(t *testing.T) {
+	router := newTestRouter()
+
+	body := `{"id":"evt_1","type":"charge.authorized","charge_id":"ch_1"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/payment/webhook", strings.NewReader(body))
+	req.Header.Set("X-Signature", "not-a-signature")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.JSONEq(t, `{"error":{"code":"AUTHORIZATION","message":"invalid signature"}}`, w.Body.String())
+}
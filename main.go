@@ -1,17 +1,78 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/RuhullahReza/SecondHand/db"
+	"github.com/RuhullahReza/SecondHand/util/config"
 )
 
 
 func main() {
-	
+
+	if len(os.Args) > 1 && os.Args[1] == "reencrypt" {
+		DB := db.NewPostgresConnection()
+		reencrypt(DB, db.NewKeyring())
+		DB.Close()
+		return
+	}
+
+	tp := db.NewTracerProvider()
+
 	DB := db.NewPostgresConnection()
 	cld := db.NewCloudinaryConnection()
-	app := Inject(DB,cld)
+	keyring := db.NewKeyring()
+	gateway := db.NewPaymentGateway()
+	app := Inject(DB,cld,keyring,gateway)
 
-	app.Run(":3000")
-}
+	server := &http.Server{
+		Addr:         config.ServerAddress(),
+		Handler:      app,
+		ReadTimeout:  config.ServerReadTimeout(),
+		WriteTimeout: config.ServerWriteTimeout(),
+		IdleTimeout:  config.ServerIdleTimeout(),
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	jobs := schedule(ctx, DB)
 
+	go func() {
+		log.Printf("listening on %s\n", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("failed to start server, err : %v\n", err)
+		}
+	}()
 
+	<-ctx.Done()
+	stop()
+	log.Println("shutting down, draining in-flight requests")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ServerShutdownTimeout())
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to shutdown server gracefully, err : %v\n", err)
+	}
+
+	select {
+	case <-jobs:
+	case <-shutdownCtx.Done():
+		log.Println("background jobs did not stop before the shutdown timeout")
+	}
+
+	if err := DB.Close(); err != nil {
+		log.Printf("failed to close database, err : %v\n", err)
+	}
+
+	if err := tp.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to flush traces, err : %v\n", err)
+	}
+}
@@ -17,11 +17,7 @@ func IsAdmin() gin.HandlerFunc {
 		role := payload.(helper.Payload).Role
 
 		if role != "ADMIN" {
-			err := helper.NewAuthorization("only admin can access")
-
-			c.JSON(err.Status(), gin.H{
-				"error": err,
-			})
+			helper.WriteError(c, helper.NewAuthorization("only admin can access"))
 			c.Abort()
 			return
 		}
@@ -1,21 +1,26 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/RuhullahReza/SecondHand/helper"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-func Auth() gin.HandlerFunc {
+// StatusChecker tells whether the account behind a valid token may still act,
+// it is satisfied by service.AccountService
+type StatusChecker interface {
+	CheckStatus(ctx context.Context, id uuid.UUID) error
+}
+
+func Auth(checker StatusChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		token, err := c.Cookie("token")
 
 		if err != nil {
-			err := helper.NewAuthorization("token required")
-
-			c.JSON(err.Status(), gin.H{
-				"error": err,
-			})
+			helper.WriteError(c, helper.NewAuthorization("token required"))
 			c.Abort()
 			return
 		}
@@ -23,11 +28,13 @@ func Auth() gin.HandlerFunc {
 		payload, ok := helper.ValidateToken(token)
 
 		if !ok {
-			err := helper.NewAuthorization("invalid token")
+			helper.WriteError(c, helper.NewAuthorization("invalid token"))
+			c.Abort()
+			return
+		}
 
-			c.JSON(err.Status(), gin.H{
-				"error": err,
-			})
+		if err := checker.CheckStatus(c, payload.UserId); err != nil {
+			helper.WriteError(c, err)
 			c.Abort()
 			return
 		}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/gin-gonic/gin"
+	ut "github.com/go-playground/universal-translator"
+	"golang.org/x/text/language"
+)
+
+var supportedLocales = language.NewMatcher([]language.Tag{
+	language.English,
+	language.Indonesian,
+})
+
+// Locale picks English or Bahasa Indonesia from Accept-Language and stores the
+// matching translator for BindData and the error writers
+func Locale(uni *ut.UniversalTranslator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		tag, _ := language.MatchStrings(supportedLocales, c.GetHeader("Accept-Language"))
+		base, _ := tag.Base()
+
+		trans, found := uni.GetTranslator(base.String())
+		if !found {
+			trans = uni.GetFallback()
+		}
+
+		c.Set(helper.LocaleKey, trans.Locale())
+		c.Set(helper.TranslatorKey, trans)
+		c.Header("Content-Language", trans.Locale())
+		c.Header("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
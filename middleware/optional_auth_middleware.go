@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/gin-gonic/gin"
+)
+
+// OptionalAuth sets the payload like Auth when a valid token cookie of an
+// account in good standing is sent and lets anonymous requests through otherwise
+func OptionalAuth(checker StatusChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		token, err := c.Cookie("token")
+
+		if err == nil {
+			if payload, ok := helper.ValidateToken(token); ok && checker.CheckStatus(c, payload.UserId) == nil {
+				c.Set("payload", payload)
+			}
+		}
+
+		c.Next()
+
+	}
+}
@@ -0,0 +1,11 @@
+package middleware
+
+import (
+	"github.com/RuhullahReza/SecondHand/util/config"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+func Tracing() gin.HandlerFunc {
+	return otelgin.Middleware(config.TracingServiceName())
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/util/config"
+	"github.com/gin-gonic/gin"
+)
+
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(helper.APIVersionKey, version)
+		c.Next()
+	}
+}
+
+// Deprecated marks the unversioned routes, pointing clients at the /api/v1
+// equivalent. The Sunset header is only sent once a date is configured.
+func Deprecated(successor string) gin.HandlerFunc {
+
+	sunset := config.LegacySunset()
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		if sunset != "" {
+			c.Header("Sunset", sunset)
+		}
+
+		path := strings.TrimPrefix(c.Request.URL.Path, "/")
+		c.Header("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor, path))
+
+		c.Next()
+	}
+}
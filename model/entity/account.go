@@ -1,16 +1,40 @@
 package entity
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Account statuses set by admins. A suspension lapses on its own once
+// SuspendedUntil has passed.
+const (
+	AccountActive    = "ACTIVE"
+	AccountSuspended = "SUSPENDED"
+	AccountBanned    = "BANNED"
+)
+
 type Account struct {
 	Id		    uuid.UUID `db:"id" json:"id"`
 	Email    	string    `db:"email" json:"email"`
+	EmailIndex 	sql.NullString `db:"email_index" json:"-"`
 	Password 	string    `db:"password" json:"password"`
 	Role 		string    `db:"role" json:"role"`
 	CreatedAt 	time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt 	time.Time `db:"updated_at" json:"updated_at"`
+	DeletedAt 	sql.NullTime `db:"deleted_at" json:"-"`
+	Status 		string 		`db:"status" json:"status"`
+	SuspendedUntil 	sql.NullTime 	`db:"suspended_until" json:"suspended_until"`
+	StatusReason 	sql.NullString 	`db:"status_reason" json:"status_reason"`
+}
+
+type AccountStatusChange struct {
+	Id 				uuid.UUID 		`db:"id" json:"id"`
+	AccountId 		uuid.UUID 		`db:"account_id" json:"account_id"`
+	Status 			string 			`db:"status" json:"status"`
+	SuspendedUntil 	sql.NullTime 	`db:"suspended_until" json:"suspended_until"`
+	Reason 			string 			`db:"reason" json:"reason"`
+	AdminId 		uuid.UUID 		`db:"admin_id" json:"admin_id"`
+	CreatedAt 		time.Time 		`db:"created_at" json:"created_at"`
 }
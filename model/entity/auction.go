@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of an auction. An ended auction is SOLD when its high bid reached
+// the reserve price and UNSOLD otherwise.
+const (
+	AuctionOpen      = "OPEN"
+	AuctionSold      = "SOLD"
+	AuctionUnsold    = "UNSOLD"
+	AuctionCancelled = "CANCELLED"
+)
+
+// Auction sells a product to the highest bidder at EndsAt. The seller sees
+// ReservePrice, bidders only whether it was met.
+type Auction struct {
+	Id            uuid.UUID     `db:"id" json:"id"`
+	ProductId     uuid.UUID     `db:"product_id" json:"product_id"`
+	SellerId      uuid.UUID     `db:"seller_id" json:"seller_id"`
+	StartPrice    int64         `db:"start_price" json:"start_price"`
+	ReservePrice  sql.NullInt64 `db:"reserve_price" json:"reserve_price"`
+	MinIncrement  int64         `db:"min_increment" json:"min_increment"`
+	Status        string        `db:"status" json:"status"`
+	HighBid       sql.NullInt64 `db:"high_bid" json:"high_bid"`
+	HighBidderId  uuid.NullUUID `db:"high_bidder_id" json:"high_bidder_id"`
+	BidCount      int           `db:"bid_count" json:"bid_count"`
+	EndsAt        time.Time     `db:"ends_at" json:"ends_at"`
+	TransactionId uuid.NullUUID `db:"transaction_id" json:"transaction_id"`
+	CreatedAt     time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time     `db:"updated_at" json:"updated_at"`
+	ClosedAt      sql.NullTime  `db:"closed_at" json:"closed_at"`
+}
+
+type Bid struct {
+	Id        uuid.UUID `db:"id" json:"id"`
+	AuctionId uuid.UUID `db:"auction_id" json:"auction_id"`
+	BidderId  uuid.UUID `db:"bidder_id" json:"bidder_id"`
+	Amount    int64     `db:"amount" json:"amount"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
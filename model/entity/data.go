@@ -5,14 +5,25 @@ import (
 	"github.com/google/uuid"
 )
 
-type City struct {
+type Province struct {
 	Id		    uuid.UUID 	`db:"id" json:"id"`
 	Name    	string   	`db:"name" json:"name"`
 	CreatedAt 	time.Time 	`db:"created_at" json:"created_at"`
 }
 
+type City struct {
+	Id		    uuid.UUID 		`db:"id" json:"id"`
+	Name    	string   		`db:"name" json:"name"`
+	ProvinceId 	uuid.NullUUID 	`db:"province_id" json:"province_id"`
+	CreatedAt 	time.Time 		`db:"created_at" json:"created_at"`
+}
+
 type Category struct {
-	Id		    uuid.UUID 	`db:"id" json:"id"`
-	Name    	string   	`db:"name" json:"name"`
-	CreatedAt 	time.Time 	`db:"created_at" json:"created_at"`
+	Id		    uuid.UUID 		`db:"id" json:"id"`
+	Name    	string   		`db:"name" json:"name"`
+	Slug    	string   		`db:"slug" json:"slug"`
+	ParentId 	uuid.NullUUID 	`db:"parent_id" json:"parent_id"`
+	SortOrder 	int 			`db:"sort_order" json:"sort_order"`
+	RequiresApproval 	bool 	`db:"requires_approval" json:"requires_approval"`
+	CreatedAt 	time.Time 		`db:"created_at" json:"created_at"`
 }
\ No newline at end of file
@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of a dispute. An OPEN dispute waits for the buyer or the seller, an
+// ESCALATED one for an admin. A RESOLVING dispute is claimed by an admin while
+// the payment provider moves the money.
+const (
+	DisputeOpen      = "OPEN"
+	DisputeEscalated = "ESCALATED"
+	DisputeResolving = "RESOLVING"
+	DisputeResolved  = "RESOLVED"
+)
+
+// Admin resolutions of a dispute
+const (
+	DisputeRefund        = "REFUND"
+	DisputePartialRefund = "PARTIAL_REFUND"
+	DisputeReject        = "REJECT"
+)
+
+// Dispute is opened by the buyer of a paid order. Awaiting is the party that
+// has to answer before RespondBy.
+type Dispute struct {
+	Id             uuid.UUID      `db:"id" json:"id"`
+	TransactionId  uuid.UUID      `db:"transaction_id" json:"transaction_id"`
+	OrderId        uuid.UUID      `db:"order_id" json:"order_id"`
+	ProductId      uuid.UUID      `db:"product_id" json:"product_id"`
+	BuyerId        uuid.UUID      `db:"buyer_id" json:"buyer_id"`
+	SellerId       uuid.UUID      `db:"seller_id" json:"seller_id"`
+	Reason         string         `db:"reason" json:"reason"`
+	Details        string         `db:"details" json:"details"`
+	Status         string         `db:"status" json:"status"`
+	Awaiting       sql.NullString `db:"awaiting" json:"awaiting"`
+	RespondBy      sql.NullTime   `db:"respond_by" json:"respond_by"`
+	Resolution     sql.NullString `db:"resolution" json:"resolution"`
+	RefundAmount   sql.NullInt64  `db:"refund_amount" json:"refund_amount"`
+	ResolutionNote string         `db:"resolution_note" json:"resolution_note"`
+	ResolvedBy     uuid.NullUUID  `db:"resolved_by" json:"resolved_by"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	EscalatedAt    sql.NullTime   `db:"escalated_at" json:"escalated_at"`
+	ResolvedAt     sql.NullTime   `db:"resolved_at" json:"resolved_at"`
+}
+
+// DisputeMessage is a message or a piece of evidence in a dispute
+type DisputeMessage struct {
+	Id        uuid.UUID      `db:"id" json:"id"`
+	DisputeId uuid.UUID      `db:"dispute_id" json:"dispute_id"`
+	AuthorId  uuid.UUID      `db:"author_id" json:"author_id"`
+	Body      string         `db:"body" json:"body"`
+	ImageUrl  sql.NullString `db:"image_url" json:"image_url"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Background jobs run by the scheduler
+const (
+	JobExpireOffers     = "expire_offers"
+	JobUnpublishStale   = "unpublish_stale_listings"
+	JobRemindSellers    = "remind_sellers"
+	JobEndReservations  = "end_reservations"
+	JobEscalateDisputes = "escalate_disputes"
+	JobCloseAuctions    = "close_auctions"
+)
+
+const (
+	JobRunning   = "RUNNING"
+	JobSucceeded = "SUCCEEDED"
+	JobFailed    = "FAILED"
+)
+
+// JobRun is one run of a background job on whichever replica held its lock.
+// Affected counts the offers, listings or sellers the run acted on.
+type JobRun struct {
+	Id         uuid.UUID      `db:"id" json:"id"`
+	Job        string         `db:"job" json:"job"`
+	Status     string         `db:"status" json:"status"`
+	Affected   int64          `db:"affected" json:"affected"`
+	Error      sql.NullString `db:"error" json:"error"`
+	StartedAt  time.Time      `db:"started_at" json:"started_at"`
+	FinishedAt sql.NullTime   `db:"finished_at" json:"finished_at"`
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of ledger accounts. Every kind but SELLER_AVAILABLE is one platform
+// account, each seller has their own SELLER_AVAILABLE account.
+const (
+	LedgerGateway         = "GATEWAY"
+	LedgerEscrow          = "ESCROW"
+	LedgerFees            = "FEES"
+	LedgerPayoutsPending  = "PAYOUTS_PENDING"
+	LedgerSellerAvailable = "SELLER_AVAILABLE"
+)
+
+// Kinds of journal entries
+const (
+	EntryPaymentHeld     = "PAYMENT_HELD"
+	EntrySaleReleased    = "SALE_RELEASED"
+	EntryPaymentRefunded = "PAYMENT_REFUNDED"
+	EntryPayoutRequested = "PAYOUT_REQUESTED"
+	EntryPayoutApproved  = "PAYOUT_APPROVED"
+	EntryPayoutRejected  = "PAYOUT_REJECTED"
+)
+
+// Statuses of a payout
+const (
+	PayoutRequested = "REQUESTED"
+	PayoutApproved  = "APPROVED"
+	PayoutRejected  = "REJECTED"
+)
+
+// Posting is one leg of a journal entry, a positive amount credits the
+// account of Kind owned by OwnerId
+type Posting struct {
+	Kind    string
+	OwnerId uuid.NullUUID
+	Amount  int64
+}
+
+type Payout struct {
+	Id          uuid.UUID      `db:"id" json:"id"`
+	SellerId    uuid.UUID      `db:"seller_id" json:"seller_id"`
+	Amount      int64          `db:"amount" json:"amount"`
+	Destination string         `db:"destination" json:"destination"`
+	Status      string         `db:"status" json:"status"`
+	Reason      sql.NullString `db:"reason" json:"reason"`
+	ReviewedBy  uuid.NullUUID  `db:"reviewed_by" json:"reviewed_by"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	ReviewedAt  sql.NullTime   `db:"reviewed_at" json:"reviewed_at"`
+}
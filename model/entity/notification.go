@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NotificationOfferExpired       = "OFFER_EXPIRED"
+	NotificationOfferReminder      = "OFFER_REMINDER"
+	NotificationListingUnpublished = "LISTING_UNPUBLISHED"
+	NotificationReservationEnded   = "RESERVATION_ENDED"
+	NotificationOrderUpdated       = "ORDER_UPDATED"
+	NotificationPayoutReviewed     = "PAYOUT_REVIEWED"
+	NotificationDisputeUpdated     = "DISPUTE_UPDATED"
+	NotificationOutbid             = "OUTBID"
+	NotificationAuctionEnded       = "AUCTION_ENDED"
+)
+
+type Notification struct {
+	Id        uuid.UUID `db:"id" json:"id"`
+	AccountId uuid.UUID `db:"account_id" json:"account_id"`
+	Kind      string    `db:"kind" json:"kind"`
+	Message   string    `db:"message" json:"message"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
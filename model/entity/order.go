@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of an order, in the order they are reached
+const (
+	OrderAwaitingPayment = "AWAITING_PAYMENT"
+	OrderPaid            = "PAID"
+	OrderShipped         = "SHIPPED"
+	OrderDelivered       = "DELIVERED"
+	OrderCompleted       = "COMPLETED"
+	OrderCancelled       = "CANCELLED"
+)
+
+// Order follows an accepted offer from payment to the handover of the product
+type Order struct {
+	Id             uuid.UUID      `db:"id" json:"id"`
+	TransactionId  uuid.UUID      `db:"transaction_id" json:"transaction_id"`
+	ProductId      uuid.UUID      `db:"product_id" json:"product_id"`
+	BuyerId        uuid.UUID      `db:"buyer_id" json:"buyer_id"`
+	SellerId       uuid.UUID      `db:"seller_id" json:"seller_id"`
+	Price          int64          `db:"price" json:"price"`
+	Status         string         `db:"status" json:"status"`
+	Carrier        sql.NullString `db:"carrier" json:"carrier"`
+	TrackingNumber sql.NullString `db:"tracking_number" json:"tracking_number"`
+	CancelReason   sql.NullString `db:"cancel_reason" json:"cancel_reason"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at" json:"updated_at"`
+	PaidAt         sql.NullTime   `db:"paid_at" json:"paid_at"`
+	ShippedAt      sql.NullTime   `db:"shipped_at" json:"shipped_at"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at" json:"delivered_at"`
+	CompletedAt    sql.NullTime   `db:"completed_at" json:"completed_at"`
+	CancelledAt    sql.NullTime   `db:"cancelled_at" json:"cancelled_at"`
+	Settling       sql.NullString `db:"settling" json:"settling"`
+}
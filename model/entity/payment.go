@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Escrow statuses of a payment. HELD money waits at the provider until the
+// buyer confirms the delivery, then it is RELEASED to the seller.
+const (
+	PaymentPending   = "PENDING"
+	PaymentHeld      = "HELD"
+	PaymentReleased  = "RELEASED"
+	PaymentRefunded  = "REFUNDED"
+	PaymentFailed    = "FAILED"
+	PaymentCancelled = "CANCELLED"
+)
+
+// Webhook event types sent by payment providers
+const (
+	EventChargeAuthorized = "charge.authorized"
+	EventChargeFailed     = "charge.failed"
+	EventChargeCaptured   = "charge.captured"
+	EventChargeRefunded   = "charge.refunded"
+)
+
+// Payment is a charge paying for the order of an accepted transaction
+type Payment struct {
+	Id             uuid.UUID    `db:"id" json:"id"`
+	TransactionId  uuid.UUID    `db:"transaction_id" json:"transaction_id"`
+	OrderId        uuid.UUID    `db:"order_id" json:"order_id"`
+	Provider       string       `db:"provider" json:"provider"`
+	ChargeId       string       `db:"charge_id" json:"charge_id"`
+	Amount         int64        `db:"amount" json:"amount"`
+	Fee            int64        `db:"fee" json:"fee"`
+	RefundedAmount int64        `db:"refunded_amount" json:"refunded_amount"`
+	Status         string       `db:"status" json:"status"`
+	PaymentUrl     string       `db:"payment_url" json:"payment_url"`
+	CreatedAt      time.Time    `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at" json:"updated_at"`
+	HeldAt         sql.NullTime `db:"held_at" json:"held_at"`
+	ReleasedAt     sql.NullTime `db:"released_at" json:"released_at"`
+	RefundedAt     sql.NullTime `db:"refunded_at" json:"refunded_at"`
+}
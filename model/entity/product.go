@@ -1,17 +1,27 @@
 package entity
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Review states of a listing. Listings in a category that requires approval
+// move DRAFT -> PENDING -> APPROVED or REJECTED, everything else is APPROVED.
+const (
+	ApprovalDraft    = "DRAFT"
+	ApprovalPending  = "PENDING"
+	ApprovalApproved = "APPROVED"
+	ApprovalRejected = "REJECTED"
+)
+
 type Product struct {
 	Id          uuid.UUID `db:"id" json:"id"`
 	AccountId   uuid.UUID `db:"account_id" json:"account_id"`
 	Name        string    `db:"name" json:"name"`
 	Price       int64     `db:"price" json:"price"`
-	Category    string    `db:"category" json:"category"`
+	CategoryId  uuid.UUID `db:"category_id" json:"category_id"`
 	Description string    `db:"description" json:"description"`
 	Thumbnail 	string    `db:"thumbnail" json:"thumbnail"`
 	Sold		bool      `db:"sold" json:"sold"`
@@ -19,4 +29,12 @@ type Product struct {
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 	Deleted		bool      `db:"deleted" json:"deleted"`
-}
\ No newline at end of file
+	Hidden		bool      `db:"hidden" json:"hidden"`
+	ApprovalStatus 	string 	`db:"approval_status" json:"approval_status"`
+	MinPrice 		sql.NullInt64 	`db:"min_price" json:"min_price"`
+	AutoAcceptPrice sql.NullInt64 	`db:"auto_accept_price" json:"auto_accept_price"`
+	ReservedFor 	uuid.NullUUID 	`db:"reserved_for" json:"reserved_for"`
+	ReservedUntil 	sql.NullTime 	`db:"reserved_until" json:"reserved_until"`
+	// not a column, GetState tells whether an auction of the product is open
+	Auctioned 		bool 			`db:"auctioned" json:"-"`
+}
@@ -5,12 +5,27 @@ import (
 	"github.com/google/uuid"
 )
 
+// Who may see a profile's phone number or address besides its owner and admins
+const (
+	VisibilityPublic         = "PUBLIC"
+	VisibilityAcceptedBuyers = "ACCEPTED_BUYERS"
+	VisibilityNobody         = "NOBODY"
+)
+
+// DeletedName replaces the name of a deleted account's profile, which stays
+// behind for the counterparties of its transactions
+const DeletedName = "Deleted user"
+
 type Profile struct {
 	Id		    uuid.UUID 	`db:"id" json:"id"`
 	Name    	string   	`db:"name" json:"name"`
 	City 		string   	`db:"city" json:"city"`
 	Address 	string   	`db:"address" json:"address"`
 	PhoneNumber string   	`db:"phone_number" json:"phone_number"`
+	Latitude 	*float64 	`db:"latitude" json:"latitude"`
+	Longitude 	*float64 	`db:"longitude" json:"longitude"`
+	PhoneVisibility 	string 	`db:"phone_visibility" json:"phone_visibility"`
+	AddressVisibility 	string 	`db:"address_visibility" json:"address_visibility"`
 	ImageUrl 	string   	`db:"image_url" json:"image_url"`
 	CreatedAt 	time.Time 	`db:"created_at" json:"created_at"`
 	UpdatedAt 	time.Time 	`db:"updated_at" json:"updated_at"`
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// What a report points at
+const (
+	ReportProduct     = "PRODUCT"
+	ReportProfile     = "PROFILE"
+	ReportTransaction = "TRANSACTION"
+)
+
+const (
+	ReportOpen      = "OPEN"
+	ReportDismissed = "DISMISSED"
+	ReportActioned  = "ACTIONED"
+)
+
+// Moderator decisions on a report
+const (
+	ReportDismiss     = "DISMISS"
+	ReportHideListing = "HIDE_LISTING"
+	ReportSuspendUser = "SUSPEND_USER"
+)
+
+type Report struct {
+	Id 					uuid.UUID 		`db:"id" json:"id"`
+	ReporterId 			uuid.UUID 		`db:"reporter_id" json:"reporter_id"`
+	TargetType 			string 			`db:"target_type" json:"target_type"`
+	TargetId 			uuid.UUID 		`db:"target_id" json:"target_id"`
+	ReportedAccountId 	uuid.UUID 		`db:"reported_account_id" json:"reported_account_id"`
+	Reason 				string 			`db:"reason" json:"reason"`
+	Details 			string 			`db:"details" json:"details"`
+	Status 				string 			`db:"status" json:"status"`
+	Action 				sql.NullString 	`db:"action" json:"action"`
+	Note 				string 			`db:"note" json:"note"`
+	ResolvedBy 			uuid.NullUUID 	`db:"resolved_by" json:"resolved_by"`
+	CreatedAt 			time.Time 		`db:"created_at" json:"created_at"`
+	DueAt 				time.Time 		`db:"due_at" json:"due_at"`
+	ResolvedAt 			sql.NullTime 	`db:"resolved_at" json:"resolved_at"`
+}
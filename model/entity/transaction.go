@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,4 +17,24 @@ type Transaction struct {
 	CreatedAt   time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
 	Deleted     bool      `db:"deleted" json:"deleted"`
-}
\ No newline at end of file
+	ExpiredAt   sql.NullTime `db:"expired_at" json:"expired_at"`
+	RemindedAt  sql.NullTime `db:"reminded_at" json:"reminded_at"`
+}
+
+// Sides of an offer proposing a price
+const (
+	ProposalBuyer  = "BUYER"
+	ProposalSeller = "SELLER"
+)
+
+// OfferProposal is one price proposed on an offer. Proposals are never
+// changed, together they are the negotiation history of the offer.
+type OfferProposal struct {
+	Id            uuid.UUID `db:"id" json:"id"`
+	TransactionId uuid.UUID `db:"transaction_id" json:"transaction_id"`
+	ProposedBy    uuid.UUID `db:"proposed_by" json:"proposed_by"`
+	Role          string    `db:"role" json:"role"`
+	Amount        int64     `db:"amount" json:"amount"`
+	Note          string    `db:"note" json:"note"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
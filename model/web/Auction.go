@@ -0,0 +1,58 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuctionRequest auctions a product until EndsAt. Below ReservePrice, hidden
+// from bidders, the product is not sold.
+type AuctionRequest struct {
+	ProductId 		uuid.UUID 	`json:"-" openapi:"-"`
+	SellerId 		uuid.UUID 	`json:"-" openapi:"-"`
+	StartPrice 		int64 		`json:"start_price" binding:"required,gt=0"`
+	ReservePrice 	*int64 		`json:"reserve_price" binding:"omitempty,gt=0"`
+	MinIncrement 	int64 		`json:"min_increment" binding:"required,gt=0"`
+	EndsAt 			time.Time 	`json:"ends_at" binding:"required"`
+}
+
+type BidRequest struct {
+	AuctionId 	uuid.UUID 	`json:"-" openapi:"-"`
+	BidderId 	uuid.UUID 	`json:"-" openapi:"-"`
+	Amount 		int64 		`json:"amount" binding:"required,gt=0"`
+}
+
+// AuctionResponse is an auction with its product. MinimumBid is what the next
+// bid has to reach, ReservePrice is only shown to the seller and Bids only on
+// a single auction, the latest first.
+type AuctionResponse struct {
+	Id 				uuid.UUID 	`db:"id" json:"id"`
+	ProductId 		uuid.UUID 	`db:"product_id" json:"product_id"`
+	ProductName 	string 		`db:"product_name" json:"product_name"`
+	Thumbnail 		string 		`db:"thumbnail" json:"thumbnail"`
+	SellerId 		uuid.UUID 	`db:"seller_id" json:"seller_id"`
+	SellerName 		string 		`db:"seller_name" json:"seller_name"`
+	StartPrice 		int64 		`db:"start_price" json:"start_price"`
+	ReservePrice 	*int64 		`db:"reserve_price" json:"reserve_price,omitempty"`
+	ReserveMet 		bool 		`db:"reserve_met" json:"reserve_met"`
+	MinIncrement 	int64 		`db:"min_increment" json:"min_increment"`
+	HighBid 		*int64 		`db:"high_bid" json:"high_bid,omitempty"`
+	HighBidderId 	*uuid.UUID 	`db:"high_bidder_id" json:"high_bidder_id,omitempty"`
+	MinimumBid 		int64 		`db:"minimum_bid" json:"minimum_bid"`
+	BidCount 		int 		`db:"bid_count" json:"bid_count"`
+	Status 			string 		`db:"status" json:"status"`
+	EndsAt 			time.Time 	`db:"ends_at" json:"ends_at"`
+	TransactionId 	*uuid.UUID 	`db:"transaction_id" json:"transaction_id,omitempty"`
+	CreatedAt 		time.Time 	`db:"created_at" json:"created_at"`
+	ClosedAt 		*time.Time 	`db:"closed_at" json:"closed_at,omitempty"`
+	Bids 			[]BidResponse 	`db:"-" json:"bids,omitempty"`
+}
+
+type BidResponse struct {
+	Id 			uuid.UUID 	`db:"id" json:"id"`
+	BidderId 	uuid.UUID 	`db:"bidder_id" json:"bidder_id"`
+	BidderName 	string 		`db:"bidder_name" json:"bidder_name"`
+	Amount 		int64 		`db:"amount" json:"amount"`
+	CreatedAt 	time.Time 	`db:"created_at" json:"created_at"`
+}
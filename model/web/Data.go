@@ -11,10 +11,84 @@ type DataResponse struct {
 	Name string    `db:"name" json:"name"`
 }
 
+type CreateCityRequest struct {
+	Name 		string 		`json:"name" binding:"required" conform:"name"`
+	ProvinceId 	uuid.UUID 	`json:"province_id" binding:"required"`
+}
+
+type CityResponse struct {
+	Id   		uuid.UUID 	`db:"id" json:"id"`
+	Name 		string    	`db:"name" json:"name"`
+	ProvinceId 	*uuid.UUID 	`db:"province_id" json:"province_id"`
+	Province 	string 		`db:"province" json:"province"`
+}
+
+type CreateCategoryRequest struct {
+	Name 		string 		`json:"name" binding:"required" conform:"name"`
+	Slug 		string 		`json:"slug" binding:"omitempty,lowercase" conform:"trim"`
+	ParentId 	*uuid.UUID 	`json:"parent_id"`
+	SortOrder 	int 		`json:"sort_order"`
+	RequiresApproval 	bool 	`json:"requires_approval"`
+}
+
+type CategoryResponse struct {
+	Id   		uuid.UUID 	`db:"id" json:"id"`
+	Name 		string    	`db:"name" json:"name"`
+	Slug 		string    	`db:"slug" json:"slug"`
+	ParentId 	*uuid.UUID 	`db:"parent_id" json:"parent_id"`
+	SortOrder 	int 		`db:"sort_order" json:"sort_order"`
+	RequiresApproval 	bool 	`db:"requires_approval" json:"requires_approval"`
+}
+
+type CategoryApprovalRequest struct {
+	Id 					uuid.UUID 	`json:"-" openapi:"-"`
+	RequiresApproval 	*bool 		`json:"requires_approval" binding:"required"`
+}
+
+type RenameCategoryRequest struct {
+	Id 		uuid.UUID 	`json:"-" openapi:"-"`
+	Name 	string 		`json:"name" binding:"required" conform:"name"`
+	Slug 	string 		`json:"slug" binding:"omitempty,lowercase" conform:"trim"`
+}
+
+type CityUsageResponse struct {
+	Profiles int `db:"profiles" json:"profiles"`
+}
+
+type CategoryUsageResponse struct {
+	Products 		int `db:"products" json:"products"`
+	Subcategories 	int `db:"subcategories" json:"subcategories"`
+}
+
 type GetByIdRequest struct {
 	ID string `uri:"id"`
 }
 
 type GetByPath struct {
 	Path string `uri:"path"`
-}
\ No newline at end of file
+}
+type PageRequest struct {
+	Page    int `form:"page"`
+	PerPage int `form:"per_page"`
+}
+
+func (p PageRequest) Number() int {
+	if p.Page < 1 {
+		return 1
+	}
+	return p.Page
+}
+
+func (p PageRequest) Limit() int {
+	if p.PerPage < 1 {
+		return 50
+	}
+	if p.PerPage > 100 {
+		return 100
+	}
+	return p.PerPage
+}
+
+func (p PageRequest) Offset() int {
+	return (p.Number() - 1) * p.Limit()
+}
@@ -0,0 +1,71 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DisputeRequest struct {
+	TransactionId 	uuid.UUID 	`json:"-" openapi:"-"`
+	BuyerId 		uuid.UUID 	`json:"-" openapi:"-"`
+	Reason 			string 		`json:"reason" binding:"required,oneof=NOT_RECEIVED NOT_AS_DESCRIBED DAMAGED OTHER"`
+	Details 		string 		`json:"details" binding:"required,max=1000" conform:"trim"`
+}
+
+type DisputeFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=OPEN ESCALATED RESOLVING RESOLVED"`
+}
+
+type DisputeMessageRequest struct {
+	DisputeId 	uuid.UUID 	`json:"-" openapi:"-"`
+	AuthorId 	uuid.UUID 	`json:"-" openapi:"-"`
+	Body 		string 		`json:"body" binding:"required,max=1000" conform:"trim"`
+}
+
+// ResolveDisputeRequest settles a dispute. A partial refund gives RefundAmount
+// back to the buyer and the rest of the price to the seller.
+type ResolveDisputeRequest struct {
+	Id 				uuid.UUID 	`json:"-" openapi:"-"`
+	AdminId 		uuid.UUID 	`json:"-" openapi:"-"`
+	Decision 		string 		`json:"decision" binding:"required,oneof=REFUND PARTIAL_REFUND REJECT"`
+	RefundAmount 	int64 		`json:"refund_amount" binding:"required_if=Decision PARTIAL_REFUND"`
+	Note 			string 		`json:"note" binding:"required,max=1000" conform:"trim"`
+}
+
+// DisputeResponse is a dispute with its order. Awaiting is the party that has
+// to answer before RespondBy, Messages are only listed on a single dispute.
+type DisputeResponse struct {
+	Id 				uuid.UUID 	`db:"id" json:"id"`
+	TransactionId 	uuid.UUID 	`db:"transaction_id" json:"transaction_id"`
+	OrderId 		uuid.UUID 	`db:"order_id" json:"order_id"`
+	OrderStatus 	string 		`db:"order_status" json:"order_status"`
+	Price 			int64 		`db:"price" json:"price"`
+	ProductId 		uuid.UUID 	`db:"product_id" json:"product_id"`
+	ProductName 	string 		`db:"product_name" json:"product_name"`
+	BuyerId 		uuid.UUID 	`db:"buyer_id" json:"buyer_id"`
+	BuyerName 		string 		`db:"buyer_name" json:"buyer_name"`
+	SellerId 		uuid.UUID 	`db:"seller_id" json:"seller_id"`
+	SellerName 		string 		`db:"seller_name" json:"seller_name"`
+	Reason 			string 		`db:"reason" json:"reason"`
+	Details 		string 		`db:"details" json:"details"`
+	Status 			string 		`db:"status" json:"status"`
+	Awaiting 		*string 	`db:"awaiting" json:"awaiting,omitempty"`
+	RespondBy 		*time.Time 	`db:"respond_by" json:"respond_by,omitempty"`
+	Resolution 		*string 	`db:"resolution" json:"resolution,omitempty"`
+	RefundAmount 	*int64 		`db:"refund_amount" json:"refund_amount,omitempty"`
+	ResolutionNote 	string 		`db:"resolution_note" json:"resolution_note,omitempty"`
+	CreatedAt 		time.Time 	`db:"created_at" json:"created_at"`
+	EscalatedAt 	*time.Time 	`db:"escalated_at" json:"escalated_at,omitempty"`
+	ResolvedAt 		*time.Time 	`db:"resolved_at" json:"resolved_at,omitempty"`
+	Messages 		[]DisputeMessageResponse 	`db:"-" json:"messages,omitempty"`
+}
+
+type DisputeMessageResponse struct {
+	Id 			uuid.UUID 	`db:"id" json:"id"`
+	AuthorId 	uuid.UUID 	`db:"author_id" json:"author_id"`
+	AuthorName 	string 		`db:"author_name" json:"author_name"`
+	Body 		string 		`db:"body" json:"body"`
+	ImageUrl 	*string 	`db:"image_url" json:"image_url,omitempty"`
+	CreatedAt 	time.Time 	`db:"created_at" json:"created_at"`
+}
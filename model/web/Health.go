@@ -0,0 +1,21 @@
+package web
+
+type HealthCheck struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Ready     bool                 `json:"ready"`
+	Database  HealthCheck          `json:"database"`
+	Storage   HealthCheck          `json:"storage"`
+	Migration MigrationHealthCheck `json:"migration"`
+}
+
+type MigrationHealthCheck struct {
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+	Current  uint   `json:"current"`
+	Expected uint   `json:"expected"`
+	Dirty    bool   `json:"dirty"`
+}
@@ -0,0 +1,29 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobRunFilter struct {
+	Job 	string `form:"job" binding:"omitempty,oneof=expire_offers unpublish_stale_listings remind_sellers end_reservations"`
+	Status 	string `form:"status" binding:"omitempty,oneof=RUNNING SUCCEEDED FAILED"`
+}
+
+type JobRunResponse struct {
+	Id 			uuid.UUID 	`db:"id" json:"id"`
+	Job 		string 		`db:"job" json:"job"`
+	Status 		string 		`db:"status" json:"status"`
+	Affected 	int64 		`db:"affected" json:"affected"`
+	Error 		*string 	`db:"error" json:"error"`
+	StartedAt 	time.Time 	`db:"started_at" json:"started_at"`
+	FinishedAt 	*time.Time 	`db:"finished_at" json:"finished_at"`
+}
+
+type NotificationResponse struct {
+	Id 			uuid.UUID 	`db:"id" json:"id"`
+	Kind 		string 		`db:"kind" json:"kind"`
+	Message 	string 		`db:"message" json:"message"`
+	CreatedAt 	time.Time 	`db:"created_at" json:"created_at"`
+}
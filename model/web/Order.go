@@ -0,0 +1,47 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderFilter struct {
+	Role 	string `form:"role" binding:"omitempty,oneof=BUYER SELLER"`
+	Status 	string `form:"status" binding:"omitempty,oneof=AWAITING_PAYMENT PAID SHIPPED DELIVERED COMPLETED CANCELLED"`
+}
+
+// OrderStatusRequest moves an order to its next status. Shipping needs the
+// carrier and tracking number, cancelling needs a reason.
+type OrderStatusRequest struct {
+	Id 				uuid.UUID 	`json:"-" openapi:"-"`
+	AccountId 		uuid.UUID 	`json:"-" openapi:"-"`
+	Status 			string 		`json:"status" binding:"required,oneof=PAID SHIPPED DELIVERED COMPLETED CANCELLED"`
+	Carrier 		string 		`json:"carrier" binding:"required_if=Status SHIPPED,max=100" conform:"trim"`
+	TrackingNumber 	string 		`json:"tracking_number" binding:"required_if=Status SHIPPED,max=100" conform:"trim"`
+	Reason 			string 		`json:"reason" binding:"required_if=Status CANCELLED,max=500" conform:"trim"`
+}
+
+type OrderResponse struct {
+	Id 				uuid.UUID 	`db:"id" json:"id"`
+	TransactionId 	uuid.UUID 	`db:"transaction_id" json:"transaction_id"`
+	ProductId 		uuid.UUID 	`db:"product_id" json:"product_id"`
+	ProductName 	string 		`db:"product_name" json:"product_name"`
+	BuyerId 		uuid.UUID 	`db:"buyer_id" json:"buyer_id"`
+	BuyerName 		string 		`db:"buyer_name" json:"buyer_name"`
+	SellerId 		uuid.UUID 	`db:"seller_id" json:"seller_id"`
+	SellerName 		string 		`db:"seller_name" json:"seller_name"`
+	Price 			int64 		`db:"price" json:"price"`
+	Status 			string 		`db:"status" json:"status"`
+	Carrier 		*string 	`db:"carrier" json:"carrier,omitempty"`
+	TrackingNumber 	*string 	`db:"tracking_number" json:"tracking_number,omitempty"`
+	CancelReason 	*string 	`db:"cancel_reason" json:"cancel_reason,omitempty"`
+	CreatedAt 		time.Time 	`db:"created_at" json:"created_at"`
+	UpdatedAt 		time.Time 	`db:"updated_at" json:"updated_at"`
+	PaidAt 			*time.Time 	`db:"paid_at" json:"paid_at,omitempty"`
+	ShippedAt 		*time.Time 	`db:"shipped_at" json:"shipped_at,omitempty"`
+	DeliveredAt 	*time.Time 	`db:"delivered_at" json:"delivered_at,omitempty"`
+	CompletedAt 	*time.Time 	`db:"completed_at" json:"completed_at,omitempty"`
+	CancelledAt 	*time.Time 	`db:"cancelled_at" json:"cancelled_at,omitempty"`
+	Payments 		[]PaymentResponse 	`db:"-" json:"payments,omitempty"`
+}
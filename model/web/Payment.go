@@ -0,0 +1,30 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentEvent is the body a payment provider posts to the webhook
+type PaymentEvent struct {
+	Id 			string 	`json:"id" binding:"required"`
+	Type 		string 	`json:"type" binding:"required"`
+	ChargeId 	string 	`json:"charge_id" binding:"required"`
+}
+
+type PaymentResponse struct {
+	Id 				uuid.UUID 	`db:"id" json:"id"`
+	TransactionId 	uuid.UUID 	`db:"transaction_id" json:"transaction_id"`
+	OrderId 		uuid.UUID 	`db:"order_id" json:"order_id"`
+	Provider 		string 		`db:"provider" json:"provider"`
+	ChargeId 		string 		`db:"charge_id" json:"charge_id"`
+	Amount 			int64 		`db:"amount" json:"amount"`
+	RefundedAmount 	int64 		`db:"refunded_amount" json:"refunded_amount"`
+	Status 			string 		`db:"status" json:"status"`
+	PaymentUrl 		string 		`db:"payment_url" json:"payment_url,omitempty"`
+	CreatedAt 		time.Time 	`db:"created_at" json:"created_at"`
+	HeldAt 			*time.Time 	`db:"held_at" json:"held_at,omitempty"`
+	ReleasedAt 		*time.Time 	`db:"released_at" json:"released_at,omitempty"`
+	RefundedAt 		*time.Time 	`db:"refunded_at" json:"refunded_at,omitempty"`
+}
@@ -10,16 +10,35 @@ import (
 type CreateProductRequest struct {
 	Name        string `json:"name" binding:"required" conform:"trim"`
 	Price       int64  `json:"price" binding:"required,number,gte=1000" conform:"trim"`
-	Category    string `json:"category" binding:"required" conform:"name"`
+	Category    string `json:"category" binding:"required" conform:"trim"`
 	Description string `json:"description" conform:"trim,!html,!js"`
 }
 
+// ProductFilter narrows public listings down to sellers in a province or city,
+// given by id or name, or within Radius km of Near ("lat,lng"). The ids and
+// coordinates are resolved by the service.
+type ProductFilter struct {
+	Province 	string 			`form:"province"`
+	City 		string 			`form:"city"`
+	Near 		string 			`form:"near"`
+	Radius 		float64 		`form:"radius"`
+	ProvinceId 	uuid.NullUUID 	`form:"-" openapi:"-"`
+	CityId 		uuid.NullUUID 	`form:"-" openapi:"-"`
+	Latitude 	*float64 		`form:"-" openapi:"-"`
+	Longitude 	*float64 		`form:"-" openapi:"-"`
+}
+
 type ProductResponse struct {
 	Id   		uuid.UUID 	`db:"id" json:"id"`
 	Name 		string    	`db:"name" json:"name"`
 	Price       int64  		`db:"price" json:"price"`
 	Category    string 		`db:"category" json:"category"`
+	CategorySlug string 	`db:"category_slug" json:"category_slug"`
 	Thumbnail 	string    	`db:"thumbnail" json:"thumbnail"`
+	Distance 	*float64 	`db:"distance" json:"distance_km,omitempty"`
+	ApprovalStatus 	string 	`db:"approval_status" json:"approval_status,omitempty"`
+	RejectionReason string 	`db:"rejection_reason" json:"rejection_reason,omitempty"`
+	Reserved 		bool 	`db:"reserved" json:"reserved,omitempty"`
 }
 
 type ProductDetailResponse struct {
@@ -31,23 +50,57 @@ type ProductDetailResponse struct {
 	Name 		string    	`db:"name" json:"name"`
 	Price       int64  		`db:"price" json:"price"`
 	Category    string 		`db:"category" json:"category"`
+	CategorySlug string 	`db:"category_slug" json:"category_slug"`
 	Description string    	`db:"description" json:"description"`
 	Sold		bool      `db:"sold" json:"sold"`
 	Published	bool      `db:"published" json:"published"`
 	UpdatedAt   time.Time 	`db:"updated_at" json:"updated_at"`
+	Reserved 		bool 		`db:"reserved" json:"reserved"`
+	ReservedUntil 	*time.Time 	`db:"reserved_until" json:"reserved_until,omitempty"`
+	MinPrice 		*int64 	`db:"min_price" json:"min_price,omitempty"`
+	AutoAcceptPrice *int64 	`db:"auto_accept_price" json:"auto_accept_price,omitempty"`
+	AuctionId 		*uuid.UUID 	`db:"auction_id" json:"auction_id,omitempty"`
 	ProductImages []entity.ProductImage `json:"product_image"`
 }
 
 type UpdateProductRequest struct {
-	Id   		uuid.UUID 	`json:"id"`
-	AccountId   uuid.UUID 	`json:"account_id"`
+	Id   		uuid.UUID 	`json:"id" openapi:"-"`
+	AccountId   uuid.UUID 	`json:"account_id" openapi:"-"`
 	Name        string 		`json:"name" binding:"required" conform:"trim"`
 	Price       int64  		`json:"price" binding:"required,number,gte=1000" conform:"trim"`
-	Category    string 		`json:"category" binding:"required" conform:"name"`
+	Category    string 		`json:"category" binding:"required" conform:"trim"`
 	Description string 		`json:"description" conform:"trim,!html,!js"`
+	MinPrice 		*int64 	`json:"min_price" binding:"omitempty,gt=0"`
+	AutoAcceptPrice *int64 	`json:"auto_accept_price" binding:"omitempty,gt=0"`
 }
 
 type ProductImageRequest struct {
 	ImageId   	uuid.UUID 	`json:"image_id"`
 	ProductId   uuid.UUID 	`json:"product_id"`
-}
\ No newline at end of file
+}
+
+// PublishResponse is the outcome of toggling the published flag. A listing
+// that needs approval is submitted instead and stays unpublished as PENDING.
+type PublishResponse struct {
+	Published 		bool 	`json:"published"`
+	ApprovalStatus 	string 	`json:"approval_status"`
+}
+
+type ProductReviewRequest struct {
+	Id 			uuid.UUID 	`json:"-" openapi:"-"`
+	AdminId 	uuid.UUID 	`json:"-" openapi:"-"`
+	Decision 	string 		`json:"decision" binding:"required,oneof=APPROVE REJECT"`
+	Reason 		string 		`json:"reason" binding:"required_if=Decision REJECT,max=500" conform:"trim"`
+}
+
+type PendingProductResponse struct {
+	Id   		uuid.UUID 	`db:"id" json:"id"`
+	Name 		string    	`db:"name" json:"name"`
+	Price       int64  		`db:"price" json:"price"`
+	Category    string 		`db:"category" json:"category"`
+	Description string 		`db:"description" json:"description"`
+	Thumbnail 	string    	`db:"thumbnail" json:"thumbnail"`
+	OwnerId 	uuid.UUID 	`db:"owner_id" json:"owner_id"`
+	Owner 		string 		`db:"owner" json:"owner"`
+	SubmittedAt time.Time 	`db:"submitted_at" json:"submitted_at"`
+}
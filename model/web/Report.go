@@ -0,0 +1,54 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportRequest struct {
+	ReporterId 	uuid.UUID 	`json:"-" openapi:"-"`
+	TargetType 	string 		`json:"-" openapi:"-"`
+	TargetId 	uuid.UUID 	`json:"-" openapi:"-"`
+	Reason 		string 		`json:"reason" binding:"required,oneof=SCAM PROHIBITED_ITEM MISLEADING HARASSMENT SPAM OTHER"`
+	Details 	string 		`json:"details" binding:"required_if=Reason OTHER,max=1000" conform:"trim"`
+}
+
+type ReportFilter struct {
+	Status 		string `form:"status" binding:"omitempty,oneof=OPEN DISMISSED ACTIONED"`
+	TargetType 	string `form:"target_type" binding:"omitempty,oneof=PRODUCT PROFILE TRANSACTION"`
+}
+
+// ReportResponse is one entry of the moderation queue. OpenReports counts the
+// open reports on the same target and Overdue is set once DueAt has passed
+// without a decision.
+type ReportResponse struct {
+	Id 					uuid.UUID 	`db:"id" json:"id"`
+	TargetType 			string 		`db:"target_type" json:"target_type"`
+	TargetId 			uuid.UUID 	`db:"target_id" json:"target_id"`
+	ReportedAccountId 	uuid.UUID 	`db:"reported_account_id" json:"reported_account_id"`
+	ReportedName 		string 		`db:"reported_name" json:"reported_name"`
+	ReporterId 			uuid.UUID 	`db:"reporter_id" json:"reporter_id"`
+	ReporterName 		string 		`db:"reporter_name" json:"reporter_name"`
+	Reason 				string 		`db:"reason" json:"reason"`
+	Details 			string 		`db:"details" json:"details"`
+	Status 				string 		`db:"status" json:"status"`
+	Action 				*string 	`db:"action" json:"action"`
+	Note 				string 		`db:"note" json:"note"`
+	ResolvedBy 			*uuid.UUID 	`db:"resolved_by" json:"resolved_by"`
+	OpenReports 		int 		`db:"open_reports" json:"open_reports"`
+	Overdue 			bool 		`db:"overdue" json:"overdue"`
+	CreatedAt 			time.Time 	`db:"created_at" json:"created_at"`
+	DueAt 				time.Time 	`db:"due_at" json:"due_at"`
+	ResolvedAt 			*time.Time 	`db:"resolved_at" json:"resolved_at"`
+}
+
+// ResolveReportRequest settles a report together with every other open report
+// on the same target
+type ResolveReportRequest struct {
+	Id 		uuid.UUID 	`json:"-" openapi:"-"`
+	AdminId uuid.UUID 	`json:"-" openapi:"-"`
+	Action 	string 		`json:"action" binding:"required,oneof=DISMISS HIDE_LISTING SUSPEND_USER"`
+	Note 	string 		`json:"note" binding:"max=1000" conform:"trim"`
+	Until 	*time.Time 	`json:"until" binding:"required_if=Action SUSPEND_USER"`
+}
@@ -7,15 +7,42 @@ import (
 )
 
 type TransactionRequest struct {
-	BuyerId 	uuid.UUID 	`json:"buyer_id"`
+	BuyerId 	uuid.UUID 	`json:"buyer_id" openapi:"-"`
 	ProductId 	uuid.UUID	`json:"product_id" binding:"required"`
-	Price 		int64		`json:"price" binding:"required"`
+	Price 		int64		`json:"price" binding:"required,gt=0"`
+	Note 		string 		`json:"note" binding:"max=500" conform:"trim"`
 }
 
 type TransactionUpdateRequest struct {
-	BuyerId 		uuid.UUID 	`json:"buyer_id"`
+	BuyerId 		uuid.UUID 	`json:"buyer_id" openapi:"-"`
 	TransactionId 	uuid.UUID	`json:"transaction_id" binding:"required"`
-	Price 			int64		`json:"price" binding:"required"`
+	Price 			int64		`json:"price" binding:"required,gt=0"`
+	Note 			string 		`json:"note" binding:"max=500" conform:"trim"`
+}
+
+// OfferResponse tells whether an offer was new and whether it met the
+// seller's auto-accept price
+type OfferResponse struct {
+	TransactionId 	uuid.UUID 	`json:"transaction_id"`
+	Created 		bool 		`json:"created"`
+	Accepted 		bool 		`json:"accepted"`
+}
+
+type CounterOfferRequest struct {
+	SellerId 		uuid.UUID 	`json:"-" openapi:"-"`
+	TransactionId 	uuid.UUID	`json:"-" openapi:"-"`
+	Price 			int64		`json:"price" binding:"required,gt=0"`
+	Note 			string 		`json:"note" binding:"max=500" conform:"trim"`
+}
+
+// NegotiationEntry is one price proposed on an offer, oldest first
+type NegotiationEntry struct {
+	ProposedBy 		uuid.UUID 	`db:"proposed_by" json:"proposed_by"`
+	Name 			string 		`db:"name" json:"name"`
+	Role 			string 		`db:"role" json:"role"`
+	Amount 			int64 		`db:"amount" json:"amount"`
+	Note 			string 		`db:"note" json:"note"`
+	CreatedAt 		time.Time 	`db:"created_at" json:"created_at"`
 }
 
 type TransactionDetailResponse struct {
@@ -34,6 +61,11 @@ type TransactionDetailResponse struct {
 	Accepted	 	bool		`db:"accepted" json:"accepted"`
 	PriceOffer	 	int64		`db:"price_offer" json:"price_offer"`
 	UpdatedAt   	time.Time 	`db:"updated_at" json:"updated_at"`
+	BuyerPhoneNumber 	string 	`json:"buyer_phone_number"`
+	BuyerAddress 		string 	`json:"buyer_address"`
+	SellerPhoneNumber 	string 	`json:"seller_phone_number"`
+	SellerAddress 		string 	`json:"seller_address"`
+	Negotiation 		[]NegotiationEntry 	`json:"negotiation"`
 }
 
 type Offer struct {
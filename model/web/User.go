@@ -1,8 +1,11 @@
 package web
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	_ "github.com/go-playground/validator/v10"
+	"github.com/lib/pq"
 )
 
 type CreateUserRequest struct {
@@ -21,11 +24,13 @@ type LoginResponse struct {
 }
 
 type UpdateProfileRequest struct {
-	Id          uuid.UUID 	`db:"id" json:"id"`
+	Id          uuid.UUID 	`db:"id" json:"id" openapi:"-"`
 	Name        string		`db:"name" json:"name" binding:"required" conform:"name"`
-	City    	string   	`db:"city" json:"city" conform:"name"`
+	City    	string   	`db:"city" json:"city" conform:"trim"`
 	Address     string   	`db:"address" json:"address" conform:"trim"`
 	PhoneNumber string   	`db:"phone_number" json:"phone_number"`
+	Latitude 	*float64 	`db:"latitude" json:"latitude" binding:"omitempty,gte=-90,lte=90,required_with=Longitude"`
+	Longitude 	*float64 	`db:"longitude" json:"longitude" binding:"omitempty,gte=-180,lte=180,required_with=Latitude"`
 }
 
 
@@ -36,8 +41,94 @@ type GetProfileResponse struct {
 	Address     string   	`db:"address" json:"address"`
 	PhoneNumber string   	`db:"phone_number" json:"phone_number"`
 	ImageUrl 	string  	`db:"image_url" json:"image_url"`
+	Latitude 	*float64 	`db:"latitude" json:"latitude"`
+	Longitude 	*float64 	`db:"longitude" json:"longitude"`
+	PhoneVisibility 	string 	`db:"phone_visibility" json:"phone_visibility,omitempty"`
+	AddressVisibility 	string 	`db:"address_visibility" json:"address_visibility,omitempty"`
+}
+
+type PrivacySettings struct {
+	Id 					uuid.UUID 	`json:"-" openapi:"-"`
+	PhoneVisibility 	string 		`db:"phone_visibility" json:"phone_visibility" binding:"required,oneof=PUBLIC ACCEPTED_BUYERS NOBODY"`
+	AddressVisibility 	string 		`db:"address_visibility" json:"address_visibility" binding:"required,oneof=PUBLIC ACCEPTED_BUYERS NOBODY"`
 }
 
 type GetAccountRequest struct {
 	ID string `uri:"id"`
-}
\ No newline at end of file
+}
+
+type AccountExport struct {
+	Account 	ExportAccount 		`json:"account"`
+	Profile 	GetProfileResponse 	`json:"profile"`
+	Products 	[]ExportProduct 	`json:"products"`
+	Offers 		[]ExportOffer 		`json:"offers"`
+	ExportedAt 	time.Time 			`json:"exported_at"`
+}
+
+type ExportAccount struct {
+	Id 			uuid.UUID 	`json:"id"`
+	Email 		string 		`json:"email"`
+	Role 		string 		`json:"role"`
+	CreatedAt 	time.Time 	`json:"created_at"`
+}
+
+type ExportProduct struct {
+	Id          uuid.UUID 		`db:"id" json:"id"`
+	Name        string    		`db:"name" json:"name"`
+	Price       int64     		`db:"price" json:"price"`
+	Category 	string    		`db:"category" json:"category"`
+	Description string    		`db:"description" json:"description"`
+	Thumbnail 	string    		`db:"thumbnail" json:"thumbnail"`
+	Images 		pq.StringArray 	`db:"images" json:"images"`
+	Sold		bool      		`db:"sold" json:"sold"`
+	Published	bool      		`db:"published" json:"published"`
+	Deleted		bool      		`db:"deleted" json:"deleted"`
+	CreatedAt   time.Time 		`db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time 		`db:"updated_at" json:"updated_at"`
+}
+
+// ExportOffer is an offer made or received, Role tells which side the
+// exporting account was on
+type ExportOffer struct {
+	Id 					uuid.UUID 	`db:"id" json:"id"`
+	Role 				string 		`db:"role" json:"role"`
+	ProductId 			uuid.UUID 	`db:"product_id" json:"product_id"`
+	ProductName 		string 		`db:"product_name" json:"product_name"`
+	CounterpartyId 		uuid.UUID 	`db:"counterparty_id" json:"counterparty_id"`
+	CounterpartyName 	string 		`db:"counterparty_name" json:"counterparty_name"`
+	PriceOffer 			int64 		`db:"price_offer" json:"price_offer"`
+	Accepted 			bool 		`db:"accepted" json:"accepted"`
+	Deleted 			bool 		`db:"deleted" json:"deleted"`
+	CreatedAt 			time.Time 	`db:"created_at" json:"created_at"`
+	UpdatedAt 			time.Time 	`db:"updated_at" json:"updated_at"`
+}
+
+type ExportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json zip"`
+}
+
+type AccountStatusRequest struct {
+	AccountId 	uuid.UUID 	`json:"-" openapi:"-"`
+	AdminId 	uuid.UUID 	`json:"-" openapi:"-"`
+	Status 		string 		`json:"status" binding:"required,oneof=ACTIVE SUSPENDED BANNED"`
+	Until 		*time.Time 	`json:"until" binding:"required_if=Status SUSPENDED"`
+	Reason 		string 		`json:"reason" binding:"required_unless=Status ACTIVE,max=500" conform:"trim"`
+}
+
+type AccountStatusChange struct {
+	Status 			string 		`db:"status" json:"status"`
+	SuspendedUntil 	*time.Time 	`db:"suspended_until" json:"suspended_until"`
+	Reason 			string 		`db:"reason" json:"reason"`
+	AdminId 		uuid.UUID 	`db:"admin_id" json:"admin_id"`
+	AdminName 		string 		`db:"admin_name" json:"admin_name"`
+	CreatedAt 		time.Time 	`db:"created_at" json:"created_at"`
+}
+
+// AccountStatusResponse shows the status in effect, a lapsed suspension reads
+// as ACTIVE
+type AccountStatusResponse struct {
+	Status 			string 					`json:"status"`
+	SuspendedUntil 	*time.Time 				`json:"suspended_until"`
+	Reason 			string 					`json:"reason"`
+	History 		[]AccountStatusChange 	`json:"history"`
+}
@@ -0,0 +1,56 @@
+package web
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WalletResponse is what a seller is owed. Available can be paid out, Held
+// waits in escrow for buyers to confirm deliveries.
+type WalletResponse struct {
+	Available 		int64 	`db:"available" json:"available"`
+	PendingPayout 	int64 	`db:"pending_payout" json:"pending_payout"`
+	Held 			int64 	`db:"held" json:"held"`
+}
+
+// StatementLine is one posting on a seller's wallet, a positive amount is
+// money in
+type StatementLine struct {
+	EntryId 		uuid.UUID 	`db:"entry_id" json:"entry_id"`
+	Kind 			string 		`db:"kind" json:"kind"`
+	ReferenceId 	uuid.UUID 	`db:"reference_id" json:"reference_id"`
+	Description 	string 		`db:"description" json:"description"`
+	Amount 			int64 		`db:"amount" json:"amount"`
+	BalanceAfter 	int64 		`db:"balance_after" json:"balance_after"`
+	CreatedAt 		time.Time 	`db:"created_at" json:"created_at"`
+}
+
+type PayoutRequest struct {
+	SellerId 		uuid.UUID 	`json:"-" openapi:"-"`
+	Amount 			int64 		`json:"amount" binding:"required,gt=0"`
+	Destination 	string 		`json:"destination" binding:"required,max=200" conform:"trim"`
+}
+
+type PayoutFilter struct {
+	Status 	string `form:"status" binding:"omitempty,oneof=REQUESTED APPROVED REJECTED"`
+}
+
+type PayoutReviewRequest struct {
+	Id 			uuid.UUID 	`json:"-" openapi:"-"`
+	AdminId 	uuid.UUID 	`json:"-" openapi:"-"`
+	Decision 	string 		`json:"decision" binding:"required,oneof=APPROVE REJECT"`
+	Reason 		string 		`json:"reason" binding:"required_if=Decision REJECT,max=500" conform:"trim"`
+}
+
+type PayoutResponse struct {
+	Id 				uuid.UUID 	`db:"id" json:"id"`
+	SellerId 		uuid.UUID 	`db:"seller_id" json:"seller_id"`
+	SellerName 		string 		`db:"seller_name" json:"seller_name"`
+	Amount 			int64 		`db:"amount" json:"amount"`
+	Destination 	string 		`db:"destination" json:"destination"`
+	Status 			string 		`db:"status" json:"status"`
+	Reason 			*string 	`db:"reason" json:"reason,omitempty"`
+	CreatedAt 		time.Time 	`db:"created_at" json:"created_at"`
+	ReviewedAt 		*time.Time 	`db:"reviewed_at" json:"reviewed_at,omitempty"`
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/jmoiron/sqlx"
+)
+
+const reencryptBatchSize = 500
+
+// reencrypt brings every personal data column onto the active key. Run it with
+// "go run . reencrypt" after encryption is enabled and after each key rotation,
+// then drop the retired key from ENCRYPTION_KEYS.
+func reencrypt(db *sqlx.DB, keyring *util.Keyring) {
+
+	ctx := context.Background()
+
+	accounts, err := repository.NewAccountRepository(db, keyring).Reencrypt(ctx, reencryptBatchSize)
+	if err != nil {
+		log.Fatalf("failed to re-encrypt accounts after %d rows, err : %v\n", accounts, err)
+	}
+
+	profiles, err := repository.NewProfileRepository(db, keyring).Reencrypt(ctx, reencryptBatchSize)
+	if err != nil {
+		log.Fatalf("failed to re-encrypt profiles after %d rows, err : %v\n", profiles, err)
+	}
+
+	log.Printf("re-encrypted %d accounts and %d profiles\n", accounts, profiles)
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/RuhullahReza/SecondHand/helper"
 	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/util"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
@@ -15,42 +19,77 @@ type AccountRepository interface {
 	Create(ctx context.Context, account *entity.Account) (*entity.Account, error)
 	FindByEmail(ctx context.Context, email string) (*entity.Account, error) 
 	FindById(ctx context.Context, id uuid.UUID) (*entity.Account, error)
+	Reencrypt(ctx context.Context, batchSize int) (int, error)
+	Delete(ctx context.Context, id uuid.UUID) ([]string, error)
+	GetStatus(ctx context.Context, id uuid.UUID) (*entity.Account, error)
+	SetStatus(ctx context.Context, change *entity.AccountStatusChange) error
+	GetStatusHistory(ctx context.Context, id uuid.UUID) ([]web.AccountStatusChange, error)
 }
 
 type AccountRepositoryImpl struct {
 	DB *sqlx.DB
+	Keyring *util.Keyring
 }
 
-func NewAccountRepository(db *sqlx.DB) AccountRepository {
+func NewAccountRepository(db *sqlx.DB, keyring *util.Keyring) AccountRepository {
 	return &AccountRepositoryImpl{
 		DB: db,
+		Keyring: keyring,
 	}
 }
 
 func (r *AccountRepositoryImpl) Create(ctx context.Context, account *entity.Account) (*entity.Account, error) {
 	
-	query := "INSERT INTO accounts (email, password, role) VALUES ($1, $2, $3) RETURNING *"
-	err := r.DB.GetContext(ctx, account, query, account.Email, account.Password, account.Role)
+	ctx, span := helper.StartSpan(ctx, "AccountRepository.Create")
+	defer span.End()
+
+	email := account.Email
+
+	encrypted, err := r.Keyring.Encrypt(email)
+	if err != nil {
+		log.Printf("failed to encrypt email on create account, err : %v\n", err)
+		return account, helper.NewInternal()
+	}
+
+	// rows not yet re-encrypted have no index, their email is still plaintext
+	query := `
+	INSERT INTO accounts (email, email_index, password, role) 
+	SELECT $1, $2, $3, $4
+	WHERE NOT EXISTS (SELECT 1 FROM accounts WHERE email_index IS NULL AND email = $5)
+	RETURNING *`
+	err = r.DB.GetContext(ctx, account, query, encrypted, r.Keyring.BlindIndex(email), account.Password, account.Role, email)
 
 	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return account, helper.NewConflict("email", email)
+		}
+
 		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
-			return account, helper.NewConflict("email", account.Email)
+			return account, helper.NewConflict("email", email)
 		}
 
 		log.Printf("failed to query create account, err : %v\n", err)
 		return account, helper.NewInternal()
 	}
 
+	account.Email = email
+
 	return account, nil
 }
 
 func (r *AccountRepositoryImpl) FindByEmail(ctx context.Context, email string) (*entity.Account, error) {
 	
+	ctx, span := helper.StartSpan(ctx, "AccountRepository.FindByEmail")
+	defer span.End()
+
 	account := &entity.Account{}
 
-	query := "SELECT * FROM accounts WHERE email=$1"
+	query := `
+	SELECT * FROM accounts 
+	WHERE (email_index = $1 OR (email_index IS NULL AND email = $2)) AND deleted_at IS NULL 
+	LIMIT 1`
 
-	if err := r.DB.GetContext(ctx, account, query, email); err != nil {
+	if err := r.DB.GetContext(ctx, account, query, r.Keyring.BlindIndex(email), email); err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			return account, helper.NewNotFound("email", email)
 		}
@@ -59,11 +98,14 @@ func (r *AccountRepositoryImpl) FindByEmail(ctx context.Context, email string) (
 		return account, helper.NewInternal()
 	}
 
-	return account, nil
+	return account, r.decrypt(account)
 }
 
 func (r *AccountRepositoryImpl) FindById(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
 	
+	ctx, span := helper.StartSpan(ctx, "AccountRepository.FindById")
+	defer span.End()
+
 	account := &entity.Account{}
 
 	query := "SELECT * FROM accounts WHERE id=$1"
@@ -77,5 +119,395 @@ func (r *AccountRepositoryImpl) FindById(ctx context.Context, id uuid.UUID) (*en
 		return account, helper.NewInternal()
 	}
 
+	return account, r.decrypt(account)
+}
+
+// Reencrypt seals every email that is plaintext, uses a retired key or lacks
+// its blind index, batchSize rows at a time. It returns the rows rewritten.
+func (r *AccountRepositoryImpl) Reencrypt(ctx context.Context, batchSize int) (int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AccountRepository.Reencrypt")
+	defer span.End()
+
+	updated := 0
+	lastId := uuid.Nil
+
+	for {
+		accounts := []entity.Account{}
+
+		query := "SELECT * FROM accounts WHERE id > $1 AND deleted_at IS NULL ORDER BY id LIMIT $2"
+		if err := r.DB.SelectContext(ctx, &accounts, query, lastId, batchSize); err != nil {
+			log.Printf("failed to query accounts to re-encrypt, err : %v\n", err)
+			return updated, helper.NewInternal()
+		}
+
+		for _, account := range accounts {
+			lastId = account.Id
+
+			email, err := r.Keyring.Decrypt(account.Email)
+			if err != nil {
+				log.Printf("failed to decrypt email of account %v, err : %v\n", account.Id, err)
+				return updated, helper.NewInternal()
+			}
+
+			index := r.Keyring.BlindIndex(email)
+			if !r.Keyring.Stale(account.Email) && account.EmailIndex.String == index {
+				continue
+			}
+
+			encrypted, err := r.Keyring.Encrypt(email)
+			if err != nil {
+				log.Printf("failed to encrypt email of account %v, err : %v\n", account.Id, err)
+				return updated, helper.NewInternal()
+			}
+
+			// skip rows changed since they were read
+			query := "UPDATE accounts SET email = $1, email_index = $2 WHERE id = $3 AND email = $4"
+			if _, err := r.DB.ExecContext(ctx, query, encrypted, index, account.Id, account.Email); err != nil {
+				log.Printf("failed to re-encrypt account %v, err : %v\n", account.Id, err)
+				return updated, helper.NewInternal()
+			}
+
+			updated++
+		}
+
+		if len(accounts) < batchSize {
+			return updated, nil
+		}
+	}
+}
+
+// Delete closes the account: its credentials are dropped, the profile is
+// anonymized, listings are soft-deleted and open offers on either side are
+// withdrawn. Accepted offers not paid yet are released with their product and
+// their order is cancelled, completed ones stay for the counterparty. It
+// refuses while an order, payment, payout, auction or dispute of the account
+// is unsettled. It returns the uploaded image urls, whose rows are gone, for
+// the caller to remove from storage.
+func (r *AccountRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) ([]string, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AccountRepository.Delete")
+	defer span.End()
+
+	images := []string{}
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin delete account, err : %v\n", err)
+		return images, helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	// the email placeholder keeps the unique constraint and frees the address
+	query := `
+	UPDATE accounts 
+	SET email = 'deleted:' || id, email_index = NULL, password = '', deleted_at = now(), updated_at = now()
+	WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf("failed to query delete account, err : %v\n", err)
+		return images, helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when delete account, err : %v\n", err)
+		return images, helper.NewInternal()
+	}
+
+	if row == 0 {
+		return images, helper.NewNotFound("id", id.String())
+	}
+
+	err = checkClosable(ctx, tx, id)
+	if err != nil {
+		return images, err
+	}
+
+	err = releaseAccepted(ctx, tx, id)
+	if err != nil {
+		return images, err
+	}
+
+	query = `
+	SELECT image_url FROM profiles WHERE id = $1 AND COALESCE(image_url, '') <> ''
+	UNION
+	SELECT i.image_url FROM images i JOIN products p ON i.product_id = p.id WHERE p.account_id = $1`
+
+	if err := tx.SelectContext(ctx, &images, query, id); err != nil {
+		log.Printf("failed to query images of deleted account, err : %v\n", err)
+		return images, helper.NewInternal()
+	}
+
+	statements := []struct {
+		name  string
+		query string
+		args  []interface{}
+	}{
+		{"anonymize profile", `
+		UPDATE profiles 
+		SET name = $2, city = NULL, address = NULL, phone_number = NULL, image_url = NULL, 
+			latitude = NULL, longitude = NULL, updated_at = now()
+		WHERE id = $1`, []interface{}{id, entity.DeletedName}},
+		{"delete product images", `
+		DELETE FROM images WHERE product_id IN (SELECT id FROM products WHERE account_id = $1)`, []interface{}{id}},
+		{"delete products", `
+		UPDATE products SET deleted = TRUE, published = FALSE, thumbnail = NULL, updated_at = now()
+		WHERE account_id = $1`, []interface{}{id}},
+		{"withdraw offers", `
+		UPDATE transactions SET deleted = TRUE, updated_at = now()
+		WHERE (buyer_id = $1 OR seller_id = $1) AND accepted = FALSE AND deleted = FALSE`, []interface{}{id}},
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			log.Printf("failed to query %s of deleted account, err : %v\n", statement.name, err)
+			return images, helper.NewInternal()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit delete account, err : %v\n", err)
+		return images, helper.NewInternal()
+	}
+
+	return images, nil
+}
+
+// releaseAccepted takes back the accepted offers of a closing account whose
+// order was not completed, cancelling the order with its pending charge and
+// freeing the reserved product. checkClosable already refused paid orders.
+func releaseAccepted(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+
+	transactionIds := []uuid.UUID{}
+
+	query := `
+	SELECT 
+		id
+	FROM 
+		transactions
+	WHERE 
+		(buyer_id = $1 OR seller_id = $1) AND accepted = TRUE AND deleted = FALSE
+		AND NOT EXISTS (SELECT 1 FROM orders WHERE orders.transaction_id = transactions.id AND orders.status = 'COMPLETED')
+	FOR UPDATE
+	`
+
+	if err := tx.SelectContext(ctx, &transactionIds, query, id); err != nil {
+		log.Printf("failed to query accepted offers of deleted account, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	for _, transactionId := range transactionIds {
+		err := cancelOpenOrder(ctx, tx, transactionId, "the account was deleted")
+		if err != nil {
+			return err
+		}
+
+		query = `
+		UPDATE 
+			products 
+		SET 
+			reserved_for = NULL, reserved_until = NULL, updated_at = now()
+		WHERE 
+			reserved_for = $1
+		`
+
+		if _, err := tx.ExecContext(ctx, query, transactionId); err != nil {
+			log.Printf("failed to query release product of deleted account, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		query = "UPDATE transactions SET accepted = FALSE, deleted = TRUE, updated_at = now() WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, transactionId); err != nil {
+			log.Printf("failed to query withdraw accepted offer of deleted account, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	}
+
+	return nil
+}
+
+// checkClosable refuses to close an account while money or a deal of it is
+// still in progress, listing what is left to settle
+func checkClosable(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+
+	usage := struct {
+		Orders   int   `db:"orders"`
+		Payments int   `db:"payments"`
+		Balance  int64 `db:"balance"`
+		Payouts  int   `db:"payouts"`
+		Auctions int   `db:"auctions"`
+		Disputes int   `db:"disputes"`
+	}{}
+
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM orders 
+		WHERE (buyer_id = $1 OR seller_id = $1) AND status IN ('PAID', 'SHIPPED', 'DELIVERED')) as orders,
+		(SELECT COUNT(*) FROM payments JOIN orders ON orders.id = payments.order_id 
+		WHERE (orders.buyer_id = $1 OR orders.seller_id = $1) AND payments.status = 'HELD') as payments,
+		COALESCE((SELECT balance FROM ledger_accounts WHERE kind = 'SELLER_AVAILABLE' AND owner_id = $1), 0) as balance,
+		(SELECT COUNT(*) FROM payouts WHERE seller_id = $1 AND status = 'REQUESTED') as payouts,
+		(SELECT COUNT(*) FROM auctions WHERE (seller_id = $1 OR high_bidder_id = $1) AND status = 'OPEN') as auctions,
+		(SELECT COUNT(*) FROM disputes WHERE (buyer_id = $1 OR seller_id = $1) AND status <> 'RESOLVED') as disputes
+	`
+
+	if err := tx.GetContext(ctx, &usage, query, id); err != nil {
+		log.Printf("failed to query usage of deleted account, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	blocking := []string{}
+	if usage.Orders > 0 {
+		blocking = append(blocking, fmt.Sprintf("%d paid orders", usage.Orders))
+	}
+	if usage.Payments > 0 {
+		blocking = append(blocking, fmt.Sprintf("%d payments held in escrow", usage.Payments))
+	}
+	if usage.Balance > 0 {
+		blocking = append(blocking, fmt.Sprintf("a wallet balance of %d", usage.Balance))
+	}
+	if usage.Payouts > 0 {
+		blocking = append(blocking, fmt.Sprintf("%d requested payouts", usage.Payouts))
+	}
+	if usage.Auctions > 0 {
+		blocking = append(blocking, fmt.Sprintf("%d open auctions", usage.Auctions))
+	}
+	if usage.Disputes > 0 {
+		blocking = append(blocking, fmt.Sprintf("%d open disputes", usage.Disputes))
+	}
+
+	if len(blocking) > 0 {
+		return helper.NewInUse("account", id.String(), strings.Join(blocking, ", "))
+	}
+
+	return nil
+}
+
+// GetStatus loads only what is needed to decide whether the account may act,
+// without touching the encrypted email
+func (r *AccountRepositoryImpl) GetStatus(ctx context.Context, id uuid.UUID) (*entity.Account, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AccountRepository.GetStatus")
+	defer span.End()
+
+	account := &entity.Account{}
+
+	query := "SELECT id, role, status, suspended_until, status_reason, deleted_at FROM accounts WHERE id = $1"
+
+	if err := r.DB.GetContext(ctx, account, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return account, helper.NewNotFound("id", id.String())
+		}
+
+		log.Printf("failed to query get account status, err : %v\n", err)
+		return account, helper.NewInternal()
+	}
+
 	return account, nil
-}
\ No newline at end of file
+}
+
+// SetStatus applies and records an admin's change. A ban also withdraws the
+// open offers the account made as buyer.
+func (r *AccountRepositoryImpl) SetStatus(ctx context.Context, change *entity.AccountStatusChange) error {
+
+	ctx, span := helper.StartSpan(ctx, "AccountRepository.SetStatus")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin set account status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	query := `
+	UPDATE accounts 
+	SET status = $1, suspended_until = $2, status_reason = NULLIF($3, ''), updated_at = now()
+	WHERE id = $4 AND deleted_at IS NULL`
+
+	result, err := tx.ExecContext(ctx, query, change.Status, change.SuspendedUntil, change.Reason, change.AccountId)
+	if err != nil {
+		log.Printf("failed to query set account status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when set account status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewNotFound("id", change.AccountId.String())
+	}
+
+	query = `
+	INSERT INTO account_status_changes (account_id, status, suspended_until, reason, admin_id)
+	VALUES ($1, $2, $3, $4, $5)`
+
+	_, err = tx.ExecContext(ctx, query, change.AccountId, change.Status, change.SuspendedUntil, change.Reason, change.AdminId)
+	if err != nil {
+		log.Printf("failed to query record account status change, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if change.Status == entity.AccountBanned {
+		query = `
+		UPDATE transactions SET deleted = TRUE, updated_at = now()
+		WHERE buyer_id = $1 AND accepted = FALSE AND deleted = FALSE`
+
+		if _, err := tx.ExecContext(ctx, query, change.AccountId); err != nil {
+			log.Printf("failed to query withdraw offers of banned account, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit set account status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+func (r *AccountRepositoryImpl) GetStatusHistory(ctx context.Context, id uuid.UUID) ([]web.AccountStatusChange, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AccountRepository.GetStatusHistory")
+	defer span.End()
+
+	history := []web.AccountStatusChange{}
+
+	query := `
+	SELECT 
+		c.status, c.suspended_until, c.reason, c.admin_id, p.name as admin_name, c.created_at
+	FROM 
+		account_status_changes c
+	JOIN 
+		profiles p ON p.id = c.admin_id
+	WHERE 
+		c.account_id = $1
+	ORDER BY 
+		c.created_at DESC`
+
+	if err := r.DB.SelectContext(ctx, &history, query, id); err != nil {
+		log.Printf("failed to query account status history, err : %v\n", err)
+		return history, helper.NewInternal()
+	}
+
+	return history, nil
+}
+
+func (r *AccountRepositoryImpl) decrypt(account *entity.Account) error {
+
+	email, err := r.Keyring.Decrypt(account.Email)
+	if err != nil {
+		log.Printf("failed to decrypt email of account %v, err : %v\n", account.Id, err)
+		return helper.NewInternal()
+	}
+
+	account.Email = email
+
+	return nil
+}
@@ -0,0 +1,500 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type AuctionRepository interface {
+	Create(ctx context.Context, auction *entity.Auction) error
+	GetById(ctx context.Context, id uuid.UUID) (*entity.Auction, error)
+	GetOne(ctx context.Context, id uuid.UUID) (web.AuctionResponse, error)
+	GetBids(ctx context.Context, id uuid.UUID) ([]web.BidResponse, error)
+	GetOpen(ctx context.Context, page web.PageRequest) ([]web.AuctionResponse, int, error)
+	PlaceBid(ctx context.Context, bid *entity.Bid, now time.Time, extension time.Duration) error
+	Cancel(ctx context.Context, id uuid.UUID) error
+	CloseEnded(ctx context.Context, now time.Time, reserveUntil time.Time) (int64, error)
+}
+
+type AuctionRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewAuctionRepository(db *sqlx.DB) AuctionRepository {
+	return &AuctionRepositoryImpl{
+		DB: db,
+	}
+}
+
+const auctionColumns = `
+	auctions.id, auctions.product_id, products.name as product_name, COALESCE(products.thumbnail, '') as thumbnail,
+	auctions.seller_id, profiles.name as seller_name, auctions.start_price, auctions.reserve_price,
+	COALESCE(auctions.high_bid >= COALESCE(auctions.reserve_price, 0), FALSE) as reserve_met, auctions.min_increment,
+	auctions.high_bid, auctions.high_bidder_id, COALESCE(auctions.high_bid + auctions.min_increment, auctions.start_price) as minimum_bid,
+	auctions.bid_count, auctions.status, auctions.ends_at, auctions.transaction_id, auctions.created_at, auctions.closed_at
+`
+
+const auctionJoins = `
+	JOIN
+		products ON products.id = auctions.product_id
+	JOIN
+		profiles ON profiles.id = auctions.seller_id
+`
+
+// Create opens an auction of a product that is published and neither sold
+// nor reserved, one at a time
+func (r *AuctionRepositoryImpl) Create(ctx context.Context, auction *entity.Auction) error {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionRepository.Create")
+	defer span.End()
+
+	query := `
+	INSERT INTO
+		auctions
+		(product_id, seller_id, start_price, reserve_price, min_increment, ends_at)
+	SELECT
+		id, $2, $3, $4, $5, $6
+	FROM
+		products
+	WHERE
+		id = $1 AND published = TRUE AND sold = FALSE AND deleted = FALSE AND reserved_for IS NULL
+	RETURNING
+		id, status, bid_count, created_at, updated_at
+	`
+
+	err := r.DB.QueryRowxContext(ctx, query, auction.ProductId, auction.SellerId, auction.StartPrice, auction.ReservePrice,
+		auction.MinIncrement, auction.EndsAt).StructScan(auction)
+	if err != nil {
+		// an offer was accepted or the product changed meanwhile
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewBadRequest("this product cannot be auctioned right now")
+		}
+
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return helper.NewConflict("auction", auction.ProductId.String())
+		}
+
+		log.Printf("failed to query create auction, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+func (r *AuctionRepositoryImpl) GetById(ctx context.Context, id uuid.UUID) (*entity.Auction, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionRepository.GetById")
+	defer span.End()
+
+	auction := &entity.Auction{}
+
+	query := "SELECT * FROM auctions WHERE id = $1"
+
+	if err := r.DB.GetContext(ctx, auction, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return auction, helper.NewNotFound("auction id", id.String())
+		}
+
+		log.Printf("failed to query get auction by id, err : %v\n", err)
+		return auction, helper.NewInternal()
+	}
+
+	return auction, nil
+}
+
+func (r *AuctionRepositoryImpl) GetOne(ctx context.Context, id uuid.UUID) (web.AuctionResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionRepository.GetOne")
+	defer span.End()
+
+	auction := web.AuctionResponse{}
+
+	query := "SELECT " + auctionColumns + " FROM auctions " + auctionJoins + " WHERE auctions.id = $1"
+
+	if err := r.DB.GetContext(ctx, &auction, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return auction, helper.NewNotFound("auction id", id.String())
+		}
+
+		log.Printf("failed to query get auction, err : %v\n", err)
+		return auction, helper.NewInternal()
+	}
+
+	return auction, nil
+}
+
+// GetBids lists the bids of an auction, the latest first
+func (r *AuctionRepositoryImpl) GetBids(ctx context.Context, id uuid.UUID) ([]web.BidResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionRepository.GetBids")
+	defer span.End()
+
+	bids := []web.BidResponse{}
+
+	query := `
+	SELECT
+		bids.id, bids.bidder_id, profiles.name as bidder_name, bids.amount, bids.created_at
+	FROM
+		bids
+	JOIN
+		profiles ON profiles.id = bids.bidder_id
+	WHERE
+		bids.auction_id = $1
+	ORDER BY
+		bids.created_at DESC
+	`
+
+	if err := r.DB.SelectContext(ctx, &bids, query, id); err != nil {
+		log.Printf("failed to query get bids, err : %v\n", err)
+		return bids, helper.NewInternal()
+	}
+
+	return bids, nil
+}
+
+// GetOpen lists the running auctions, the one ending first first
+func (r *AuctionRepositoryImpl) GetOpen(ctx context.Context, page web.PageRequest) ([]web.AuctionResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionRepository.GetOpen")
+	defer span.End()
+
+	auctions := []web.AuctionResponse{}
+	total := 0
+
+	from := "FROM auctions " + auctionJoins + `
+	WHERE
+		auctions.status = 'OPEN'
+	`
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from); err != nil {
+		log.Printf("failed to query count open auctions, err : %v\n", err)
+		return auctions, total, helper.NewInternal()
+	}
+
+	query := "SELECT " + auctionColumns + " " + from + `
+	ORDER BY
+		auctions.ends_at
+	LIMIT $1 OFFSET $2
+	`
+
+	if err := r.DB.SelectContext(ctx, &auctions, query, page.Limit(), page.Offset()); err != nil {
+		log.Printf("failed to query get open auctions, err : %v\n", err)
+		return auctions, total, helper.NewInternal()
+	}
+
+	return auctions, total, nil
+}
+
+// PlaceBid records a bid that reaches the start price, or the high bid plus
+// the minimum increment, on an auction that did not end by now. The auction
+// row stays locked meanwhile so concurrent bids are taken one after another.
+// A bid less than extension before the end pushes the end to extension after
+// the bid. The outbid bidder is notified.
+func (r *AuctionRepositoryImpl) PlaceBid(ctx context.Context, bid *entity.Bid, now time.Time, extension time.Duration) error {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionRepository.PlaceBid")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin place bid, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	auction := &entity.Auction{}
+	if err := tx.GetContext(ctx, auction, "SELECT * FROM auctions WHERE id = $1 FOR UPDATE", bid.AuctionId); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewNotFound("auction id", bid.AuctionId.String())
+		}
+
+		log.Printf("failed to query get auction to bid on, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if auction.Status != entity.AuctionOpen || !now.Before(auction.EndsAt) {
+		return helper.NewBadRequest("this auction has ended")
+	}
+
+	if auction.SellerId == bid.BidderId {
+		return helper.NewBadRequest("you cannot bid on your own auction")
+	}
+
+	if bid.Amount < util.MinimumBid(auction.StartPrice, auction.HighBid, auction.MinIncrement) {
+		return helper.NewBadRequest("a bid must be at least the minimum bid")
+	}
+
+	query := `
+	INSERT INTO
+		bids (auction_id, bidder_id, amount)
+	VALUES
+		($1, $2, $3)
+	RETURNING
+		id, created_at
+	`
+
+	if err := tx.QueryRowxContext(ctx, query, bid.AuctionId, bid.BidderId, bid.Amount).StructScan(bid); err != nil {
+		log.Printf("failed to query place bid, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	endsAt := util.ExtendDeadline(auction.EndsAt, now, extension)
+
+	query = `
+	UPDATE
+		auctions
+	SET
+		high_bid = $1, high_bidder_id = $2, bid_count = bid_count + 1, ends_at = $3, updated_at = now()
+	WHERE
+		id = $4
+	`
+
+	if _, err := tx.ExecContext(ctx, query, bid.Amount, bid.BidderId, endsAt, bid.AuctionId); err != nil {
+		log.Printf("failed to query raise high bid, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if auction.HighBidderId.Valid && auction.HighBidderId.UUID != bid.BidderId {
+		query = `
+		INSERT INTO
+			notifications (account_id, kind, message)
+		SELECT
+			$1, 'OUTBID', 'you were outbid on ' || name || ', the high bid is now ' || $2::bigint
+		FROM
+			products
+		WHERE
+			id = $3
+		`
+
+		if _, err := tx.ExecContext(ctx, query, auction.HighBidderId.UUID, bid.Amount, auction.ProductId); err != nil {
+			log.Printf("failed to query notify outbid bidder, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit place bid, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// Cancel ends an open auction nobody bid on yet
+func (r *AuctionRepositoryImpl) Cancel(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionRepository.Cancel")
+	defer span.End()
+
+	query := `
+	UPDATE
+		auctions
+	SET
+		status = 'CANCELLED', closed_at = now(), updated_at = now()
+	WHERE
+		id = $1 AND status = 'OPEN' AND bid_count = 0
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf("failed to query cancel auction, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when cancel auction, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewBadRequest("only an open auction without bids can be cancelled")
+	}
+
+	return nil
+}
+
+// CloseEnded closes the open auctions that ended by now, each in its own
+// transaction. A high bid reaching the reserve price becomes an accepted offer
+// of the high bidder, which reserves the product until reserveUntil and opens
+// its order.
+func (r *AuctionRepositoryImpl) CloseEnded(ctx context.Context, now time.Time, reserveUntil time.Time) (int64, error) {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionRepository.CloseEnded")
+	defer span.End()
+
+	ids := []uuid.UUID{}
+
+	query := "SELECT id FROM auctions WHERE status = 'OPEN' AND ends_at <= $1 ORDER BY ends_at"
+
+	if err := r.DB.SelectContext(ctx, &ids, query, now); err != nil {
+		log.Printf("failed to query get ended auctions, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	closed := int64(0)
+	for _, id := range ids {
+		ok, err := r.close(ctx, id, now, reserveUntil)
+		if err != nil {
+			return closed, err
+		}
+
+		if ok {
+			closed++
+		}
+	}
+
+	return closed, nil
+}
+
+// close closes an auction unless a late bid pushed its end back meanwhile
+func (r *AuctionRepositoryImpl) close(ctx context.Context, id uuid.UUID, now time.Time, reserveUntil time.Time) (bool, error) {
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin close auction, err : %v\n", err)
+		return false, helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	auction := &entity.Auction{}
+	if err := tx.GetContext(ctx, auction, "SELECT * FROM auctions WHERE id = $1 FOR UPDATE", id); err != nil {
+		log.Printf("failed to query get ended auction, err : %v\n", err)
+		return false, helper.NewInternal()
+	}
+
+	if auction.Status != entity.AuctionOpen || auction.EndsAt.After(now) {
+		return false, nil
+	}
+
+	won := auction.HighBid.Valid && auction.HighBid.Int64 >= auction.ReservePrice.Int64
+
+	if won {
+		// the product is frozen while auctioned, but an admin may still have
+		// taken it down
+		available := false
+
+		query := `
+		SELECT
+			reserved_for IS NULL AND sold = FALSE AND deleted = FALSE
+		FROM
+			products
+		WHERE
+			id = $1
+		FOR UPDATE
+		`
+
+		if err := tx.GetContext(ctx, &available, query, auction.ProductId); err != nil {
+			log.Printf("failed to query check auctioned product, err : %v\n", err)
+			return false, helper.NewInternal()
+		}
+
+		won = available
+	}
+
+	if won {
+		transactionId := uuid.Nil
+
+		query := `
+		INSERT INTO
+			transactions
+			(buyer_id, seller_id, product_id, price_offer)
+		VALUES
+			($1, $2, $3, $4)
+		ON CONFLICT
+			(product_id, buyer_id) WHERE deleted = FALSE
+		DO UPDATE SET
+			price_offer = EXCLUDED.price_offer, updated_at = now()
+		RETURNING
+			id
+		`
+
+		err := tx.GetContext(ctx, &transactionId, query, auction.HighBidderId.UUID, auction.SellerId, auction.ProductId, auction.HighBid.Int64)
+		if err != nil {
+			log.Printf("failed to query create offer of auction winner, err : %v\n", err)
+			return false, helper.NewInternal()
+		}
+
+		err = insertProposal(ctx, tx, &entity.OfferProposal{
+			TransactionId: transactionId,
+			ProposedBy:    auction.HighBidderId.UUID,
+			Role:          entity.ProposalBuyer,
+			Amount:        auction.HighBid.Int64,
+			Note:          "winning bid of the auction",
+		})
+		if err != nil {
+			return false, err
+		}
+
+		err = acceptAndReserve(ctx, tx, transactionId, auction.ProductId, reserveUntil)
+		if err != nil {
+			return false, err
+		}
+
+		auction.Status = entity.AuctionSold
+		auction.TransactionId = uuid.NullUUID{UUID: transactionId, Valid: true}
+	} else {
+		auction.Status = entity.AuctionUnsold
+	}
+
+	query := `
+	UPDATE
+		auctions
+	SET
+		status = $1, transaction_id = $2, closed_at = $3, updated_at = $3
+	WHERE
+		id = $4
+	`
+
+	if _, err := tx.ExecContext(ctx, query, auction.Status, auction.TransactionId, now, id); err != nil {
+		log.Printf("failed to query close auction, err : %v\n", err)
+		return false, helper.NewInternal()
+	}
+
+	notifications := map[uuid.UUID]string{}
+	switch {
+	case won:
+		notifications[auction.SellerId] = "the auction ended with a winning bid of " + strconv.FormatInt(auction.HighBid.Int64, 10) + " on "
+		notifications[auction.HighBidderId.UUID] = "you won the auction, pay the order to get "
+	case auction.HighBid.Valid:
+		notifications[auction.SellerId] = "the auction ended below the reserve price on "
+		notifications[auction.HighBidderId.UUID] = "the auction ended below the reserve price on "
+	default:
+		notifications[auction.SellerId] = "the auction ended without bids on "
+	}
+
+	query = `
+	INSERT INTO
+		notifications (account_id, kind, message)
+	SELECT
+		$1, 'AUCTION_ENDED', $2 || name
+	FROM
+		products
+	WHERE
+		id = $3
+	`
+
+	for accountId, message := range notifications {
+		if _, err := tx.ExecContext(ctx, query, accountId, message, auction.ProductId); err != nil {
+			log.Printf("failed to query notify auction end, err : %v\n", err)
+			return false, helper.NewInternal()
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit close auction, err : %v\n", err)
+		return false, helper.NewInternal()
+	}
+
+	return true, nil
+}
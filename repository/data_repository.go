@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"log"
 
 	"github.com/RuhullahReza/SecondHand/helper"
@@ -13,14 +14,21 @@ import (
 )
 
 type DataRepository interface {
-	CreateCity(ctx context.Context, name string) error
-	DeleteCity(ctx context.Context, id uuid.UUID) error
-	CheckCityName(ctx context.Context, name string) error
-	GetAllCity(ctx context.Context) ([]web.DataResponse, error)
-	CreateCategory(ctx context.Context, name string) error
-	CheckCategory(ctx context.Context, name string) error
-	GetAllCategory(ctx context.Context) ([]web.DataResponse, error)
-	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	CreateCity(ctx context.Context, city *entity.City) error
+	DeleteCity(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error
+	RenameCity(ctx context.Context, id uuid.UUID, name string) error
+	CityUsage(ctx context.Context, id uuid.UUID) (web.CityUsageResponse, error)
+	FindCity(ctx context.Context, ref string) (*entity.City, error)
+	GetAllCity(ctx context.Context, provinceId uuid.NullUUID) ([]web.CityResponse, error)
+	FindProvince(ctx context.Context, ref string) (*entity.Province, error)
+	GetAllProvince(ctx context.Context) ([]web.DataResponse, error)
+	CreateCategory(ctx context.Context, category *entity.Category) error
+	FindCategory(ctx context.Context, ref string) (*entity.Category, error)
+	GetAllCategory(ctx context.Context) ([]web.CategoryResponse, error)
+	DeleteCategory(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error
+	RenameCategory(ctx context.Context, req web.RenameCategoryRequest) error
+	SetCategoryApproval(ctx context.Context, req web.CategoryApprovalRequest) error
+	CategoryUsage(ctx context.Context, id uuid.UUID) (web.CategoryUsageResponse, error)
 }
 
 type DataRepositoryImpl struct {
@@ -33,14 +41,21 @@ func NewDataRepository(db *sqlx.DB) DataRepository {
 	}
 }
 
-func (r *DataRepositoryImpl) CreateCity(ctx context.Context, name string) error {
+func (r *DataRepositoryImpl) CreateCity(ctx context.Context, city *entity.City) error {
 
-	query := "INSERT INTO cities (name) VALUES ($1)"
-	_, err := r.DB.ExecContext(ctx, query, name)
+	ctx, span := helper.StartSpan(ctx, "DataRepository.CreateCity")
+	defer span.End()
+
+	query := "INSERT INTO cities (name, province_id) VALUES ($1, $2)"
+	_, err := r.DB.ExecContext(ctx, query, city.Name, city.ProvinceId)
 
 	if err != nil {
 		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
-			return helper.NewConflict("city",name)
+			return helper.NewConflict("city", city.Name)
+		}
+
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			return helper.NewNotFound("province", city.ProvinceId.UUID.String())
 		}
 
 		log.Printf("failed to query create city, err : %v\n", err)
@@ -50,148 +65,473 @@ func (r *DataRepositoryImpl) CreateCity(ctx context.Context, name string) error
 	return nil
 }
 
-func (r *DataRepositoryImpl) DeleteCity(ctx context.Context, id uuid.UUID) error {
+// DeleteCity removes a city that no profile uses. With reassignTo set, the
+// profiles are moved to that city first in the same transaction.
+func (r *DataRepositoryImpl) DeleteCity(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
 
-	query := "DELETE FROM cities WHERE id = $1"
-	result, err := r.DB.ExecContext(ctx, query, id)
+	ctx, span := helper.StartSpan(ctx, "DataRepository.DeleteCity")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
 	if err != nil {
+		log.Printf("failed to begin delete city, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	name, err := lockCity(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if reassignTo != nil {
+		if *reassignTo == id {
+			return helper.NewBadRequest("cannot reassign to the deleted item")
+		}
+
+		target, err := lockCity(ctx, tx, *reassignTo)
+		if err != nil {
+			return err
+		}
+
+		query := "UPDATE profiles SET city = $1, updated_at = now() WHERE city = $2"
+		if _, err := tx.ExecContext(ctx, query, target, name); err != nil {
+			log.Printf("failed to reassign profiles to city, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	} else {
+		usage := web.CityUsageResponse{}
+		query := "SELECT COUNT(*) as profiles FROM profiles WHERE city = $1"
+		if err := tx.GetContext(ctx, &usage, query, name); err != nil {
+			log.Printf("failed to count city usage, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		if usage.Profiles > 0 {
+			return helper.NewInUse("City", name, fmt.Sprintf("%d profiles", usage.Profiles))
+		}
+	}
+
+	query := "DELETE FROM cities WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
 		log.Printf("failed to query delete city, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit delete city, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	
+	return nil
+}
+
+// RenameCity renames a city, profiles follow through their foreign key
+func (r *DataRepositoryImpl) RenameCity(ctx context.Context, id uuid.UUID, name string) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.RenameCity")
+	defer span.End()
+
+	query := "UPDATE cities SET name = $1 WHERE id = $2"
+	result, err := r.DB.ExecContext(ctx, query, name, id)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return helper.NewConflict("city", name)
+		}
+
+		log.Printf("failed to query rename city, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
 	row, err := result.RowsAffected()
 	if err != nil {
-		log.Printf("failed to get rows affected when delete city, err : %v\n", err)
+		log.Printf("failed to get rows affected when rename city, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
 	if row == 0 {
-		return helper.NewNotFound("City",id.String())
+		return helper.NewNotFound("City", id.String())
 	}
-	
+
 	return nil
 }
 
-func (r *DataRepositoryImpl) CheckCityName(ctx context.Context, name string) error {
+func (r *DataRepositoryImpl) CityUsage(ctx context.Context, id uuid.UUID) (web.CityUsageResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.CityUsage")
+	defer span.End()
+
+	usage := web.CityUsageResponse{}
+
+	query := `
+		SELECT COUNT(profiles.id) as profiles 
+		FROM cities 
+		LEFT JOIN profiles ON profiles.city = cities.name 
+		WHERE cities.id = $1 
+		GROUP BY cities.id`
+
+	if err := r.DB.GetContext(ctx, &usage, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return usage, helper.NewNotFound("City", id.String())
+		}
+
+		log.Printf("failed to query city usage, err : %v\n", err)
+		return usage, helper.NewInternal()
+	}
+
+	return usage, nil
+}
+
+// lockCity returns the name of the city and holds its row until tx ends
+func lockCity(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) (string, error) {
+
+	var name string
+
+	query := "SELECT name FROM cities WHERE id = $1 FOR UPDATE"
+	if err := tx.GetContext(ctx, &name, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return name, helper.NewNotFound("City", id.String())
+		}
+
+		log.Printf("failed to query lock city, err : %v\n", err)
+		return name, helper.NewInternal()
+	}
+
+	return name, nil
+}
+
+// FindCity resolves a city by id or, case-insensitively, by name
+func (r *DataRepositoryImpl) FindCity(ctx context.Context, ref string) (*entity.City, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.FindCity")
+	defer span.End()
 
 	city := &entity.City{}
 
-	query := "SELECT name FROM cities WHERE name=$1 LIMIT 1"
+	query := "SELECT * FROM cities WHERE id::text = lower($1) OR lower(name) = lower($1) LIMIT 1"
 
-	if err := r.DB.GetContext(ctx, city, query, name); err != nil {
+	if err := r.DB.GetContext(ctx, city, query, ref); err != nil {
 		if err.Error() == "sql: no rows in result set" {
-			return helper.NewNotFound("city name", name)
+			return city, helper.NewNotFound("city name", ref)
 		}
 
-		log.Printf("failed to query check city name, err : %v\n", err)
-		return helper.NewInternal()
+		log.Printf("failed to query find city, err : %v\n", err)
+		return city, helper.NewInternal()
 	}
 
-	return nil
+	return city, nil
 }
 
-func (r *DataRepositoryImpl) GetAllCity(ctx context.Context) ([]web.DataResponse, error) {
+func (r *DataRepositoryImpl) GetAllCity(ctx context.Context, provinceId uuid.NullUUID) ([]web.CityResponse, error) {
 
-	cities := []web.DataResponse{}
+	ctx, span := helper.StartSpan(ctx, "DataRepository.GetAllCity")
+	defer span.End()
 
-	query := "SELECT id, name FROM cities"
-	rows, err := r.DB.QueryContext(ctx,query)
-	if err != nil {
+	cities := []web.CityResponse{}
+
+	query := `
+		SELECT 
+			cities.id, cities.name, cities.province_id, COALESCE(provinces.name, '') as province
+		FROM 
+			cities
+		LEFT JOIN 
+			provinces ON provinces.id = cities.province_id
+		WHERE 
+			$1::uuid IS NULL OR cities.province_id = $1
+		ORDER BY cities.name`
+
+	if err := r.DB.SelectContext(ctx, &cities, query, provinceId); err != nil {
 		log.Printf("failed to query get all city, err : %v\n", err)
 		return cities, helper.NewInternal()
 	}
+	
+	return cities, nil
+}
 
-	for rows.Next(){
-		city := web.DataResponse{}
-		err := rows.Scan(&city.Id,&city.Name)
-		if err != nil {
-			log.Printf("failed to scanning city, err : %v\n", err)
-			return cities, helper.NewInternal()
+// FindProvince resolves a province by id or, case-insensitively, by name
+func (r *DataRepositoryImpl) FindProvince(ctx context.Context, ref string) (*entity.Province, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.FindProvince")
+	defer span.End()
+
+	province := &entity.Province{}
+
+	query := "SELECT * FROM provinces WHERE id::text = lower($1) OR lower(name) = lower($1) LIMIT 1"
+
+	if err := r.DB.GetContext(ctx, province, query, ref); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return province, helper.NewNotFound("province", ref)
 		}
 
-		cities = append(cities, city)
+		log.Printf("failed to query find province, err : %v\n", err)
+		return province, helper.NewInternal()
 	}
-	
-	return cities, nil
+
+	return province, nil
+}
+
+func (r *DataRepositoryImpl) GetAllProvince(ctx context.Context) ([]web.DataResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.GetAllProvince")
+	defer span.End()
+
+	provinces := []web.DataResponse{}
+
+	query := "SELECT id, name FROM provinces ORDER BY name"
+	if err := r.DB.SelectContext(ctx, &provinces, query); err != nil {
+		log.Printf("failed to query get all province, err : %v\n", err)
+		return provinces, helper.NewInternal()
+	}
+
+	return provinces, nil
 }
 
-func (r *DataRepositoryImpl) CreateCategory(ctx context.Context, name string) error {
+func (r *DataRepositoryImpl) CreateCategory(ctx context.Context, category *entity.Category) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.CreateCategory")
+	defer span.End()
 
-	query := "INSERT INTO categories (name) VALUES ($1) "
-	_, err := r.DB.ExecContext(ctx, query, name)
+	query := "INSERT INTO categories (name, slug, parent_id, sort_order, requires_approval) VALUES ($1, $2, $3, $4, $5) "
+	_, err := r.DB.ExecContext(ctx, query, category.Name, category.Slug, category.ParentId, category.SortOrder,
+		category.RequiresApproval)
 
 	if err != nil {
-		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
-			return helper.NewConflict("category",name)
+		if err, ok := err.(*pq.Error); ok {
+			switch {
+			case err.Code.Name() == "unique_violation" && err.Constraint == "categories_slug_key":
+				return helper.NewConflict("category slug", category.Slug)
+			case err.Code.Name() == "unique_violation":
+				return helper.NewConflict("category", category.Name)
+			case err.Code.Name() == "foreign_key_violation":
+				return helper.NewNotFound("parent category", category.ParentId.UUID.String())
+			}
 		}
 
-		log.Printf("failed to create category: %v, err: %v\n", name, err)
+		log.Printf("failed to create category: %v, err: %v\n", category.Name, err)
 		return helper.NewInternal()
 	}
 
 	return nil
 }
 
-func (r *DataRepositoryImpl) CheckCategory(ctx context.Context, name string) error {
+// FindCategory resolves a category by slug, falling back to its name so
+// clients still sending the old free-text category keep working
+func (r *DataRepositoryImpl) FindCategory(ctx context.Context, ref string) (*entity.Category, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.FindCategory")
+	defer span.End()
 
 	category := &entity.Category{}
 
-	query := "SELECT name FROM categories WHERE name=$1 LIMIT 1"
+	query := `
+		SELECT * FROM categories 
+		WHERE slug = lower($1) OR lower(name) = lower($1)
+		ORDER BY slug = lower($1) DESC
+		LIMIT 1`
 
-	if err := r.DB.GetContext(ctx, category, query, name); err != nil {
+	if err := r.DB.GetContext(ctx, category, query, ref); err != nil {
 		if err.Error() == "sql: no rows in result set" {
-			return helper.NewNotFound("category name", name)
+			return category, helper.NewNotFound("category", ref)
 		}
 
-		log.Printf("failed to query get check category, err : %v\n", err)
+		log.Printf("failed to query find category, err : %v\n", err)
+		return category, helper.NewInternal()
+	}
+
+	return category, nil
+}
+
+// DeleteCategory removes a category that has no products and no
+// subcategories. With reassignTo set, both are moved under that category first
+// in the same transaction.
+func (r *DataRepositoryImpl) DeleteCategory(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.DeleteCategory")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin delete category, err : %v\n", err)
 		return helper.NewInternal()
 	}
+	defer tx.Rollback()
+
+	category := &entity.Category{}
 
+	query := "SELECT * FROM categories WHERE id = $1 FOR UPDATE"
+	if err := tx.GetContext(ctx, category, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewNotFound("Category", id.String())
+		}
+
+		log.Printf("failed to query lock category, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if reassignTo != nil {
+		// the target must live outside the deleted subtree, otherwise moving
+		// the children under it would create a cycle
+		var inside bool
+		query := `
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE id = $1
+				UNION
+				SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id
+			)
+			SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`
+		if err := tx.GetContext(ctx, &inside, query, id, *reassignTo); err != nil {
+			log.Printf("failed to query category subtree, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		if inside {
+			return helper.NewBadRequest("cannot reassign to the deleted item")
+		}
+
+		query = "UPDATE products SET category_id = $1, updated_at = now() WHERE category_id = $2"
+		if _, err := tx.ExecContext(ctx, query, *reassignTo, id); err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+				return helper.NewNotFound("Category", reassignTo.String())
+			}
+
+			log.Printf("failed to reassign products to category, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		query = "UPDATE categories SET parent_id = $1 WHERE parent_id = $2"
+		if _, err := tx.ExecContext(ctx, query, *reassignTo, id); err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+				return helper.NewNotFound("Category", reassignTo.String())
+			}
+
+			log.Printf("failed to reassign subcategories to category, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	}
+
+	query = "DELETE FROM categories WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "foreign_key_violation" {
+			usage, err := r.CategoryUsage(ctx, id)
+			if err != nil {
+				return err
+			}
+
+			return helper.NewInUse("Category", category.Name,
+				fmt.Sprintf("%d products and %d subcategories", usage.Products, usage.Subcategories))
+		}
+
+		log.Printf("failed to query delete category, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit delete category, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	
 	return nil
 }
 
-func (r *DataRepositoryImpl) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+// RenameCategory changes the name and, when given, the slug. Products refer
+// to categories by id so they follow automatically.
+func (r *DataRepositoryImpl) RenameCategory(ctx context.Context, req web.RenameCategoryRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.RenameCategory")
+	defer span.End()
 
-	query := "DELETE FROM categories WHERE id = $1"
-	result, err := r.DB.ExecContext(ctx, query, id)
+	query := "UPDATE categories SET name = $1, slug = COALESCE(NULLIF($2, ''), slug) WHERE id = $3"
+	result, err := r.DB.ExecContext(ctx, query, req.Name, req.Slug, req.Id)
 	if err != nil {
-		log.Printf("failed to query delete category, err : %v\n", err)
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			if err.Constraint == "categories_slug_key" {
+				return helper.NewConflict("category slug", req.Slug)
+			}
+			return helper.NewConflict("category", req.Name)
+		}
+
+		log.Printf("failed to query rename category, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
 	row, err := result.RowsAffected()
 	if err != nil {
-		log.Printf("failed to get rows affected when delete category, err : %v\n", err)
+		log.Printf("failed to get rows affected when rename category, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
 	if row == 0 {
-		return helper.NewNotFound("Category",id.String())
+		return helper.NewNotFound("Category", req.Id.String())
 	}
-	
+
 	return nil
 }
 
-func (r *DataRepositoryImpl) GetAllCategory(ctx context.Context) ([]web.DataResponse, error) {
+// SetCategoryApproval turns the review of new listings on or off. Listings
+// already approved in the category stay published.
+func (r *DataRepositoryImpl) SetCategoryApproval(ctx context.Context, req web.CategoryApprovalRequest) error {
 
-	categories := []web.DataResponse{}
+	ctx, span := helper.StartSpan(ctx, "DataRepository.SetCategoryApproval")
+	defer span.End()
 
-	query := "SELECT id, name FROM categories"
-	rows, err := r.DB.QueryContext(ctx,query)
+	query := "UPDATE categories SET requires_approval = $1 WHERE id = $2"
+	result, err := r.DB.ExecContext(ctx, query, *req.RequiresApproval, req.Id)
 	if err != nil {
-		log.Printf("failed to query getting all category, err : %v\n", err)
-		return categories, helper.NewInternal()
+		log.Printf("failed to query set category approval, err : %v\n", err)
+		return helper.NewInternal()
 	}
 
-	for rows.Next(){
-		category := web.DataResponse{}
-		err := rows.Scan(&category.Id,&category.Name)
-		if err != nil {
-			log.Printf("failed to scanning category, err : %v\n", err)
-			return categories, helper.NewInternal()
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when set category approval, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewNotFound("Category", req.Id.String())
+	}
+
+	return nil
+}
+
+func (r *DataRepositoryImpl) CategoryUsage(ctx context.Context, id uuid.UUID) (web.CategoryUsageResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.CategoryUsage")
+	defer span.End()
+
+	usage := web.CategoryUsageResponse{}
+
+	query := `
+		SELECT 
+			(SELECT COUNT(*) FROM products WHERE category_id = categories.id) as products,
+			(SELECT COUNT(*) FROM categories child WHERE child.parent_id = categories.id) as subcategories
+		FROM categories 
+		WHERE id = $1`
+
+	if err := r.DB.GetContext(ctx, &usage, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return usage, helper.NewNotFound("Category", id.String())
 		}
-		categories = append(categories, category)
+
+		log.Printf("failed to query category usage, err : %v\n", err)
+		return usage, helper.NewInternal()
+	}
+
+	return usage, nil
+}
+
+func (r *DataRepositoryImpl) GetAllCategory(ctx context.Context) ([]web.CategoryResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DataRepository.GetAllCategory")
+	defer span.End()
+
+	categories := []web.CategoryResponse{}
+
+	query := "SELECT id, name, slug, parent_id, sort_order, requires_approval FROM categories ORDER BY sort_order, name"
+	if err := r.DB.SelectContext(ctx, &categories, query); err != nil {
+		log.Printf("failed to query getting all category, err : %v\n", err)
+		return categories, helper.NewInternal()
 	}
 	
 	return categories, nil
-}
\ No newline at end of file
+}
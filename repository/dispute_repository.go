@@ -0,0 +1,540 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *entity.Dispute) error
+	GetById(ctx context.Context, id uuid.UUID) (*entity.Dispute, error)
+	GetOne(ctx context.Context, id uuid.UUID) (web.DisputeResponse, error)
+	GetMessages(ctx context.Context, id uuid.UUID) ([]web.DisputeMessageResponse, error)
+	GetByAccount(ctx context.Context, accountId uuid.UUID, filter web.DisputeFilter, page web.PageRequest) ([]web.DisputeResponse, int, error)
+	GetQueue(ctx context.Context, filter web.DisputeFilter, page web.PageRequest) ([]web.DisputeResponse, int, error)
+	AddMessage(ctx context.Context, message *entity.DisputeMessage, party string, respondBy time.Time) error
+	Claim(ctx context.Context, id uuid.UUID) error
+	Unclaim(ctx context.Context, id uuid.UUID) error
+	Resolve(ctx context.Context, dispute *entity.Dispute, order *entity.Order, from string, payment *entity.Payment) error
+	CheckOpen(ctx context.Context, productId uuid.UUID) (bool, error)
+	EscalateOverdue(ctx context.Context, now time.Time) (int64, error)
+}
+
+type DisputeRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewDisputeRepository(db *sqlx.DB) DisputeRepository {
+	return &DisputeRepositoryImpl{
+		DB: db,
+	}
+}
+
+const disputeColumns = `
+	disputes.id, disputes.transaction_id, disputes.order_id, orders.status as order_status, orders.price,
+	disputes.product_id, products.name as product_name, disputes.buyer_id, buyer.name as buyer_name,
+	disputes.seller_id, seller.name as seller_name, disputes.reason, disputes.details, disputes.status,
+	disputes.awaiting, disputes.respond_by, disputes.resolution, disputes.refund_amount, disputes.resolution_note,
+	disputes.created_at, disputes.escalated_at, disputes.resolved_at
+`
+
+const disputeJoins = `
+	JOIN 
+		orders ON orders.id = disputes.order_id
+	JOIN 
+		products ON products.id = disputes.product_id
+	JOIN 
+		profiles buyer ON buyer.id = disputes.buyer_id
+	JOIN 
+		profiles seller ON seller.id = disputes.seller_id
+`
+
+// Create opens a dispute for the seller to answer, one per offer at a time
+func (r *DisputeRepositoryImpl) Create(ctx context.Context, dispute *entity.Dispute) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.Create")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin create dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO 
+		disputes
+		(transaction_id, order_id, product_id, buyer_id, seller_id, reason, details, awaiting, respond_by)
+	VALUES 
+		($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	RETURNING 
+		id, status, created_at, updated_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query, dispute.TransactionId, dispute.OrderId, dispute.ProductId, dispute.BuyerId,
+		dispute.SellerId, dispute.Reason, dispute.Details, dispute.Awaiting, dispute.RespondBy).StructScan(dispute)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return helper.NewConflict("dispute", dispute.TransactionId.String())
+		}
+
+		log.Printf("failed to query create dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	query = `
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	SELECT 
+		$1, 'DISPUTE_UPDATED', 'the buyer of ' || name || ' opened a dispute, answer it before ' || to_char($2::timestamptz, 'YYYY-MM-DD HH24:MI TZ')
+	FROM 
+		products
+	WHERE 
+		id = $3
+	`
+
+	if _, err := tx.ExecContext(ctx, query, dispute.SellerId, dispute.RespondBy, dispute.ProductId); err != nil {
+		log.Printf("failed to query notify dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit create dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+func (r *DisputeRepositoryImpl) GetById(ctx context.Context, id uuid.UUID) (*entity.Dispute, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.GetById")
+	defer span.End()
+
+	dispute := &entity.Dispute{}
+
+	query := "SELECT * FROM disputes WHERE id = $1"
+
+	if err := r.DB.GetContext(ctx, dispute, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return dispute, helper.NewNotFound("dispute id", id.String())
+		}
+
+		log.Printf("failed to query get dispute by id, err : %v\n", err)
+		return dispute, helper.NewInternal()
+	}
+
+	return dispute, nil
+}
+
+func (r *DisputeRepositoryImpl) GetOne(ctx context.Context, id uuid.UUID) (web.DisputeResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.GetOne")
+	defer span.End()
+
+	dispute := web.DisputeResponse{}
+
+	query := "SELECT " + disputeColumns + " FROM disputes " + disputeJoins + " WHERE disputes.id = $1"
+
+	if err := r.DB.GetContext(ctx, &dispute, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return dispute, helper.NewNotFound("dispute id", id.String())
+		}
+
+		log.Printf("failed to query get dispute, err : %v\n", err)
+		return dispute, helper.NewInternal()
+	}
+
+	return dispute, nil
+}
+
+// GetMessages lists the message trail of a dispute, oldest first
+func (r *DisputeRepositoryImpl) GetMessages(ctx context.Context, id uuid.UUID) ([]web.DisputeMessageResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.GetMessages")
+	defer span.End()
+
+	messages := []web.DisputeMessageResponse{}
+
+	query := `
+	SELECT 
+		dispute_messages.id, dispute_messages.author_id, COALESCE(profiles.name, '') as author_name,
+		dispute_messages.body, dispute_messages.image_url, dispute_messages.created_at
+	FROM 
+		dispute_messages
+	LEFT JOIN 
+		profiles ON profiles.id = dispute_messages.author_id
+	WHERE 
+		dispute_messages.dispute_id = $1
+	ORDER BY 
+		dispute_messages.created_at
+	`
+
+	if err := r.DB.SelectContext(ctx, &messages, query, id); err != nil {
+		log.Printf("failed to query get dispute messages, err : %v\n", err)
+		return messages, helper.NewInternal()
+	}
+
+	return messages, nil
+}
+
+// GetByAccount lists the disputes an account is a party of, the latest first
+func (r *DisputeRepositoryImpl) GetByAccount(ctx context.Context, accountId uuid.UUID, filter web.DisputeFilter, page web.PageRequest) ([]web.DisputeResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.GetByAccount")
+	defer span.End()
+
+	from := "FROM disputes " + disputeJoins + `
+	WHERE 
+		(disputes.buyer_id = $1 OR disputes.seller_id = $1) AND ($2 = '' OR disputes.status = $2)
+	`
+
+	order := `
+	ORDER BY 
+		disputes.created_at DESC
+	LIMIT $3 OFFSET $4
+	`
+
+	return r.queryDisputes(ctx, from, order, page, accountId, filter.Status)
+}
+
+// GetQueue lists the unresolved disputes for admins, escalated ones first and
+// then the oldest
+func (r *DisputeRepositoryImpl) GetQueue(ctx context.Context, filter web.DisputeFilter, page web.PageRequest) ([]web.DisputeResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.GetQueue")
+	defer span.End()
+
+	from := "FROM disputes " + disputeJoins + `
+	WHERE 
+		($1 = '' AND disputes.status <> 'RESOLVED') OR disputes.status = $1
+	`
+
+	order := `
+	ORDER BY 
+		disputes.status = 'ESCALATED' DESC, disputes.created_at
+	LIMIT $2 OFFSET $3
+	`
+
+	return r.queryDisputes(ctx, from, order, page, filter.Status)
+}
+
+// queryDisputes pages the disputes matching from, whose parameters are args,
+// by order, which takes the limit and offset right after args. The total is
+// counted apart from the page.
+func (r *DisputeRepositoryImpl) queryDisputes(ctx context.Context, from string, order string, page web.PageRequest, args ...interface{}) ([]web.DisputeResponse, int, error) {
+
+	disputes := []web.DisputeResponse{}
+	total := 0
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from, args...); err != nil {
+		log.Printf("failed to query count disputes, err : %v\n", err)
+		return disputes, total, helper.NewInternal()
+	}
+
+	query := "SELECT " + disputeColumns + " " + from + order
+
+	if err := r.DB.SelectContext(ctx, &disputes, query, append(args, page.Limit(), page.Offset())...); err != nil {
+		log.Printf("failed to query get disputes, err : %v\n", err)
+		return disputes, total, helper.NewInternal()
+	}
+
+	return disputes, total, nil
+}
+
+// AddMessage adds a message to an unresolved dispute and lets the parties
+// other than the author know. When party is the one the dispute waits for,
+// it then waits for the other party until respondBy.
+func (r *DisputeRepositoryImpl) AddMessage(ctx context.Context, message *entity.DisputeMessage, party string, respondBy time.Time) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.AddMessage")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin add dispute message, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO 
+		dispute_messages (dispute_id, author_id, body, image_url)
+	SELECT 
+		id, $2, $3, $4
+	FROM 
+		disputes
+	WHERE 
+		id = $1 AND status <> 'RESOLVED'
+	RETURNING 
+		id, created_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query, message.DisputeId, message.AuthorId, message.Body, message.ImageUrl).StructScan(message)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewBadRequest("the dispute was already resolved")
+		}
+
+		log.Printf("failed to query add dispute message, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	query = `
+	UPDATE 
+		disputes
+	SET 
+		awaiting = CASE WHEN $1 = 'BUYER' THEN 'SELLER' ELSE 'BUYER' END, respond_by = $2, updated_at = now()
+	WHERE 
+		id = $3 AND status = 'OPEN' AND awaiting = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, party, respondBy, message.DisputeId); err != nil {
+		log.Printf("failed to query pass dispute to the other party, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	query = `
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	SELECT 
+		party, 'DISPUTE_UPDATED', 'there is a new message in the dispute about ' || products.name
+	FROM 
+		disputes
+	JOIN 
+		products ON products.id = disputes.product_id
+	CROSS JOIN 
+		unnest(ARRAY[disputes.buyer_id, disputes.seller_id]) as party
+	WHERE 
+		disputes.id = $1 AND party <> $2
+	`
+
+	if _, err := tx.ExecContext(ctx, query, message.DisputeId, message.AuthorId); err != nil {
+		log.Printf("failed to query notify dispute message, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit add dispute message, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// Resolve records the decision on a claimed dispute together with what
+// it does to the money and the order: the payment, when given and still held,
+// is refunded or released less the refund, and the order moves from from to
+// its status. Both parties are notified.
+func (r *DisputeRepositoryImpl) Resolve(ctx context.Context, dispute *entity.Dispute, order *entity.Order, from string, payment *entity.Payment) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.Resolve")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin resolve dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	query := `
+	UPDATE 
+		disputes
+	SET 
+		status = 'RESOLVED', awaiting = NULL, respond_by = NULL, resolution = $1, refund_amount = $2,
+		resolution_note = $3, resolved_by = $4, resolved_at = now(), updated_at = now()
+	WHERE 
+		id = $5 AND status = 'RESOLVING'
+	`
+
+	result, err := tx.ExecContext(ctx, query, dispute.Resolution, dispute.RefundAmount, dispute.ResolutionNote, dispute.ResolvedBy, dispute.Id)
+	if err != nil {
+		log.Printf("failed to query resolve dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when resolve dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewBadRequest("the dispute is not being resolved")
+	}
+
+	if payment != nil {
+		held := &entity.Payment{}
+		if err := tx.GetContext(ctx, held, "SELECT * FROM payments WHERE id = $1 FOR UPDATE", payment.Id); err != nil {
+			log.Printf("failed to query get disputed payment, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		if held.Status == entity.PaymentHeld {
+			held.Fee, held.RefundedAmount = payment.Fee, payment.RefundedAmount
+			if err := movePayment(ctx, tx, held, payment.Status); err != nil {
+				return err
+			}
+		}
+	}
+
+	if order.Status != from {
+		if err := moveOrder(ctx, tx, order, from); err != nil {
+			return err
+		}
+	}
+
+	query = `
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	SELECT 
+		party, 'DISPUTE_UPDATED', 'the dispute about ' || products.name || ' was resolved: ' || lower(replace($2, '_', ' '))
+	FROM 
+		disputes
+	JOIN 
+		products ON products.id = disputes.product_id
+	CROSS JOIN 
+		unnest(ARRAY[disputes.buyer_id, disputes.seller_id]) as party
+	WHERE 
+		disputes.id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, dispute.Id, dispute.Resolution); err != nil {
+		log.Printf("failed to query notify dispute resolution, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit resolve dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// Claim marks an open or escalated dispute as RESOLVING, so only one admin
+// gets to move its money
+func (r *DisputeRepositoryImpl) Claim(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.Claim")
+	defer span.End()
+
+	query := `
+	UPDATE 
+		disputes
+	SET 
+		status = 'RESOLVING', awaiting = NULL, respond_by = NULL, updated_at = now()
+	WHERE 
+		id = $1 AND status IN ('OPEN', 'ESCALATED')
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf("failed to query claim dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when claim dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewBadRequest("the dispute is already being resolved")
+	}
+
+	return nil
+}
+
+// Unclaim hands a RESOLVING dispute whose money could not be moved back to
+// the admins as ESCALATED
+func (r *DisputeRepositoryImpl) Unclaim(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.Unclaim")
+	defer span.End()
+
+	query := "UPDATE disputes SET status = 'ESCALATED', escalated_at = COALESCE(escalated_at, now()), updated_at = now() WHERE id = $1 AND status = 'RESOLVING'"
+
+	if _, err := r.DB.ExecContext(ctx, query, id); err != nil {
+		log.Printf("failed to query unclaim dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// CheckOpen tells whether a product has an unresolved dispute, which freezes
+// the product, its accepted offer and its order
+func (r *DisputeRepositoryImpl) CheckOpen(ctx context.Context, productId uuid.UUID) (bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.CheckOpen")
+	defer span.End()
+
+	open := false
+
+	query := "SELECT EXISTS (SELECT 1 FROM disputes WHERE product_id = $1 AND status <> 'RESOLVED')"
+
+	if err := r.DB.GetContext(ctx, &open, query, productId); err != nil {
+		log.Printf("failed to query check open dispute, err : %v\n", err)
+		return open, helper.NewInternal()
+	}
+
+	return open, nil
+}
+
+// EscalateOverdue hands the open disputes whose awaited party did not answer
+// by now over to the admins and lets both parties know
+func (r *DisputeRepositoryImpl) EscalateOverdue(ctx context.Context, now time.Time) (int64, error) {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeRepository.EscalateOverdue")
+	defer span.End()
+
+	query := `
+	WITH escalated AS (
+		UPDATE 
+			disputes
+		SET 
+			status = 'ESCALATED', awaiting = NULL, respond_by = NULL, escalated_at = $1, updated_at = $1
+		FROM 
+			disputes overdue
+		WHERE 
+			disputes.id = overdue.id AND disputes.status = 'OPEN' AND disputes.respond_by < $1
+		RETURNING 
+			disputes.id, disputes.product_id, disputes.buyer_id, disputes.seller_id, overdue.awaiting
+	), notified AS (
+		INSERT INTO 
+			notifications (account_id, kind, message)
+		SELECT 
+			party, 'DISPUTE_UPDATED', 'the dispute about ' || products.name || ' was escalated to an admin, the ' || lower(escalated.awaiting) || ' did not answer in time'
+		FROM 
+			escalated
+		JOIN 
+			products ON products.id = escalated.product_id
+		CROSS JOIN 
+			unnest(ARRAY[escalated.buyer_id, escalated.seller_id]) as party
+	)
+	SELECT 
+		COUNT(*)
+	FROM 
+		escalated
+	`
+
+	escalated := int64(0)
+	if err := r.DB.GetContext(ctx, &escalated, query, now); err != nil {
+		log.Printf("failed to query escalate overdue disputes, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	return escalated, nil
+}
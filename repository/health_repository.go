@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/jmoiron/sqlx"
+)
+
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (uint, bool, error)
+}
+
+type HealthRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewHealthRepository(db *sqlx.DB) HealthRepository {
+	return &HealthRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *HealthRepositoryImpl) Ping(ctx context.Context) error {
+
+	ctx, span := helper.StartSpan(ctx, "HealthRepository.Ping")
+	defer span.End()
+
+	if err := r.DB.PingContext(ctx); err != nil {
+		log.Printf("failed to ping database, err : %v\n", err)
+		return helper.NewServiceUnavailable()
+	}
+
+	return nil
+}
+
+func (r *HealthRepositoryImpl) MigrationVersion(ctx context.Context) (uint, bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "HealthRepository.MigrationVersion")
+	defer span.End()
+
+	var migration struct {
+		Version uint `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+
+	query := "SELECT version, dirty FROM schema_migrations LIMIT 1"
+
+	if err := r.DB.GetContext(ctx, &migration, query); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return 0, false, helper.NewNotFound("schema_migrations", "version")
+		}
+
+		log.Printf("failed to query migration version, err : %v\n", err)
+		return 0, false, helper.NewServiceUnavailable()
+	}
+
+	return migration.Version, migration.Dirty, nil
+}
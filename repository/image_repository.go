@@ -11,6 +11,7 @@ import (
 	"github.com/cloudinary/cloudinary-go/api/uploader"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/net/context"
 )
 
@@ -22,6 +23,7 @@ type ImageRepository interface {
 	GetByProductId(ctx context.Context, id uuid.UUID) ([]entity.ProductImage, error) 
 	GetPathById(ctx context.Context, id uuid.UUID) (*entity.Image, error)
 	GetPublicId(url string) string
+	Ping(ctx context.Context) error
 }
 
 type ImageRepositoryImpl struct {
@@ -38,8 +40,14 @@ func NewImageRepository(cld *cloudinary.Cloudinary, db *sqlx.DB) ImageRepository
 
 func (r *ImageRepositoryImpl) Upload(ctx context.Context, input interface{}, folder string) (string, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ImageRepository.Upload")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("storage.system", "cloudinary"), attribute.String("storage.folder", folder))
+
 	uploadParam, err := r.CLD.Upload.Upload(ctx, input, uploader.UploadParams{Folder: folder})
 	if err != nil {
+		span.RecordError(err)
 		log.Printf("failed to upload image, err : %v\n", err)
 		return "", helper.NewInternal()
 	}
@@ -49,9 +57,15 @@ func (r *ImageRepositoryImpl) Upload(ctx context.Context, input interface{}, fol
 
 func (r *ImageRepositoryImpl) Delete(ctx context.Context, id string) error {
 
+	ctx, span := helper.StartSpan(ctx, "ImageRepository.Delete")
+	defer span.End()
+
 	publicId := fmt.Sprintf("secondHand-go/%s", r.GetPublicId(id))
+	span.SetAttributes(attribute.String("storage.system", "cloudinary"), attribute.String("storage.public_id", publicId))
+
 	_, err := r.CLD.Upload.Destroy(ctx, uploader.DestroyParams {PublicID: publicId})
 	if err != nil {
+		span.RecordError(err)
 		log.Printf("failed to delete image, err : %v\n", err)
 		return helper.NewInternal()
 	}
@@ -59,6 +73,28 @@ func (r *ImageRepositoryImpl) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *ImageRepositoryImpl) Ping(ctx context.Context) error {
+
+	ctx, span := helper.StartSpan(ctx, "ImageRepository.Ping")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("storage.system", "cloudinary"))
+
+	result, err := r.CLD.Admin.Ping(ctx)
+	if err != nil {
+		span.RecordError(err)
+		log.Printf("failed to ping image storage, err : %v\n", err)
+		return helper.NewServiceUnavailable()
+	}
+
+	if result.Status != "ok" {
+		log.Printf("image storage ping returned status %q, err : %v\n", result.Status, result.Error.Message)
+		return helper.NewServiceUnavailable()
+	}
+
+	return nil
+}
+
 func (r *ImageRepositoryImpl) GetPublicId(url string) string {
 
 	urlSplitted := strings.Split(url,"/")
@@ -70,6 +106,9 @@ func (r *ImageRepositoryImpl) GetPublicId(url string) string {
 
 func (r *ImageRepositoryImpl) Create(ctx context.Context, image *entity.Image) error {
 
+	ctx, span := helper.StartSpan(ctx, "ImageRepository.Create")
+	defer span.End()
+
 	query := `
 	INSERT INTO 
 		images 
@@ -89,6 +128,9 @@ func (r *ImageRepositoryImpl) Create(ctx context.Context, image *entity.Image) e
 
 func (r *ImageRepositoryImpl) DeleteById(ctx context.Context, id uuid.UUID) error {
 
+	ctx, span := helper.StartSpan(ctx, "ImageRepository.DeleteById")
+	defer span.End()
+
 	query := `
 	DELETE FROM
 		images
@@ -107,6 +149,9 @@ func (r *ImageRepositoryImpl) DeleteById(ctx context.Context, id uuid.UUID) erro
 
 func (r *ImageRepositoryImpl) GetPathById(ctx context.Context, id uuid.UUID) (*entity.Image, error) {
 	
+	ctx, span := helper.StartSpan(ctx, "ImageRepository.GetPathById")
+	defer span.End()
+
 	image := &entity.Image{}
 
 	query := `
@@ -134,6 +179,9 @@ func (r *ImageRepositoryImpl) GetPathById(ctx context.Context, id uuid.UUID) (*e
 
 func (r *ImageRepositoryImpl) GetByProductId(ctx context.Context, id uuid.UUID) ([]entity.ProductImage, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ImageRepository.GetByProductId")
+	defer span.End()
+
 	images := []entity.ProductImage{}
 
 	query := `
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type JobRepository interface {
+	TryLock(ctx context.Context, job string) (func(), bool, error)
+	StartRun(ctx context.Context, job string) (uuid.UUID, error)
+	FinishRun(ctx context.Context, id uuid.UUID, affected int64, jobErr error) error
+	GetRuns(ctx context.Context, filter web.JobRunFilter, page web.PageRequest) ([]web.JobRunResponse, int, error)
+}
+
+type JobRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewJobRepository(db *sqlx.DB) JobRepository {
+	return &JobRepositoryImpl{
+		DB: db,
+	}
+}
+
+// TryLock takes the advisory lock of a job so only one replica runs it at a
+// time. The lock lives in a transaction kept open until release is called,
+// Postgres drops it by itself if the replica dies meanwhile.
+func (r *JobRepositoryImpl) TryLock(ctx context.Context, job string) (func(), bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "JobRepository.TryLock")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin job lock, err : %v\n", err)
+		return nil, false, helper.NewInternal()
+	}
+
+	locked := false
+	if err := tx.GetContext(ctx, &locked, "SELECT pg_try_advisory_xact_lock(hashtext($1))", "job:"+job); err != nil {
+		tx.Rollback()
+		log.Printf("failed to query job lock, err : %v\n", err)
+		return nil, false, helper.NewInternal()
+	}
+
+	if !locked {
+		tx.Rollback()
+		return nil, false, nil
+	}
+
+	release := func() {
+		tx.Rollback()
+	}
+
+	return release, true, nil
+}
+
+func (r *JobRepositoryImpl) StartRun(ctx context.Context, job string) (uuid.UUID, error) {
+
+	ctx, span := helper.StartSpan(ctx, "JobRepository.StartRun")
+	defer span.End()
+
+	id := uuid.Nil
+
+	query := "INSERT INTO job_runs (job) VALUES ($1) RETURNING id"
+
+	if err := r.DB.GetContext(ctx, &id, query, job); err != nil {
+		log.Printf("failed to query start job run, err : %v\n", err)
+		return id, helper.NewInternal()
+	}
+
+	return id, nil
+}
+
+// FinishRun records the outcome of a run, failed when jobErr is set
+func (r *JobRepositoryImpl) FinishRun(ctx context.Context, id uuid.UUID, affected int64, jobErr error) error {
+
+	ctx, span := helper.StartSpan(ctx, "JobRepository.FinishRun")
+	defer span.End()
+
+	status, message := entity.JobSucceeded, ""
+	if jobErr != nil {
+		status, message = entity.JobFailed, jobErr.Error()
+	}
+
+	query := `
+	UPDATE job_runs 
+	SET status = $1, affected = $2, error = NULLIF($3, ''), finished_at = now() 
+	WHERE id = $4`
+
+	if _, err := r.DB.ExecContext(ctx, query, status, affected, message, id); err != nil {
+		log.Printf("failed to query finish job run, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// GetRuns lists job runs, the latest first
+func (r *JobRepositoryImpl) GetRuns(ctx context.Context, filter web.JobRunFilter, page web.PageRequest) ([]web.JobRunResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "JobRepository.GetRuns")
+	defer span.End()
+
+	runs := []web.JobRunResponse{}
+	total := 0
+
+	from := `
+	FROM 
+		job_runs
+	WHERE 
+		($1 = '' OR job = $1) AND ($2 = '' OR status = $2)
+	`
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from, filter.Job, filter.Status); err != nil {
+		log.Printf("failed to query count job runs, err : %v\n", err)
+		return runs, total, helper.NewInternal()
+	}
+
+	query := `
+	SELECT 
+		id, job, status, affected, error, started_at, finished_at
+	` + from + `
+	ORDER BY 
+		started_at DESC
+	LIMIT $3 OFFSET $4
+	`
+
+	if err := r.DB.SelectContext(ctx, &runs, query, filter.Job, filter.Status, page.Limit(), page.Offset()); err != nil {
+		log.Printf("failed to query job runs, err : %v\n", err)
+		return runs, total, helper.NewInternal()
+	}
+
+	return runs, total, nil
+}
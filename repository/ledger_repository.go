@@ -0,0 +1,355 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type LedgerRepository interface {
+	GetWallet(ctx context.Context, sellerId uuid.UUID) (web.WalletResponse, error)
+	GetStatement(ctx context.Context, sellerId uuid.UUID, page web.PageRequest) ([]web.StatementLine, int, error)
+	RequestPayout(ctx context.Context, payout *entity.Payout) error
+	GetPayoutsBySeller(ctx context.Context, sellerId uuid.UUID, page web.PageRequest) ([]web.PayoutResponse, int, error)
+	GetPayouts(ctx context.Context, filter web.PayoutFilter, page web.PageRequest) ([]web.PayoutResponse, int, error)
+	ReviewPayout(ctx context.Context, req web.PayoutReviewRequest, status string) error
+}
+
+type LedgerRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewLedgerRepository(db *sqlx.DB) LedgerRepository {
+	return &LedgerRepositoryImpl{
+		DB: db,
+	}
+}
+
+// GetWallet sums up what a seller is owed: the balance of their ledger
+// account, the payouts waiting for an admin and the escrow of their orders
+func (r *LedgerRepositoryImpl) GetWallet(ctx context.Context, sellerId uuid.UUID) (web.WalletResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerRepository.GetWallet")
+	defer span.End()
+
+	wallet := web.WalletResponse{}
+
+	query := `
+	SELECT 
+		COALESCE((SELECT balance FROM ledger_accounts WHERE kind = 'SELLER_AVAILABLE' AND owner_id = $1), 0) as available,
+		COALESCE((SELECT SUM(amount) FROM payouts WHERE seller_id = $1 AND status = 'REQUESTED'), 0) as pending_payout,
+		COALESCE((
+			SELECT SUM(payments.amount - payments.fee) FROM payments 
+			JOIN orders ON orders.id = payments.order_id
+			WHERE orders.seller_id = $1 AND payments.status = 'HELD'
+		), 0) as held
+	`
+
+	if err := r.DB.GetContext(ctx, &wallet, query, sellerId); err != nil {
+		log.Printf("failed to query get wallet, err : %v\n", err)
+		return wallet, helper.NewInternal()
+	}
+
+	return wallet, nil
+}
+
+// GetStatement lists the postings on a seller's ledger account, the latest
+// first
+func (r *LedgerRepositoryImpl) GetStatement(ctx context.Context, sellerId uuid.UUID, page web.PageRequest) ([]web.StatementLine, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerRepository.GetStatement")
+	defer span.End()
+
+	lines := []web.StatementLine{}
+	total := 0
+
+	from := `
+	FROM 
+		postings
+	JOIN 
+		ledger_accounts ON ledger_accounts.id = postings.account_id
+	JOIN 
+		journal_entries ON journal_entries.id = postings.entry_id
+	WHERE 
+		ledger_accounts.kind = 'SELLER_AVAILABLE' AND ledger_accounts.owner_id = $1
+	`
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from, sellerId); err != nil {
+		log.Printf("failed to query count statement, err : %v\n", err)
+		return lines, total, helper.NewInternal()
+	}
+
+	query := `
+	SELECT 
+		journal_entries.id as entry_id, journal_entries.kind, journal_entries.reference_id, journal_entries.description, 
+		postings.amount, postings.balance_after, postings.created_at
+	` + from + `
+	ORDER BY 
+		postings.created_at DESC, postings.id
+	LIMIT $2 OFFSET $3
+	`
+
+	if err := r.DB.SelectContext(ctx, &lines, query, sellerId, page.Limit(), page.Offset()); err != nil {
+		log.Printf("failed to query get statement, err : %v\n", err)
+		return lines, total, helper.NewInternal()
+	}
+
+	return lines, total, nil
+}
+
+// RequestPayout moves the amount from the seller's balance to the pending
+// payouts, refused when the balance is too low
+func (r *LedgerRepositoryImpl) RequestPayout(ctx context.Context, payout *entity.Payout) error {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerRepository.RequestPayout")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin request payout, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO 
+		payouts (seller_id, amount, destination) 
+	VALUES 
+		($1, $2, $3)
+	RETURNING 
+		id, status, created_at
+	`
+
+	err = tx.QueryRowxContext(ctx, query, payout.SellerId, payout.Amount, payout.Destination).StructScan(payout)
+	if err != nil {
+		log.Printf("failed to query create payout, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	err = postEntry(ctx, tx, entity.EntryPayoutRequested, payout.Id, "payout requested to "+payout.Destination,
+		entity.Posting{Kind: entity.LedgerSellerAvailable, OwnerId: uuid.NullUUID{UUID: payout.SellerId, Valid: true}, Amount: -payout.Amount},
+		entity.Posting{Kind: entity.LedgerPayoutsPending, Amount: payout.Amount},
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit request payout, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+const payoutColumns = `
+	payouts.id, payouts.seller_id, profiles.name as seller_name, payouts.amount, payouts.destination, 
+	payouts.status, payouts.reason, payouts.created_at, payouts.reviewed_at
+`
+
+func (r *LedgerRepositoryImpl) GetPayoutsBySeller(ctx context.Context, sellerId uuid.UUID, page web.PageRequest) ([]web.PayoutResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerRepository.GetPayoutsBySeller")
+	defer span.End()
+
+	from := `
+	FROM 
+		payouts
+	JOIN 
+		profiles ON profiles.id = payouts.seller_id
+	WHERE 
+		payouts.seller_id = $1
+	`
+
+	return r.queryPayouts(ctx, from, "payouts.created_at DESC", sellerId, page)
+}
+
+// GetPayouts lists payouts for admins, the oldest first so requests are
+// reviewed in order
+func (r *LedgerRepositoryImpl) GetPayouts(ctx context.Context, filter web.PayoutFilter, page web.PageRequest) ([]web.PayoutResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerRepository.GetPayouts")
+	defer span.End()
+
+	from := `
+	FROM 
+		payouts
+	JOIN 
+		profiles ON profiles.id = payouts.seller_id
+	WHERE 
+		($1 = '' OR payouts.status = $1)
+	`
+
+	return r.queryPayouts(ctx, from, "payouts.created_at", filter.Status, page)
+}
+
+// queryPayouts pages the payouts matching from, whose only parameter is arg,
+// in order. The total is counted apart from the page.
+func (r *LedgerRepositoryImpl) queryPayouts(ctx context.Context, from string, order string, arg interface{}, page web.PageRequest) ([]web.PayoutResponse, int, error) {
+
+	payouts := []web.PayoutResponse{}
+	total := 0
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from, arg); err != nil {
+		log.Printf("failed to query count payouts, err : %v\n", err)
+		return payouts, total, helper.NewInternal()
+	}
+
+	query := "SELECT " + payoutColumns + from + " ORDER BY " + order + " LIMIT $2 OFFSET $3"
+
+	if err := r.DB.SelectContext(ctx, &payouts, query, arg, page.Limit(), page.Offset()); err != nil {
+		log.Printf("failed to query get payouts, err : %v\n", err)
+		return payouts, total, helper.NewInternal()
+	}
+
+	return payouts, total, nil
+}
+
+// ReviewPayout approves a requested payout, the money leaves the platform, or
+// rejects it and gives the amount back to the seller's balance
+func (r *LedgerRepositoryImpl) ReviewPayout(ctx context.Context, req web.PayoutReviewRequest, status string) error {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerRepository.ReviewPayout")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin review payout, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	query := `
+	UPDATE 
+		payouts 
+	SET 
+		status = $1, reason = NULLIF($2, ''), reviewed_by = $3, reviewed_at = now()
+	WHERE 
+		id = $4 AND status = 'REQUESTED'
+	RETURNING 
+		seller_id, amount
+	`
+
+	payout := entity.Payout{}
+	err = tx.GetContext(ctx, &payout, query, status, req.Reason, req.AdminId, req.Id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			exists := false
+			if err := tx.GetContext(ctx, &exists, "SELECT EXISTS (SELECT 1 FROM payouts WHERE id = $1)", req.Id); err != nil {
+				log.Printf("failed to query check payout, err : %v\n", err)
+				return helper.NewInternal()
+			}
+
+			if !exists {
+				return helper.NewNotFound("payout id", req.Id.String())
+			}
+
+			return helper.NewBadRequest("the payout was already reviewed")
+		}
+
+		log.Printf("failed to query review payout, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if status == entity.PayoutApproved {
+		err = postEntry(ctx, tx, entity.EntryPayoutApproved, req.Id, "payout approved",
+			entity.Posting{Kind: entity.LedgerPayoutsPending, Amount: -payout.Amount},
+			entity.Posting{Kind: entity.LedgerGateway, Amount: payout.Amount},
+		)
+	} else {
+		err = postEntry(ctx, tx, entity.EntryPayoutRejected, req.Id, "payout rejected: "+req.Reason,
+			entity.Posting{Kind: entity.LedgerPayoutsPending, Amount: -payout.Amount},
+			entity.Posting{Kind: entity.LedgerSellerAvailable, OwnerId: uuid.NullUUID{UUID: payout.SellerId, Valid: true}, Amount: payout.Amount},
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	query = `
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	VALUES 
+		($1, 'PAYOUT_REVIEWED', 'your payout request was ' || lower($2))
+	`
+
+	if _, err := tx.ExecContext(ctx, query, payout.SellerId, status); err != nil {
+		log.Printf("failed to query notify payout review, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit review payout, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// postEntry records a journal entry with its postings, which must add up to
+// zero. An entry of the same kind for the same reference is recorded once,
+// posting it again does nothing. A posting that would overdraw an account is
+// refused.
+func postEntry(ctx context.Context, tx *sqlx.Tx, kind string, referenceId uuid.UUID, description string, postings ...entity.Posting) error {
+
+	query := `
+	INSERT INTO 
+		journal_entries (kind, reference_id, description) 
+	VALUES 
+		($1, $2, $3)
+	ON CONFLICT 
+		(kind, reference_id) DO NOTHING
+	RETURNING 
+		id
+	`
+
+	entryId := uuid.Nil
+	if err := tx.GetContext(ctx, &entryId, query, kind, referenceId, description); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil
+		}
+
+		log.Printf("failed to query create journal entry, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	for _, posting := range postings {
+		if posting.Amount == 0 {
+			continue
+		}
+
+		query = "INSERT INTO ledger_accounts (kind, owner_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+		if _, err := tx.ExecContext(ctx, query, posting.Kind, posting.OwnerId); err != nil {
+			log.Printf("failed to query open ledger account, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		query = `
+		INSERT INTO 
+			postings (entry_id, account_id, amount, balance_after)
+		SELECT 
+			$1, id, $2, 0
+		FROM 
+			ledger_accounts
+		WHERE 
+			kind = $3 AND owner_id IS NOT DISTINCT FROM $4
+		`
+
+		if _, err := tx.ExecContext(ctx, query, entryId, posting.Amount, posting.Kind, posting.OwnerId); err != nil {
+			if err, ok := err.(*pq.Error); ok && err.Code.Name() == "check_violation" {
+				return helper.NewBadRequest("insufficient balance")
+			}
+
+			log.Printf("failed to query create posting, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	}
+
+	return nil
+}
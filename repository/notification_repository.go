@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationRepository interface {
+	GetByAccount(ctx context.Context, accountId uuid.UUID, page web.PageRequest) ([]web.NotificationResponse, int, error)
+}
+
+type NotificationRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) NotificationRepository {
+	return &NotificationRepositoryImpl{
+		DB: db,
+	}
+}
+
+// GetByAccount lists the notifications of an account, the latest first
+func (r *NotificationRepositoryImpl) GetByAccount(ctx context.Context, accountId uuid.UUID, page web.PageRequest) ([]web.NotificationResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "NotificationRepository.GetByAccount")
+	defer span.End()
+
+	notifications := []web.NotificationResponse{}
+	total := 0
+
+	query := "SELECT COUNT(*) FROM notifications WHERE account_id = $1"
+
+	if err := r.DB.GetContext(ctx, &total, query, accountId); err != nil {
+		log.Printf("failed to query count notifications, err : %v\n", err)
+		return notifications, total, helper.NewInternal()
+	}
+
+	query = `
+	SELECT 
+		id, kind, message, created_at
+	FROM 
+		notifications
+	WHERE 
+		account_id = $1
+	ORDER BY 
+		created_at DESC
+	LIMIT $2 OFFSET $3
+	`
+
+	if err := r.DB.SelectContext(ctx, &notifications, query, accountId, page.Limit(), page.Offset()); err != nil {
+		log.Printf("failed to query get notifications, err : %v\n", err)
+		return notifications, total, helper.NewInternal()
+	}
+
+	return notifications, total, nil
+}
@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type OrderRepository interface {
+	GetById(ctx context.Context, id uuid.UUID) (*entity.Order, error)
+	GetOne(ctx context.Context, id uuid.UUID) (web.OrderResponse, error)
+	GetByTransaction(ctx context.Context, transactionId uuid.UUID) (*entity.Order, bool, error)
+	GetByAccount(ctx context.Context, accountId uuid.UUID, filter web.OrderFilter, page web.PageRequest) ([]web.OrderResponse, int, error)
+	Claim(ctx context.Context, id uuid.UUID, from string, status string) error
+	Unclaim(ctx context.Context, id uuid.UUID) error
+	Move(ctx context.Context, order *entity.Order, from string, notify uuid.UUID, payment *entity.Payment, paymentStatus string) error
+}
+
+type OrderRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewOrderRepository(db *sqlx.DB) OrderRepository {
+	return &OrderRepositoryImpl{
+		DB: db,
+	}
+}
+
+const orderColumns = `
+	orders.id, orders.transaction_id, orders.product_id, products.name as product_name, 
+	orders.buyer_id, buyer.name as buyer_name, orders.seller_id, seller.name as seller_name, 
+	orders.price, orders.status, orders.carrier, orders.tracking_number, orders.cancel_reason, 
+	orders.created_at, orders.updated_at, orders.paid_at, orders.shipped_at, orders.delivered_at, 
+	orders.completed_at, orders.cancelled_at
+`
+
+const orderJoins = `
+	JOIN 
+		products ON products.id = orders.product_id
+	JOIN 
+		profiles buyer ON buyer.id = orders.buyer_id
+	JOIN 
+		profiles seller ON seller.id = orders.seller_id
+`
+
+func (r *OrderRepositoryImpl) GetById(ctx context.Context, id uuid.UUID) (*entity.Order, error) {
+
+	ctx, span := helper.StartSpan(ctx, "OrderRepository.GetById")
+	defer span.End()
+
+	order := &entity.Order{}
+
+	query := "SELECT * FROM orders WHERE id = $1"
+
+	if err := r.DB.GetContext(ctx, order, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return order, helper.NewNotFound("order id", id.String())
+		}
+
+		log.Printf("failed to query get order by id, err : %v\n", err)
+		return order, helper.NewInternal()
+	}
+
+	return order, nil
+}
+
+// GetByTransaction returns the order of an accepted offer that was not
+// cancelled, if any
+func (r *OrderRepositoryImpl) GetByTransaction(ctx context.Context, transactionId uuid.UUID) (*entity.Order, bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "OrderRepository.GetByTransaction")
+	defer span.End()
+
+	order := &entity.Order{}
+
+	query := "SELECT * FROM orders WHERE transaction_id = $1 AND status <> 'CANCELLED'"
+
+	if err := r.DB.GetContext(ctx, order, query, transactionId); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return order, false, nil
+		}
+
+		log.Printf("failed to query get order by transaction, err : %v\n", err)
+		return order, false, helper.NewInternal()
+	}
+
+	return order, true, nil
+}
+
+func (r *OrderRepositoryImpl) GetOne(ctx context.Context, id uuid.UUID) (web.OrderResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "OrderRepository.GetOne")
+	defer span.End()
+
+	order := web.OrderResponse{}
+
+	query := "SELECT " + orderColumns + " FROM orders " + orderJoins + " WHERE orders.id = $1"
+
+	if err := r.DB.GetContext(ctx, &order, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return order, helper.NewNotFound("order id", id.String())
+		}
+
+		log.Printf("failed to query get order, err : %v\n", err)
+		return order, helper.NewInternal()
+	}
+
+	return order, nil
+}
+
+// GetByAccount lists the orders an account buys or sells in, the latest first
+func (r *OrderRepositoryImpl) GetByAccount(ctx context.Context, accountId uuid.UUID, filter web.OrderFilter, page web.PageRequest) ([]web.OrderResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "OrderRepository.GetByAccount")
+	defer span.End()
+
+	orders := []web.OrderResponse{}
+	total := 0
+
+	from := "FROM orders " + orderJoins + `
+	WHERE 
+		(($2 IN ('', 'BUYER') AND orders.buyer_id = $1) OR ($2 IN ('', 'SELLER') AND orders.seller_id = $1))
+		AND ($3 = '' OR orders.status = $3)
+	`
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from, accountId, filter.Role, filter.Status); err != nil {
+		log.Printf("failed to query count orders, err : %v\n", err)
+		return orders, total, helper.NewInternal()
+	}
+
+	query := "SELECT " + orderColumns + from + `
+	ORDER BY 
+		orders.created_at DESC
+	LIMIT $4 OFFSET $5
+	`
+
+	if err := r.DB.SelectContext(ctx, &orders, query, accountId, filter.Role, filter.Status, page.Limit(), page.Offset()); err != nil {
+		log.Printf("failed to query get orders, err : %v\n", err)
+		return orders, total, helper.NewInternal()
+	}
+
+	return orders, total, nil
+}
+
+// Claim marks an order in from as being moved to status, so only one request
+// gets the payment provider to move its money
+func (r *OrderRepositoryImpl) Claim(ctx context.Context, id uuid.UUID, from string, status string) error {
+
+	ctx, span := helper.StartSpan(ctx, "OrderRepository.Claim")
+	defer span.End()
+
+	query := "UPDATE orders SET settling = $1, updated_at = now() WHERE id = $2 AND status = $3 AND settling IS NULL"
+
+	result, err := r.DB.ExecContext(ctx, query, status, id, from)
+	if err != nil {
+		log.Printf("failed to query claim order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when claim order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewBadRequest("the order is already being updated")
+	}
+
+	return nil
+}
+
+// Unclaim frees an order whose money could not be moved by the payment
+// provider
+func (r *OrderRepositoryImpl) Unclaim(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "OrderRepository.Unclaim")
+	defer span.End()
+
+	query := "UPDATE orders SET settling = NULL, updated_at = now() WHERE id = $1"
+
+	if _, err := r.DB.ExecContext(ctx, query, id); err != nil {
+		log.Printf("failed to query unclaim order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// Move sets the status of an order still in from and lets notify know. With
+// paymentStatus the payment of the order, unless it changed meanwhile, reaches
+// that status in the same transaction. Completing an order sells its product,
+// cancelling it makes the product available again and the offer is no longer
+// accepted.
+func (r *OrderRepositoryImpl) Move(ctx context.Context, order *entity.Order, from string, notify uuid.UUID, payment *entity.Payment, paymentStatus string) error {
+
+	ctx, span := helper.StartSpan(ctx, "OrderRepository.Move")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin move order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	if paymentStatus != "" {
+		locked := &entity.Payment{}
+		if err := tx.GetContext(ctx, locked, "SELECT * FROM payments WHERE id = $1 FOR UPDATE", payment.Id); err != nil {
+			log.Printf("failed to query get order payment, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		if locked.Status == payment.Status {
+			if err := movePayment(ctx, tx, locked, paymentStatus); err != nil {
+				return err
+			}
+		}
+	}
+
+	err = moveOrder(ctx, tx, order, from)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	SELECT 
+		$1, 'ORDER_UPDATED', 'the order of ' || name || ' is now ' || lower($2)
+	FROM 
+		products
+	WHERE 
+		id = $3
+	`
+
+	if _, err := tx.ExecContext(ctx, query, notify, order.Status, order.ProductId); err != nil {
+		log.Printf("failed to query notify order update, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit move order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// moveOrder sets the status of an order still in from, completing it sells
+// the product and cancelling it releases the product and the offer. An order
+// claimed for another status stays as it is.
+func moveOrder(ctx context.Context, tx *sqlx.Tx, order *entity.Order, from string) error {
+
+	query := `
+	UPDATE 
+		orders 
+	SET 
+		status = $1, carrier = COALESCE($2, carrier), tracking_number = COALESCE($3, tracking_number), 
+		cancel_reason = COALESCE($4, cancel_reason), updated_at = now(),
+		paid_at = CASE WHEN $1 = 'PAID' THEN now() ELSE paid_at END,
+		shipped_at = CASE WHEN $1 = 'SHIPPED' THEN now() ELSE shipped_at END,
+		delivered_at = CASE WHEN $1 = 'DELIVERED' THEN now() ELSE delivered_at END,
+		completed_at = CASE WHEN $1 = 'COMPLETED' THEN now() ELSE completed_at END,
+		cancelled_at = CASE WHEN $1 = 'CANCELLED' THEN now() ELSE cancelled_at END,
+		settling = NULL
+	WHERE 
+		id = $5 AND status = $6 AND (settling IS NULL OR settling = $1)
+	`
+
+	result, err := tx.ExecContext(ctx, query, order.Status, order.Carrier, order.TrackingNumber, order.CancelReason, order.Id, from)
+	if err != nil {
+		log.Printf("failed to query move order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when move order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	// someone else moved the order meanwhile
+	if row == 0 {
+		return helper.NewBadRequest("the order cannot move to this status from its current status")
+	}
+
+	switch order.Status {
+	case entity.OrderCompleted:
+		query = `
+		UPDATE 
+			products 
+		SET 
+			sold = TRUE, reserved_for = NULL, reserved_until = NULL, updated_at = now()
+		WHERE 
+			id = $1
+		`
+
+		if _, err := tx.ExecContext(ctx, query, order.ProductId); err != nil {
+			log.Printf("failed to query sell ordered product, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	case entity.OrderCancelled:
+		query = `
+		UPDATE 
+			products 
+		SET 
+			reserved_for = NULL, reserved_until = NULL, updated_at = now()
+		WHERE 
+			id = $1 AND reserved_for = $2
+		`
+
+		if _, err := tx.ExecContext(ctx, query, order.ProductId, order.TransactionId); err != nil {
+			log.Printf("failed to query release ordered product, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		query = "UPDATE transactions SET accepted = FALSE, updated_at = now() WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, query, order.TransactionId); err != nil {
+			log.Printf("failed to query reopen ordered offer, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	}
+
+	return nil
+}
+
+// createOrder opens the order of an offer that was just accepted
+func createOrder(ctx context.Context, tx *sqlx.Tx, transactionId uuid.UUID) error {
+
+	query := `
+	INSERT INTO 
+		orders 
+		(transaction_id, product_id, buyer_id, seller_id, price)
+	SELECT 
+		id, product_id, buyer_id, seller_id, price_offer
+	FROM 
+		transactions
+	WHERE 
+		id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, transactionId); err != nil {
+		log.Printf("failed to query create order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// cancelOpenOrder cancels the order of an offer whose acceptance ends before
+// it was paid, with the charge the buyer may be paying. Paid orders have to be
+// cancelled on their own.
+func cancelOpenOrder(ctx context.Context, tx *sqlx.Tx, transactionId uuid.UUID, reason string) error {
+
+	paid := false
+	query := "SELECT EXISTS (SELECT 1 FROM orders WHERE transaction_id = $1 AND status IN ('PAID', 'SHIPPED', 'DELIVERED', 'COMPLETED'))"
+	if err := tx.GetContext(ctx, &paid, query, transactionId); err != nil {
+		log.Printf("failed to query check paid order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if paid {
+		return helper.NewBadRequest("the order of this offer is already paid")
+	}
+
+	query = `
+	WITH cancelled AS (
+		UPDATE 
+			orders 
+		SET 
+			status = 'CANCELLED', cancel_reason = $1, cancelled_at = now(), updated_at = now()
+		WHERE 
+			transaction_id = $2 AND status = 'AWAITING_PAYMENT'
+		RETURNING 
+			id
+	)
+	UPDATE 
+		payments 
+	SET 
+		status = 'CANCELLED', updated_at = now()
+	FROM 
+		cancelled
+	WHERE 
+		payments.order_id = cancelled.id AND payments.status = 'PENDING'
+	`
+
+	if _, err := tx.ExecContext(ctx, query, reason, transactionId); err != nil {
+		log.Printf("failed to query cancel open order, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
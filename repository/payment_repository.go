@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type PaymentRepository interface {
+	Create(ctx context.Context, payment *entity.Payment) error
+	GetOpenByOrder(ctx context.Context, orderId uuid.UUID) (*entity.Payment, bool, error)
+	GetByOrder(ctx context.Context, orderId uuid.UUID) ([]web.PaymentResponse, error)
+	SetStatus(ctx context.Context, id uuid.UUID, status string, from string) error
+	HandleEvent(ctx context.Context, provider string, event web.PaymentEvent) (*entity.Payment, bool, error)
+}
+
+type PaymentRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewPaymentRepository(db *sqlx.DB) PaymentRepository {
+	return &PaymentRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *PaymentRepositoryImpl) Create(ctx context.Context, payment *entity.Payment) error {
+
+	ctx, span := helper.StartSpan(ctx, "PaymentRepository.Create")
+	defer span.End()
+
+	query := `
+	INSERT INTO 
+		payments 
+		(transaction_id, order_id, provider, charge_id, amount, fee, payment_url) 
+	VALUES 
+		($1, $2, $3, $4, $5, $6, $7)
+	RETURNING 
+		id, status, created_at, updated_at
+	`
+
+	err := r.DB.QueryRowxContext(ctx, query, payment.TransactionId, payment.OrderId, payment.Provider, payment.ChargeId,
+		payment.Amount, payment.Fee, payment.PaymentUrl).StructScan(payment)
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return helper.NewConflict("payment", payment.OrderId.String())
+		}
+
+		log.Printf("failed to query create payment, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// GetOpenByOrder returns the pending or held payment of an order, if any
+func (r *PaymentRepositoryImpl) GetOpenByOrder(ctx context.Context, orderId uuid.UUID) (*entity.Payment, bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "PaymentRepository.GetOpenByOrder")
+	defer span.End()
+
+	payment := &entity.Payment{}
+
+	query := "SELECT * FROM payments WHERE order_id = $1 AND status IN ('PENDING', 'HELD')"
+
+	if err := r.DB.GetContext(ctx, payment, query, orderId); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return payment, false, nil
+		}
+
+		log.Printf("failed to query get open payment, err : %v\n", err)
+		return payment, false, helper.NewInternal()
+	}
+
+	return payment, true, nil
+}
+
+// GetByOrder lists every payment attempt of an order, oldest first
+func (r *PaymentRepositoryImpl) GetByOrder(ctx context.Context, orderId uuid.UUID) ([]web.PaymentResponse, error) {
+
+	ctx, span := helper.StartSpan(ctx, "PaymentRepository.GetByOrder")
+	defer span.End()
+
+	payments := []web.PaymentResponse{}
+
+	query := `
+	SELECT 
+		id, transaction_id, order_id, provider, charge_id, amount, refunded_amount, status, 
+		CASE WHEN status = 'PENDING' THEN payment_url ELSE '' END as payment_url, 
+		created_at, held_at, released_at, refunded_at
+	FROM 
+		payments
+	WHERE 
+		order_id = $1
+	ORDER BY 
+		created_at
+	`
+
+	if err := r.DB.SelectContext(ctx, &payments, query, orderId); err != nil {
+		log.Printf("failed to query get payments, err : %v\n", err)
+		return payments, helper.NewInternal()
+	}
+
+	return payments, nil
+}
+
+// SetStatus moves a payment still in from to status
+func (r *PaymentRepositoryImpl) SetStatus(ctx context.Context, id uuid.UUID, status string, from string) error {
+
+	ctx, span := helper.StartSpan(ctx, "PaymentRepository.SetStatus")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin set payment status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	payment := &entity.Payment{}
+	if err := tx.GetContext(ctx, payment, "SELECT * FROM payments WHERE id = $1 FOR UPDATE", id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewNotFound("payment", id.String())
+		}
+
+		log.Printf("failed to query get payment, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if payment.Status != from {
+		return nil
+	}
+
+	err = movePayment(ctx, tx, payment, status)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit set payment status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// movePayment sets the status of a locked payment and records the money it
+// moves in the ledger: held in escrow, released to the seller less the fee and
+// any partial refund, or refunded from escrow
+func movePayment(ctx context.Context, tx *sqlx.Tx, payment *entity.Payment, status string) error {
+
+	query := `
+	UPDATE 
+		payments 
+	SET 
+		status = $1, fee = $3, refunded_amount = $4, updated_at = now(),
+		held_at = CASE WHEN $1 = 'HELD' THEN now() ELSE held_at END,
+		released_at = CASE WHEN $1 = 'RELEASED' THEN now() ELSE released_at END,
+		refunded_at = CASE WHEN $1 = 'REFUNDED' THEN now() ELSE refunded_at END
+	WHERE 
+		id = $2
+	`
+
+	if _, err := tx.ExecContext(ctx, query, status, payment.Id, payment.Fee, payment.RefundedAmount); err != nil {
+		log.Printf("failed to query set payment status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	from := payment.Status
+	payment.Status = status
+
+	switch {
+	case status == entity.PaymentHeld:
+		return postEntry(ctx, tx, entity.EntryPaymentHeld, payment.Id, "payment of order "+payment.OrderId.String()+" held in escrow",
+			entity.Posting{Kind: entity.LedgerGateway, Amount: -payment.Amount},
+			entity.Posting{Kind: entity.LedgerEscrow, Amount: payment.Amount},
+		)
+	case from == entity.PaymentHeld && status == entity.PaymentReleased:
+		sellerId := uuid.Nil
+		if err := tx.GetContext(ctx, &sellerId, "SELECT seller_id FROM orders WHERE id = $1", payment.OrderId); err != nil {
+			log.Printf("failed to query get seller of payment, err : %v\n", err)
+			return helper.NewInternal()
+		}
+
+		return postEntry(ctx, tx, entity.EntrySaleReleased, payment.Id, "sale of order "+payment.OrderId.String(),
+			entity.Posting{Kind: entity.LedgerEscrow, Amount: -payment.Amount},
+			entity.Posting{Kind: entity.LedgerSellerAvailable, OwnerId: uuid.NullUUID{UUID: sellerId, Valid: true}, Amount: payment.Amount - payment.RefundedAmount - payment.Fee},
+			entity.Posting{Kind: entity.LedgerFees, Amount: payment.Fee},
+			entity.Posting{Kind: entity.LedgerGateway, Amount: payment.RefundedAmount},
+		)
+	case from == entity.PaymentHeld && status == entity.PaymentRefunded:
+		return postEntry(ctx, tx, entity.EntryPaymentRefunded, payment.Id, "refund of order "+payment.OrderId.String(),
+			entity.Posting{Kind: entity.LedgerEscrow, Amount: -payment.Amount},
+			entity.Posting{Kind: entity.LedgerGateway, Amount: payment.Amount},
+		)
+	}
+
+	return nil
+}
+
+// HandleEvent applies a webhook event to the payment of its charge once. It
+// reports false for an event handled before. An authorized charge holds the
+// money and marks the order paid, the other events confirm what the charge
+// became. The payment is returned as it is after the event. An event of a
+// charge with no payment yet is not recorded, so the provider retries it.
+func (r *PaymentRepositoryImpl) HandleEvent(ctx context.Context, provider string, event web.PaymentEvent) (*entity.Payment, bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "PaymentRepository.HandleEvent")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin handle payment event, err : %v\n", err)
+		return nil, false, helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	// Pay creates the charge before its payment, an early event waits for it
+	payment := &entity.Payment{}
+	query := "SELECT * FROM payments WHERE provider = $1 AND charge_id = $2 FOR UPDATE"
+
+	if err := tx.GetContext(ctx, payment, query, provider, event.ChargeId); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, false, helper.NewNotFound("charge id", event.ChargeId)
+		}
+
+		log.Printf("failed to query get payment of event, err : %v\n", err)
+		return nil, false, helper.NewInternal()
+	}
+
+	query = `
+	INSERT INTO 
+		payment_events (provider, event_id, type, charge_id) 
+	VALUES 
+		($1, $2, $3, $4)
+	ON CONFLICT DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx, query, provider, event.Id, event.Type, event.ChargeId)
+	if err != nil {
+		log.Printf("failed to query record payment event, err : %v\n", err)
+		return nil, false, helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when record payment event, err : %v\n", err)
+		return nil, false, helper.NewInternal()
+	}
+
+	handled := row == 1
+
+	if handled {
+		status, from := "", []string{}
+		switch event.Type {
+		case entity.EventChargeAuthorized:
+			status, from = entity.PaymentHeld, []string{entity.PaymentPending}
+		case entity.EventChargeFailed:
+			status, from = entity.PaymentFailed, []string{entity.PaymentPending}
+		case entity.EventChargeCaptured:
+			status, from = entity.PaymentReleased, []string{entity.PaymentHeld}
+		case entity.EventChargeRefunded:
+			status, from = entity.PaymentRefunded, []string{entity.PaymentHeld, entity.PaymentCancelled}
+		}
+
+		for _, f := range from {
+			if payment.Status != f {
+				continue
+			}
+
+			if err := movePayment(ctx, tx, payment, status); err != nil {
+				return nil, false, err
+			}
+			break
+		}
+
+		if payment.Status == entity.PaymentHeld && event.Type == entity.EventChargeAuthorized {
+			query = `
+			WITH paid AS (
+				UPDATE 
+					orders 
+				SET 
+					status = 'PAID', paid_at = now(), updated_at = now()
+				WHERE 
+					id = $1 AND status = 'AWAITING_PAYMENT'
+				RETURNING 
+					seller_id, product_id
+			)
+			INSERT INTO 
+				notifications (account_id, kind, message)
+			SELECT 
+				paid.seller_id, 'ORDER_UPDATED', 'the order of ' || products.name || ' was paid, the money is held until the buyer confirms the delivery'
+			FROM 
+				paid
+			JOIN 
+				products ON products.id = paid.product_id
+			`
+
+			if _, err := tx.ExecContext(ctx, query, payment.OrderId); err != nil {
+				log.Printf("failed to query mark order paid, err : %v\n", err)
+				return nil, false, helper.NewInternal()
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit handle payment event, err : %v\n", err)
+		return nil, false, helper.NewInternal()
+	}
+
+	return payment, handled, nil
+}
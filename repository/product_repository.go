@@ -14,14 +14,15 @@ import (
 
 type ProductRepository interface {
 	Create(ctx context.Context, product *entity.Product) error
-	GetAll(ctx context.Context) ([]web.ProductResponse, error)
-	GetByCategory(ctx context.Context, category string) ([]web.ProductResponse, error)
+	GetAll(ctx context.Context, filter web.ProductFilter, page web.PageRequest) ([]web.ProductResponse, int, error)
+	GetByCategory(ctx context.Context, categoryId uuid.UUID, page web.PageRequest) ([]web.ProductResponse, int, error)
 	GetProduct(ctx context.Context, id uuid.UUID) ([]web.OfferByProduct, error)
 	GetOne(ctx context.Context, id uuid.UUID) ([]web.ProductDetailResponse, error)
 	OwnerGetOne(ctx context.Context, id uuid.UUID) ([]web.ProductDetailResponse, error)
 	GetByAccount(ctx context.Context, account_id uuid.UUID, status bool, published bool) ([]web.ProductResponse, error)
 	Update(ctx context.Context, product *entity.Product) error
 	GetOwnerId(ctx context.Context, productId uuid.UUID) (uuid.UUID, error)
+	GetState(ctx context.Context, productId uuid.UUID) (*entity.Product, error)
 	IsOwner(ctx context.Context, accountId uuid.UUID, productId uuid.UUID) (bool, error)
 	CheckOwner(ctx context.Context, accountId uuid.UUID, productId uuid.UUID) error
 	CheckThumbnail(ctx context.Context, productId uuid.UUID) (bool, error)
@@ -29,9 +30,19 @@ type ProductRepository interface {
 	SetThumbnail(ctx context.Context, product *entity.Product) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	Publish(ctx context.Context, id uuid.UUID, status bool) error
+	GetApproval(ctx context.Context, id uuid.UUID) (string, bool, error)
+	SubmitForReview(ctx context.Context, id uuid.UUID) error
+	Review(ctx context.Context, req web.ProductReviewRequest, status string) error
+	GetPendingReview(ctx context.Context, page web.PageRequest) ([]web.PendingProductResponse, int, error)
 	CheckPublished(ctx context.Context, productId uuid.UUID) (bool, error)
+	CheckHidden(ctx context.Context, productId uuid.UUID) (bool, error)
+	Hide(ctx context.Context, id uuid.UUID) error
 	CheckSold(ctx context.Context, productId uuid.UUID) (bool, error)
 	SetSold(ctx context.Context, id uuid.UUID, status bool) error
+	ReleaseReservation(ctx context.Context, id uuid.UUID) error
+	EndReservations(ctx context.Context, now time.Time) (int64, error)
+	ExportByAccount(ctx context.Context, accountId uuid.UUID) ([]web.ExportProduct, error)
+	UnpublishStale(ctx context.Context, before time.Time) (int64, error)
 }
 
 type ProductRepositoryImpl struct {
@@ -46,14 +57,18 @@ func NewProductRepository(db *sqlx.DB) ProductRepository {
 
 func (r *ProductRepositoryImpl) Create(ctx context.Context, product *entity.Product) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.Create")
+	defer span.End()
+
 	query := `
 	INSERT INTO 
 		products 
-		(account_id, name, price, category, description) 
+		(account_id, name, price, category_id, description, approval_status) 
 	VALUES 
-		($1, $2, $3, $4, $5)
+		($1, $2, $3, $4, $5, $6)
 	`
-	_, err := r.DB.ExecContext(ctx, query, product.AccountId, product.Name, product.Price, product.Category, product.Description)
+	_, err := r.DB.ExecContext(ctx, query, product.AccountId, product.Name, product.Price, product.CategoryId, product.Description,
+		product.ApprovalStatus)
 
 	if err != nil {
 		log.Printf("failed to query create product, err : %v\n", err)
@@ -65,6 +80,9 @@ func (r *ProductRepositoryImpl) Create(ctx context.Context, product *entity.Prod
 
 func (r *ProductRepositoryImpl) CheckOwner(ctx context.Context, accountId uuid.UUID, productId uuid.UUID) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.CheckOwner")
+	defer span.End()
+
 	product := &entity.Product{}
 
 	query := "SELECT id FROM products WHERE account_id=$1 AND id=$2 LIMIT 1"
@@ -83,6 +101,9 @@ func (r *ProductRepositoryImpl) CheckOwner(ctx context.Context, accountId uuid.U
 
 func (r *ProductRepositoryImpl) IsOwner(ctx context.Context, accountId uuid.UUID, productId uuid.UUID) (bool, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.IsOwner")
+	defer span.End()
+
 	product := &entity.Product{}
 
 	query := "SELECT id FROM products WHERE account_id=$1 AND id=$2 LIMIT 1"
@@ -101,6 +122,9 @@ func (r *ProductRepositoryImpl) IsOwner(ctx context.Context, accountId uuid.UUID
 
 func (r *ProductRepositoryImpl) GetOwnerId(ctx context.Context, productId uuid.UUID) (uuid.UUID, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetOwnerId")
+	defer span.End()
+
 	product := &entity.Product{}
 
 	query := `
@@ -125,8 +149,42 @@ func (r *ProductRepositoryImpl) GetOwnerId(ctx context.Context, productId uuid.U
 	return product.AccountId, nil
 }
 
+// GetState returns the owner, prices and the flags deciding whether a product
+// can still receive offers, whatever its state
+func (r *ProductRepositoryImpl) GetState(ctx context.Context, productId uuid.UUID) (*entity.Product, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetState")
+	defer span.End()
+
+	product := &entity.Product{}
+
+	query := `
+	SELECT 
+		id, account_id, price, sold, published, deleted, hidden, min_price, auto_accept_price, reserved_for, reserved_until,
+		EXISTS (SELECT 1 FROM auctions WHERE product_id = products.id AND status = 'OPEN') as auctioned
+	FROM 
+		products 
+	WHERE 
+		id=$1
+	`
+
+	if err := r.DB.GetContext(ctx, product, query, productId); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return product, helper.NewNotFound("product id", productId.String())
+		}
+
+		log.Printf("failed to query get product state, err : %v\n", err)
+		return product, helper.NewInternal()
+	}
+
+	return product, nil
+}
+
 func (r *ProductRepositoryImpl) CheckThumbnail(ctx context.Context, productId uuid.UUID) (bool, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.CheckThumbnail")
+	defer span.End()
+
 	product := &entity.Product{}
 
 	query := `
@@ -150,6 +208,9 @@ func (r *ProductRepositoryImpl) CheckThumbnail(ctx context.Context, productId uu
 
 func (r *ProductRepositoryImpl) IsThumbnail(ctx context.Context, productId uuid.UUID, path string) (bool, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.IsThumbnail")
+	defer span.End()
+
 	product := &entity.Product{}
 
 	query := `
@@ -172,6 +233,9 @@ func (r *ProductRepositoryImpl) IsThumbnail(ctx context.Context, productId uuid.
 }
 
 func (r *ProductRepositoryImpl) SetThumbnail(ctx context.Context, product *entity.Product) error {
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.SetThumbnail")
+	defer span.End()
+
 	query := `
 	UPDATE 
 		products 
@@ -202,83 +266,161 @@ func (r *ProductRepositoryImpl) SetThumbnail(ctx context.Context, product *entit
 	return nil
 }
 
-func (r *ProductRepositoryImpl) GetAll(ctx context.Context) ([]web.ProductResponse, error) {
+func (r *ProductRepositoryImpl) GetAll(ctx context.Context, filter web.ProductFilter, page web.PageRequest) ([]web.ProductResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetAll")
+	defer span.End()
 
 	products := []web.ProductResponse{}
+	total := 0
 
-	query := `
-		SELECT 
-			id, name, price, category, thumbnail 
+	// counted apart from the page, so a page past the last one still has the total
+	from := `
 		FROM 
 			products
+		JOIN 
+			categories ON categories.id = products.category_id
+		JOIN 
+			profiles ON profiles.id = products.account_id
+		JOIN 
+			accounts ON accounts.id = products.account_id
+		LEFT JOIN 
+			cities ON cities.name = profiles.city
+		LEFT JOIN LATERAL (
+			-- haversine distance in km to the seller's rounded location, so
+			-- the result never reveals more than the rounded coordinates
+			SELECT 6371 * 2 * asin(sqrt(
+				power(sin(radians(round(profiles.latitude::numeric, 2)::float8 - $3) / 2), 2) +
+				cos(radians($3)) * cos(radians(round(profiles.latitude::numeric, 2)::float8)) *
+				power(sin(radians(round(profiles.longitude::numeric, 2)::float8 - $4::float8) / 2), 2)
+			)) as distance
+			WHERE $3::float8 IS NOT NULL AND profiles.latitude IS NOT NULL
+		) nearby ON TRUE
 		WHERE 
-			deleted=FALSE AND sold=FALSE AND published=TRUE
-		LIMIT 50
+			products.deleted=FALSE AND products.sold=FALSE AND products.published=TRUE
+			AND products.approval_status='APPROVED' AND products.reserved_for IS NULL AND accounts.status <> 'BANNED'
+			AND ($1::uuid IS NULL OR cities.province_id = $1)
+			AND ($2::uuid IS NULL OR cities.id = $2)
+			AND ($3::float8 IS NULL OR (
+				profiles.latitude BETWEEN $3 - $5::float8 / 111.0 AND $3 + $5::float8 / 111.0
+				AND nearby.distance <= $5::float8
+			))
+	`
+	args := []interface{}{filter.ProvinceId, filter.CityId, filter.Latitude, filter.Longitude, filter.Radius}
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from, args...); err != nil {
+		log.Printf("failed to query count all product, err : %v\n", err)
+		return products, total, helper.NewInternal()
+	}
+
+	query := `
+		SELECT 
+			products.id, products.name, products.price, categories.name, categories.slug, 
+			products.thumbnail, round(nearby.distance::numeric, 1)::float8 as distance
+	` + from + `
+		ORDER BY nearby.distance ASC NULLS LAST, products.created_at DESC
+		LIMIT $6 OFFSET $7
 	`
-	rows, err := r.DB.QueryContext(ctx, query)
+	rows, err := r.DB.QueryContext(ctx, query, append(args, page.Limit(), page.Offset())...)
 	if err != nil {
 		log.Printf("failed to query get all product, err : %v\n", err)
-		return products, helper.NewInternal()
+		return products, total, helper.NewInternal()
 	}
 
 	for rows.Next() {
 		product := web.ProductResponse{}
-		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.Thumbnail)
+		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.CategorySlug, &product.Thumbnail,
+			&product.Distance)
 		if err != nil {
 			log.Printf("failed to scanning product, err : %v\n", err)
-			return products, helper.NewInternal()
+			return products, total, helper.NewInternal()
 		}
 
 		products = append(products, product)
 	}
 
-	return products, nil
+	return products, total, nil
 }
 
-func (r *ProductRepositoryImpl) GetByCategory(ctx context.Context, category string) ([]web.ProductResponse, error) {
+func (r *ProductRepositoryImpl) GetByCategory(ctx context.Context, categoryId uuid.UUID, page web.PageRequest) ([]web.ProductResponse, int, error) {
 
-	products := []web.ProductResponse{}
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetByCategory")
+	defer span.End()
 
-	query := `
-		SELECT 
-			id, name, price, category, thumbnail 
+	products := []web.ProductResponse{}
+	total := 0
+
+	// the category and every category nested below it
+	tree := `
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = $1
+			UNION
+			SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id
+		)
+	`
+	from := `
 		FROM 
 			products
+		JOIN 
+			categories ON categories.id = products.category_id
+		JOIN 
+			accounts ON accounts.id = products.account_id
 		WHERE 
-			category = $1 AND deleted=FALSE AND sold=FALSE AND published=TRUE
-		LIMIT 50
+			products.category_id IN (SELECT id FROM tree) 
+			AND products.deleted=FALSE AND products.sold=FALSE AND products.published=TRUE
+			AND products.approval_status='APPROVED' AND products.reserved_for IS NULL AND accounts.status <> 'BANNED'
 	`
-	rows, err := r.DB.QueryContext(ctx, query, category)
+
+	if err := r.DB.GetContext(ctx, &total, tree+"SELECT COUNT(*) "+from, categoryId); err != nil {
+		log.Printf("failed to query count product by category, err : %v\n", err)
+		return products, total, helper.NewInternal()
+	}
+
+	query := tree + `
+		SELECT 
+			products.id, products.name, products.price, categories.name, categories.slug, products.thumbnail
+	` + from + `
+		ORDER BY products.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.DB.QueryContext(ctx, query, categoryId, page.Limit(), page.Offset())
 	if err != nil {
 		log.Printf("failed to query get product by category, err : %v\n", err)
-		return products, helper.NewInternal()
+		return products, total, helper.NewInternal()
 	}
 
 	for rows.Next() {
 		product := web.ProductResponse{}
-		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.Thumbnail)
+		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.CategorySlug, &product.Thumbnail)
 		if err != nil {
 			log.Printf("failed to scanning product, err : %v\n", err)
-			return products, helper.NewInternal()
+			return products, total, helper.NewInternal()
 		}
 
 		products = append(products, product)
 	}
 
-	return products, nil
+	return products, total, nil
 }
 
 func (r *ProductRepositoryImpl) OwnerGetOne(ctx context.Context, id uuid.UUID) ([]web.ProductDetailResponse, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.OwnerGetOne")
+	defer span.End()
+
 	products := []web.ProductDetailResponse{}
 
 	query := `
 		SELECT 
-			products.id as id, products.name as name, products.price as price, products.category as category, 
+			products.id as id, products.name as name, products.price as price, categories.name as category, categories.slug as category_slug, 
 			products.description as description, products.updated_at as updated_at, products.sold as sold, products.published as published,
-			profiles.id as owner_id, profiles.name as owner, profiles.city as city, COALESCE(profiles.image_url,'') as image_url
+			profiles.id as owner_id, profiles.name as owner, profiles.city as city, COALESCE(profiles.image_url,'') as image_url,
+			products.reserved_for IS NOT NULL as reserved, products.reserved_until, products.min_price, products.auto_accept_price,
+			(SELECT auctions.id FROM auctions WHERE auctions.product_id = products.id AND auctions.status = 'OPEN') as auction_id
 		FROM 
 			products
+		JOIN 
+			categories ON categories.id = products.category_id
 		LEFT JOIN 
 			profiles 
 		ON 
@@ -295,8 +437,9 @@ func (r *ProductRepositoryImpl) OwnerGetOne(ctx context.Context, id uuid.UUID) (
 
 	for rows.Next() {
 		product := web.ProductDetailResponse{}
-		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.Description,
-			&product.UpdatedAt, &product.Sold, &product.Published, &product.OwnerId, &product.Owner, &product.City, &product.ImageUrl)
+		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.CategorySlug, &product.Description,
+			&product.UpdatedAt, &product.Sold, &product.Published, &product.OwnerId, &product.Owner, &product.City, &product.ImageUrl,
+			&product.Reserved, &product.ReservedUntil, &product.MinPrice, &product.AutoAcceptPrice, &product.AuctionId)
 		if err != nil {
 			log.Printf("failed to scanning product, err : %v\n", err)
 			return products, helper.NewInternal()
@@ -310,21 +453,29 @@ func (r *ProductRepositoryImpl) OwnerGetOne(ctx context.Context, id uuid.UUID) (
 
 func (r *ProductRepositoryImpl) GetOne(ctx context.Context, id uuid.UUID) ([]web.ProductDetailResponse, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetOne")
+	defer span.End()
+
 	products := []web.ProductDetailResponse{}
 
 	query := `
 		SELECT 
-			products.id as id, products.name as name, products.price as price, products.category as category, 
+			products.id as id, products.name as name, products.price as price, categories.name as category, categories.slug as category_slug, 
 			products.description as description, products.updated_at as updated_at, products.sold as sold, products.published as published,
-			profiles.id as owner_id, profiles.name as owner, profiles.city as city, COALESCE(profiles.image_url,'') as image_url
+			profiles.id as owner_id, profiles.name as owner, profiles.city as city, COALESCE(profiles.image_url,'') as image_url,
+			products.reserved_for IS NOT NULL as reserved,
+			(SELECT auctions.id FROM auctions WHERE auctions.product_id = products.id AND auctions.status = 'OPEN') as auction_id
 		FROM 
 			products
+		JOIN 
+			categories ON categories.id = products.category_id
 		LEFT JOIN 
 			profiles 
 		ON 
 			profiles.id = products.account_id
 		WHERE 
 			products.id = $1 AND products.sold = FALSE AND products.published = TRUE AND products.deleted = FALSE
+			AND products.approval_status = 'APPROVED'
 		LIMIT 1
 	`
 	rows, err := r.DB.QueryContext(ctx, query, id)
@@ -335,8 +486,9 @@ func (r *ProductRepositoryImpl) GetOne(ctx context.Context, id uuid.UUID) ([]web
 
 	for rows.Next() {
 		product := web.ProductDetailResponse{}
-		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.Description,
-			&product.UpdatedAt, &product.Sold, &product.Published, &product.OwnerId, &product.Owner, &product.City, &product.ImageUrl)
+		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.CategorySlug, &product.Description,
+			&product.UpdatedAt, &product.Sold, &product.Published, &product.OwnerId, &product.Owner, &product.City, &product.ImageUrl,
+			&product.Reserved, &product.AuctionId)
 		if err != nil {
 			log.Printf("failed to scanning product, err : %v\n", err)
 			return products, helper.NewInternal()
@@ -350,15 +502,22 @@ func (r *ProductRepositoryImpl) GetOne(ctx context.Context, id uuid.UUID) ([]web
 
 func (r *ProductRepositoryImpl) GetByAccount(ctx context.Context, account_id uuid.UUID, status bool, published bool) ([]web.ProductResponse, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetByAccount")
+	defer span.End()
+
 	products := []web.ProductResponse{}
 
 	query := `
 		SELECT 
-			id, name, price, category, COALESCE(thumbnail,'') as thumbnail 
+			products.id, products.name, products.price, categories.name, categories.slug, 
+			COALESCE(products.thumbnail,'') as thumbnail, products.approval_status, COALESCE(products.rejection_reason,''),
+			products.reserved_for IS NOT NULL
 		FROM 
 			products
+		JOIN 
+			categories ON categories.id = products.category_id
 		WHERE 
-			account_id = $1 AND sold=$2 AND published=$3 AND deleted=FALSE
+			products.account_id = $1 AND products.sold=$2 AND products.published=$3 AND products.deleted=FALSE
 		LIMIT 50
 	`
 	rows, err := r.DB.QueryContext(ctx, query, account_id, status, published)
@@ -369,7 +528,8 @@ func (r *ProductRepositoryImpl) GetByAccount(ctx context.Context, account_id uui
 
 	for rows.Next() {
 		product := web.ProductResponse{}
-		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.Thumbnail)
+		err := rows.Scan(&product.Id, &product.Name, &product.Price, &product.Category, &product.CategorySlug, &product.Thumbnail,
+			&product.ApprovalStatus, &product.RejectionReason, &product.Reserved)
 		if err != nil {
 			log.Printf("failed to scanning product, err : %v\n", err)
 			return products, helper.NewInternal()
@@ -383,6 +543,9 @@ func (r *ProductRepositoryImpl) GetByAccount(ctx context.Context, account_id uui
 
 func (r *ProductRepositoryImpl) GetProduct(ctx context.Context, id uuid.UUID) ([]web.OfferByProduct, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetProduct")
+	defer span.End()
+
 	products := []web.OfferByProduct{}
 
 	query := `
@@ -416,16 +579,22 @@ func (r *ProductRepositoryImpl) GetProduct(ctx context.Context, id uuid.UUID) ([
 
 func (r *ProductRepositoryImpl) Update(ctx context.Context, product *entity.Product) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.Update")
+	defer span.End()
+
 	query := `
 	UPDATE 
 		products 
 	SET 
-		name = $1, price = $2, category = $3, description = $4, updated_at = $5
+		name = $1, price = $2, category_id = $3, description = $4, updated_at = $5,
+		approval_status = $7, published = published AND $7 = 'APPROVED', rejection_reason = NULL, submitted_at = NULL,
+		min_price = $8, auto_accept_price = $9
 	WHERE 
 		id = $6 AND sold = FALSE AND deleted = FALSE
 	`
 
-	result, err := r.DB.ExecContext(ctx, query, product.Name, product.Price, product.Category, product.Description, product.UpdatedAt, product.Id)
+	result, err := r.DB.ExecContext(ctx, query, product.Name, product.Price, product.CategoryId, product.Description, product.UpdatedAt, product.Id,
+		product.ApprovalStatus, product.MinPrice, product.AutoAcceptPrice)
 
 	if err != nil {
 		log.Printf("failed to query update product, err : %v\n", err)
@@ -448,6 +617,9 @@ func (r *ProductRepositoryImpl) Update(ctx context.Context, product *entity.Prod
 
 func (r *ProductRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.Delete")
+	defer span.End()
+
 	query := `
 	UPDATE 
 		products 
@@ -480,11 +652,15 @@ func (r *ProductRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error
 
 func (r *ProductRepositoryImpl) Publish(ctx context.Context, id uuid.UUID, status bool) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.Publish")
+	defer span.End()
+
 	query := `
 	UPDATE 
 		products 
 	SET 
-		published = $1, updated_at = $2
+		published = $1, updated_at = $2,
+		approval_status = CASE WHEN $1 THEN 'APPROVED' ELSE approval_status END
 	WHERE 
 		id = $3 AND deleted = FALSE
 	`
@@ -510,8 +686,161 @@ func (r *ProductRepositoryImpl) Publish(ctx context.Context, id uuid.UUID, statu
 	return nil
 }
 
+// GetApproval returns the review state of a listing and whether its category
+// requires a review before publishing
+func (r *ProductRepositoryImpl) GetApproval(ctx context.Context, id uuid.UUID) (string, bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetApproval")
+	defer span.End()
+
+	approval := struct {
+		Status 				string 	`db:"approval_status"`
+		RequiresApproval 	bool 	`db:"requires_approval"`
+	}{}
+
+	query := `
+	SELECT
+		products.approval_status, categories.requires_approval
+	FROM 
+		products
+	JOIN 
+		categories ON categories.id = products.category_id
+	WHERE 
+		products.id = $1 AND products.deleted = FALSE
+	`
+
+	if err := r.DB.GetContext(ctx, &approval, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return "", false, helper.NewNotFound("product id", id.String())
+		}
+
+		log.Printf("failed to query get product approval, err : %v\n", err)
+		return "", false, helper.NewInternal()
+	}
+
+	return approval.Status, approval.RequiresApproval, nil
+}
+
+// SubmitForReview puts an unpublished draft or rejected listing in the
+// review queue
+func (r *ProductRepositoryImpl) SubmitForReview(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.SubmitForReview")
+	defer span.End()
+
+	query := `
+	UPDATE 
+		products 
+	SET 
+		approval_status = 'PENDING', rejection_reason = NULL, submitted_at = now(), updated_at = now()
+	WHERE 
+		id = $1 AND deleted = FALSE AND approval_status IN ('DRAFT', 'REJECTED')
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		log.Printf("failed to query submit product for review, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when submit product for review, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewBadRequest("this product is already waiting for review")
+	}
+
+	return nil
+}
+
+// Review records the decision on a pending listing. An approved listing is
+// published right away since its owner already asked to publish it.
+func (r *ProductRepositoryImpl) Review(ctx context.Context, req web.ProductReviewRequest, status string) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.Review")
+	defer span.End()
+
+	query := `
+	UPDATE 
+		products 
+	SET 
+		approval_status = $1, rejection_reason = NULLIF($2, ''), reviewed_by = $3, reviewed_at = now(),
+		published = ($1 = 'APPROVED'), updated_at = now()
+	WHERE 
+		id = $4 AND deleted = FALSE AND approval_status = 'PENDING'
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, status, req.Reason, req.AdminId, req.Id)
+	if err != nil {
+		log.Printf("failed to query review product, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when review product, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewBadRequest("this product is not waiting for review")
+	}
+
+	return nil
+}
+
+// GetPendingReview lists the listings waiting for review, the longest
+// waiting first
+func (r *ProductRepositoryImpl) GetPendingReview(ctx context.Context, page web.PageRequest) ([]web.PendingProductResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.GetPendingReview")
+	defer span.End()
+
+	products := []web.PendingProductResponse{}
+	total := 0
+
+	from := `
+		FROM 
+			products
+		JOIN 
+			categories ON categories.id = products.category_id
+		JOIN 
+			profiles ON profiles.id = products.account_id
+		WHERE 
+			products.approval_status = 'PENDING' AND products.deleted = FALSE
+	`
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from); err != nil {
+		log.Printf("failed to query count products pending review, err : %v\n", err)
+		return products, total, helper.NewInternal()
+	}
+
+	query := `
+		SELECT 
+			products.id, products.name, products.price, categories.name as category, products.description,
+			COALESCE(products.thumbnail,'') as thumbnail, profiles.id as owner_id, profiles.name as owner,
+			products.submitted_at
+	` + from + `
+		ORDER BY products.submitted_at ASC
+		LIMIT $1 OFFSET $2
+	`
+
+	if err := r.DB.SelectContext(ctx, &products, query, page.Limit(), page.Offset()); err != nil {
+		log.Printf("failed to query get products pending review, err : %v\n", err)
+		return products, total, helper.NewInternal()
+	}
+
+	return products, total, nil
+}
+
 func (r *ProductRepositoryImpl) CheckPublished(ctx context.Context, productId uuid.UUID) (bool, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.CheckPublished")
+	defer span.End()
+
 	product := &entity.Product{}
 
 	query := `
@@ -537,6 +866,9 @@ func (r *ProductRepositoryImpl) CheckPublished(ctx context.Context, productId uu
 
 func (r *ProductRepositoryImpl) CheckSold(ctx context.Context, productId uuid.UUID) (bool, error) {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.CheckSold")
+	defer span.End()
+
 	product := &entity.Product{}
 
 	query := `
@@ -561,13 +893,20 @@ func (r *ProductRepositoryImpl) CheckSold(ctx context.Context, productId uuid.UU
 	return product.Sold, nil
 }
 
+// SetSold marks a product sold or available again. Selling a reserved product
+// ends its reservation.
 func (r *ProductRepositoryImpl) SetSold(ctx context.Context, id uuid.UUID, status bool) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.SetSold")
+	defer span.End()
+
 	query := `
 	UPDATE 
 		products 
 	SET 
-		sold = $1, updated_at = $2
+		sold = $1, updated_at = $2,
+		reserved_for = CASE WHEN $1 THEN NULL ELSE reserved_for END,
+		reserved_until = CASE WHEN $1 THEN NULL ELSE reserved_until END
 	WHERE 
 		id = $3 AND deleted = FALSE
 	`
@@ -592,3 +931,307 @@ func (r *ProductRepositoryImpl) SetSold(ctx context.Context, id uuid.UUID, statu
 
 	return nil
 }
+
+// ExportByAccount lists every product of the account, deleted ones included,
+// with all of their image urls
+func (r *ProductRepositoryImpl) ExportByAccount(ctx context.Context, accountId uuid.UUID) ([]web.ExportProduct, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.ExportByAccount")
+	defer span.End()
+
+	products := []web.ExportProduct{}
+
+	query := `
+	SELECT 
+		p.id, p.name, p.price, c.name as category, p.description, COALESCE(p.thumbnail, '') as thumbnail,
+		COALESCE(array_agg(i.image_url) FILTER (WHERE i.id IS NOT NULL), '{}') as images,
+		p.sold, p.published, p.deleted, p.created_at, p.updated_at
+	FROM 
+		products p
+	JOIN 
+		categories c ON c.id = p.category_id
+	LEFT JOIN 
+		images i ON i.product_id = p.id
+	WHERE 
+		p.account_id = $1
+	GROUP BY 
+		p.id, c.name
+	ORDER BY 
+		p.created_at
+	`
+
+	if err := r.DB.SelectContext(ctx, &products, query, accountId); err != nil {
+		log.Printf("failed to query export products, err : %v\n", err)
+		return products, helper.NewInternal()
+	}
+
+	return products, nil
+}
+
+func (r *ProductRepositoryImpl) CheckHidden(ctx context.Context, productId uuid.UUID) (bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.CheckHidden")
+	defer span.End()
+
+	product := &entity.Product{}
+
+	query := "SELECT hidden FROM products WHERE id=$1 LIMIT 1"
+
+	if err := r.DB.GetContext(ctx, product, query, productId); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return false, helper.NewNotFound("product id", productId.String())
+		}
+
+		log.Printf("failed to query check hidden status, err : %v\n", err)
+		return false, helper.NewInternal()
+	}
+
+	return product.Hidden, nil
+}
+
+// Hide unpublishes a listing on a moderator's decision and keeps its owner
+// from publishing it again
+func (r *ProductRepositoryImpl) Hide(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.Hide")
+	defer span.End()
+
+	query := "UPDATE products SET hidden = TRUE, published = FALSE, updated_at = $1 WHERE id = $2 AND deleted = FALSE"
+
+	result, err := r.DB.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		log.Printf("failed to query hide product, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when hide product, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewNotFound("id", id.String())
+	}
+
+	return nil
+}
+
+// UnpublishStale unpublishes the listings left unchanged since before, except
+// those with an accepted offer or a running auction, and lets their sellers
+// know. It returns how many listings were unpublished.
+func (r *ProductRepositoryImpl) UnpublishStale(ctx context.Context, before time.Time) (int64, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.UnpublishStale")
+	defer span.End()
+
+	query := `
+	WITH stale AS (
+		UPDATE 
+			products 
+		SET 
+			published = FALSE, updated_at = now()
+		WHERE 
+			published = TRUE AND sold = FALSE AND deleted = FALSE AND updated_at < $1
+			AND NOT EXISTS (
+				SELECT 1 FROM transactions 
+				WHERE transactions.product_id = products.id AND transactions.accepted = TRUE AND transactions.deleted = FALSE
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM auctions 
+				WHERE auctions.product_id = products.id AND auctions.status = 'OPEN'
+			)
+		RETURNING 
+			account_id, name
+	)
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	SELECT 
+		account_id, 'LISTING_UNPUBLISHED', name || ' was unpublished after a long time without changes, publish it again to keep selling'
+	FROM 
+		stale
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, before)
+	if err != nil {
+		log.Printf("failed to query unpublish stale products, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when unpublish stale products, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	return row, nil
+}
+
+// ReleaseReservation makes a reserved product available again, the offer that
+// reserved it is no longer accepted, its unpaid order is cancelled and its
+// buyer is let known
+func (r *ProductRepositoryImpl) ReleaseReservation(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.ReleaseReservation")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin release reservation, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	query := `
+	UPDATE 
+		products 
+	SET 
+		reserved_for = NULL, reserved_until = NULL, updated_at = now()
+	FROM 
+		(SELECT id, reserved_for FROM products WHERE id = $1 FOR UPDATE) old
+	WHERE 
+		products.id = old.id AND old.reserved_for IS NOT NULL AND products.deleted = FALSE
+	RETURNING 
+		old.reserved_for
+	`
+
+	transactionId := uuid.Nil
+	if err := tx.GetContext(ctx, &transactionId, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewBadRequest("this product is not reserved")
+		}
+
+		log.Printf("failed to query release reservation, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	err = cancelOpenOrder(ctx, tx, transactionId, "the seller released the product")
+	if err != nil {
+		return err
+	}
+
+	query = `
+	WITH released AS (
+		UPDATE 
+			transactions 
+		SET 
+			accepted = FALSE, updated_at = now()
+		WHERE 
+			id = $1
+		RETURNING 
+			buyer_id, product_id
+	)
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	SELECT 
+		released.buyer_id, 'RESERVATION_ENDED', 'the seller released ' || products.name || ', your offer is open again'
+	FROM 
+		released
+	JOIN 
+		products ON products.id = released.product_id
+	`
+
+	if _, err := tx.ExecContext(ctx, query, transactionId); err != nil {
+		log.Printf("failed to query release reserved offer, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit release reservation, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// EndReservations releases the products whose reservation ran out before now
+// without a payment, reopens the offers that reserved them, cancels their
+// orders and lets the seller and buyer know. It returns how many reservations
+// ended.
+func (r *ProductRepositoryImpl) EndReservations(ctx context.Context, now time.Time) (int64, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProductRepository.EndReservations")
+	defer span.End()
+
+	query := `
+	WITH due AS (
+		SELECT 
+			id, reserved_for
+		FROM 
+			products
+		WHERE 
+			reserved_for IS NOT NULL AND reserved_until < $1
+			AND NOT EXISTS (
+				SELECT 1 FROM orders 
+				WHERE orders.transaction_id = products.reserved_for AND orders.status IN ('PAID', 'SHIPPED', 'DELIVERED')
+			)
+		FOR UPDATE
+	), released AS (
+		UPDATE 
+			products 
+		SET 
+			reserved_for = NULL, reserved_until = NULL, updated_at = now()
+		FROM 
+			due
+		WHERE 
+			products.id = due.id
+		RETURNING 
+			products.account_id, products.name, due.reserved_for
+	), reopened AS (
+		UPDATE 
+			transactions 
+		SET 
+			accepted = FALSE, updated_at = now()
+		FROM 
+			released
+		WHERE 
+			transactions.id = released.reserved_for
+		RETURNING 
+			transactions.buyer_id, released.account_id, released.name
+	), cancelled AS (
+		UPDATE 
+			orders 
+		SET 
+			status = 'CANCELLED', cancel_reason = 'the reservation ended without a payment', cancelled_at = now(), updated_at = now()
+		FROM 
+			released
+		WHERE 
+			orders.transaction_id = released.reserved_for AND orders.status = 'AWAITING_PAYMENT'
+		RETURNING 
+			orders.id
+	), abandoned AS (
+		UPDATE 
+			payments 
+		SET 
+			status = 'CANCELLED', updated_at = now()
+		FROM 
+			cancelled
+		WHERE 
+			payments.order_id = cancelled.id AND payments.status = 'PENDING'
+	), notified AS (
+		INSERT INTO 
+			notifications (account_id, kind, message)
+		SELECT 
+			account_id, 'RESERVATION_ENDED', 'the reservation of ' || name || ' ended without a sale, it is available again'
+		FROM 
+			reopened
+		UNION ALL
+		SELECT 
+			buyer_id, 'RESERVATION_ENDED', 'the reservation of ' || name || ' for you ended without a sale, your offer is open again'
+		FROM 
+			reopened
+	)
+	SELECT 
+		COUNT(*) 
+	FROM 
+		due
+	`
+
+	var count int64
+	if err := r.DB.GetContext(ctx, &count, query, now); err != nil {
+		log.Printf("failed to query end reservations, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	return count, nil
+}
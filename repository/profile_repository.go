@@ -6,6 +6,8 @@ import (
 
 	"github.com/RuhullahReza/SecondHand/helper"
 	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/util"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
@@ -18,20 +20,28 @@ type ProfileRepository interface {
 	GetProfileById(ctx context.Context, id uuid.UUID) (*entity.Profile, error)
 	GetProfileImage(ctx context.Context, id uuid.UUID) (*entity.Profile, error)
 	UpdateImage(ctx context.Context, p *entity.Profile) error
+	UpdatePrivacy(ctx context.Context, settings web.PrivacySettings) error
+	HasAcceptedOffer(ctx context.Context, a uuid.UUID, b uuid.UUID) (bool, error)
+	Reencrypt(ctx context.Context, batchSize int) (int, error)
 }
 
 type ProfileRepositoryImpl struct {
 	DB *sqlx.DB
+	Keyring *util.Keyring
 }
 
-func NewProfileRepository(db *sqlx.DB) ProfileRepository {
+func NewProfileRepository(db *sqlx.DB, keyring *util.Keyring) ProfileRepository {
 	return &ProfileRepositoryImpl{
 		DB: db,
+		Keyring: keyring,
 	}
 }
 
 func (r *ProfileRepositoryImpl) Create(ctx context.Context, p *entity.Profile) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.Create")
+	defer span.End()
+
 	query := "INSERT INTO profiles (id, name) VALUES ($1, $2)"
 	_, err := r.DB.ExecContext(ctx, query, p.Id, p.Name)
 
@@ -45,9 +55,27 @@ func (r *ProfileRepositoryImpl) Create(ctx context.Context, p *entity.Profile) e
 
 func (r *ProfileRepositoryImpl) Update(ctx context.Context, p *entity.Profile) error {
 	
-	query := "UPDATE profiles SET name = $1, city = $2, address = $3, phone_number = $4, updated_at = $5 WHERE id = $6"
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.Update")
+	defer span.End()
+
+	address, err := r.Keyring.Encrypt(p.Address)
+	if err != nil {
+		log.Printf("failed to encrypt address on update profile, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	phoneNumber, err := r.Keyring.Encrypt(p.PhoneNumber)
+	if err != nil {
+		log.Printf("failed to encrypt phone number on update profile, err : %v\n", err)
+		return helper.NewInternal()
+	}
 
-	result, err := r.DB.ExecContext(ctx, query, p.Name, p.City, p.Address, p.PhoneNumber, p.UpdatedAt, p.Id)
+	query := `
+	UPDATE profiles 
+	SET name = $1, city = $2, address = $3, phone_number = $4, latitude = $5, longitude = $6, updated_at = $7 
+	WHERE id = $8`
+
+	result, err := r.DB.ExecContext(ctx, query, p.Name, p.City, address, phoneNumber, p.Latitude, p.Longitude, p.UpdatedAt, p.Id)
 
 	if err != nil {
 		log.Printf("failed to query update profile, err : %v\n", err)
@@ -70,6 +98,9 @@ func (r *ProfileRepositoryImpl) Update(ctx context.Context, p *entity.Profile) e
 
 func (r *ProfileRepositoryImpl) GetNameById(ctx context.Context, id uuid.UUID) (*entity.Profile, error) {
 	
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.GetNameById")
+	defer span.End()
+
 	profile := &entity.Profile{}
 
 	query := "SELECT name FROM profiles WHERE id=$1"
@@ -88,15 +119,23 @@ func (r *ProfileRepositoryImpl) GetNameById(ctx context.Context, id uuid.UUID) (
 
 func (r *ProfileRepositoryImpl) GetProfileById(ctx context.Context, id uuid.UUID) (*entity.Profile, error) {
 	
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.GetProfileById")
+	defer span.End()
+
 	profile := &entity.Profile{}
 
 	query := `
 	SELECT 
+		id,
 		COALESCE(name, '') as name, 
 		COALESCE(city, '') as city,
 		COALESCE(address, '') as address, 
 		COALESCE(phone_number, '') as phone_number, 
-		COALESCE(image_url, '') as image_url 
+		COALESCE(image_url, '') as image_url,
+		latitude,
+		longitude,
+		phone_visibility,
+		address_visibility 
 	FROM profiles 
 	WHERE id=$1
 	`
@@ -110,11 +149,28 @@ func (r *ProfileRepositoryImpl) GetProfileById(ctx context.Context, id uuid.UUID
 		return profile, helper.NewInternal()
 	}
 
+	address, err := r.Keyring.Decrypt(profile.Address)
+	if err != nil {
+		log.Printf("failed to decrypt address of profile %v, err : %v\n", id, err)
+		return profile, helper.NewInternal()
+	}
+
+	phoneNumber, err := r.Keyring.Decrypt(profile.PhoneNumber)
+	if err != nil {
+		log.Printf("failed to decrypt phone number of profile %v, err : %v\n", id, err)
+		return profile, helper.NewInternal()
+	}
+
+	profile.Address, profile.PhoneNumber = address, phoneNumber
+
 	return profile, nil
 }
 
 func (r *ProfileRepositoryImpl) CheckProfile(ctx context.Context, id uuid.UUID) (bool, error) {
 	
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.CheckProfile")
+	defer span.End()
+
 	profile := &entity.Profile{}
 
 	query := `
@@ -158,6 +214,9 @@ func (r *ProfileRepositoryImpl) CheckProfile(ctx context.Context, id uuid.UUID)
 
 func (r *ProfileRepositoryImpl) GetProfileImage(ctx context.Context, id uuid.UUID) (*entity.Profile, error) {
 	
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.GetProfileImage")
+	defer span.End()
+
 	profile := &entity.Profile{}
 
 	query := `
@@ -181,6 +240,9 @@ func (r *ProfileRepositoryImpl) GetProfileImage(ctx context.Context, id uuid.UUI
 
 func (r *ProfileRepositoryImpl) UpdateImage(ctx context.Context, p *entity.Profile) error {
 	
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.UpdateImage")
+	defer span.End()
+
 	query := "UPDATE profiles SET image_url = $1, updated_at = $2 WHERE id = $3"
 
 	result, err := r.DB.ExecContext(ctx, query, p.ImageUrl, p.UpdatedAt, p.Id)
@@ -204,3 +266,124 @@ func (r *ProfileRepositoryImpl) UpdateImage(ctx context.Context, p *entity.Profi
 	return nil
 }
 
+
+func (r *ProfileRepositoryImpl) UpdatePrivacy(ctx context.Context, settings web.PrivacySettings) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.UpdatePrivacy")
+	defer span.End()
+
+	query := "UPDATE profiles SET phone_visibility = $1, address_visibility = $2, updated_at = now() WHERE id = $3"
+
+	result, err := r.DB.ExecContext(ctx, query, settings.PhoneVisibility, settings.AddressVisibility, settings.Id)
+	if err != nil {
+		log.Printf("failed to query update privacy, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when update privacy, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if row == 0 {
+		return helper.NewNotFound("id", settings.Id.String())
+	}
+
+	return nil
+}
+
+// HasAcceptedOffer reports whether a and b share an accepted offer, whichever
+// of them was the seller
+func (r *ProfileRepositoryImpl) HasAcceptedOffer(ctx context.Context, a uuid.UUID, b uuid.UUID) (bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.HasAcceptedOffer")
+	defer span.End()
+
+	var accepted bool
+
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM transactions 
+		WHERE accepted = TRUE AND deleted = FALSE
+		AND ((seller_id = $1 AND buyer_id = $2) OR (seller_id = $2 AND buyer_id = $1))
+	)`
+
+	if err := r.DB.GetContext(ctx, &accepted, query, a, b); err != nil {
+		log.Printf("failed to query has accepted offer, err : %v\n", err)
+		return false, helper.NewInternal()
+	}
+
+	return accepted, nil
+}
+
+// Reencrypt seals every phone number and address that is plaintext or uses a
+// retired key, batchSize rows at a time. It returns the rows rewritten.
+func (r *ProfileRepositoryImpl) Reencrypt(ctx context.Context, batchSize int) (int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ProfileRepository.Reencrypt")
+	defer span.End()
+
+	updated := 0
+	lastId := uuid.Nil
+
+	for {
+		profiles := []entity.Profile{}
+
+		query := `
+		SELECT id, COALESCE(address, '') as address, COALESCE(phone_number, '') as phone_number
+		FROM profiles WHERE id > $1 ORDER BY id LIMIT $2`
+
+		if err := r.DB.SelectContext(ctx, &profiles, query, lastId, batchSize); err != nil {
+			log.Printf("failed to query profiles to re-encrypt, err : %v\n", err)
+			return updated, helper.NewInternal()
+		}
+
+		for _, profile := range profiles {
+			lastId = profile.Id
+
+			if !r.Keyring.Stale(profile.Address) && !r.Keyring.Stale(profile.PhoneNumber) {
+				continue
+			}
+
+			address, err := r.reseal(profile.Address)
+			if err != nil {
+				log.Printf("failed to re-encrypt address of profile %v, err : %v\n", profile.Id, err)
+				return updated, helper.NewInternal()
+			}
+
+			phoneNumber, err := r.reseal(profile.PhoneNumber)
+			if err != nil {
+				log.Printf("failed to re-encrypt phone number of profile %v, err : %v\n", profile.Id, err)
+				return updated, helper.NewInternal()
+			}
+
+			// skip rows changed since they were read
+			query := `
+			UPDATE profiles SET address = NULLIF($1, ''), phone_number = NULLIF($2, '')
+			WHERE id = $3 AND COALESCE(address, '') = $4 AND COALESCE(phone_number, '') = $5`
+
+			_, err = r.DB.ExecContext(ctx, query, address, phoneNumber, profile.Id, profile.Address, profile.PhoneNumber)
+			if err != nil {
+				log.Printf("failed to re-encrypt profile %v, err : %v\n", profile.Id, err)
+				return updated, helper.NewInternal()
+			}
+
+			updated++
+		}
+
+		if len(profiles) < batchSize {
+			return updated, nil
+		}
+	}
+}
+
+func (r *ProfileRepositoryImpl) reseal(stored string) (string, error) {
+
+	value, err := r.Keyring.Decrypt(stored)
+	if err != nil {
+		return "", err
+	}
+
+	return r.Keyring.Encrypt(value)
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ReportRepository interface {
+	Create(ctx context.Context, report *entity.Report) error
+	CountOpen(ctx context.Context, targetType string, targetId uuid.UUID) (int, error)
+	GetById(ctx context.Context, id uuid.UUID) (*entity.Report, error)
+	GetQueue(ctx context.Context, filter web.ReportFilter, page web.PageRequest) ([]web.ReportResponse, int, error)
+	Resolve(ctx context.Context, report *entity.Report) ([]uuid.UUID, error)
+	Reopen(ctx context.Context, ids []uuid.UUID) error
+}
+
+type ReportRepositoryImpl struct {
+	DB *sqlx.DB
+}
+
+func NewReportRepository(db *sqlx.DB) ReportRepository {
+	return &ReportRepositoryImpl{
+		DB: db,
+	}
+}
+
+func (r *ReportRepositoryImpl) Create(ctx context.Context, report *entity.Report) error {
+
+	ctx, span := helper.StartSpan(ctx, "ReportRepository.Create")
+	defer span.End()
+
+	query := `
+	INSERT INTO reports (reporter_id, target_type, target_id, reported_account_id, reason, details, due_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err := r.DB.ExecContext(ctx, query, report.ReporterId, report.TargetType, report.TargetId, report.ReportedAccountId,
+		report.Reason, report.Details, report.DueAt)
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "unique_violation" {
+			return helper.NewConflict("report", report.TargetId.String())
+		}
+
+		log.Printf("failed to query create report, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// CountOpen counts the open reports on a target, which is the number of
+// people reporting it since each may only have one open
+func (r *ReportRepositoryImpl) CountOpen(ctx context.Context, targetType string, targetId uuid.UUID) (int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ReportRepository.CountOpen")
+	defer span.End()
+
+	count := 0
+
+	query := "SELECT COUNT(*) FROM reports WHERE target_type = $1 AND target_id = $2 AND status = 'OPEN'"
+
+	if err := r.DB.GetContext(ctx, &count, query, targetType, targetId); err != nil {
+		log.Printf("failed to query count open reports, err : %v\n", err)
+		return count, helper.NewInternal()
+	}
+
+	return count, nil
+}
+
+func (r *ReportRepositoryImpl) GetById(ctx context.Context, id uuid.UUID) (*entity.Report, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ReportRepository.GetById")
+	defer span.End()
+
+	report := &entity.Report{}
+
+	query := "SELECT * FROM reports WHERE id = $1"
+
+	if err := r.DB.GetContext(ctx, report, query, id); err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return report, helper.NewNotFound("id", id.String())
+		}
+
+		log.Printf("failed to query get report by id, err : %v\n", err)
+		return report, helper.NewInternal()
+	}
+
+	return report, nil
+}
+
+// GetQueue lists reports by status, OPEN when unset, the ones closest to
+// their deadline first
+func (r *ReportRepositoryImpl) GetQueue(ctx context.Context, filter web.ReportFilter, page web.PageRequest) ([]web.ReportResponse, int, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ReportRepository.GetQueue")
+	defer span.End()
+
+	reports := []web.ReportResponse{}
+	total := 0
+
+	if filter.Status == "" {
+		filter.Status = entity.ReportOpen
+	}
+
+	from := `
+	FROM
+		reports r
+	JOIN
+		profiles reported ON reported.id = r.reported_account_id
+	JOIN
+		profiles reporter ON reporter.id = r.reporter_id
+	WHERE
+		r.status = $1 AND ($2 = '' OR r.target_type = $2)
+	`
+
+	if err := r.DB.GetContext(ctx, &total, "SELECT COUNT(*) "+from, filter.Status, filter.TargetType); err != nil {
+		log.Printf("failed to query count report queue, err : %v\n", err)
+		return reports, total, helper.NewInternal()
+	}
+
+	query := `
+	SELECT
+		r.id, r.target_type, r.target_id, r.reported_account_id, reported.name as reported_name,
+		r.reporter_id, reporter.name as reporter_name, r.reason, r.details, r.status, r.action, r.note,
+		r.resolved_by, r.created_at, r.due_at, r.resolved_at,
+		(SELECT COUNT(*) FROM reports o
+			WHERE o.target_type = r.target_type AND o.target_id = r.target_id AND o.status = 'OPEN') as open_reports,
+		(r.status = 'OPEN' AND r.due_at < now()) as overdue
+	` + from + `
+	ORDER BY
+		r.due_at ASC
+	LIMIT $3 OFFSET $4
+	`
+
+	if err := r.DB.SelectContext(ctx, &reports, query, filter.Status, filter.TargetType, page.Limit(), page.Offset()); err != nil {
+		log.Printf("failed to query report queue, err : %v\n", err)
+		return reports, total, helper.NewInternal()
+	}
+
+	return reports, total, nil
+}
+
+// Resolve closes every open report on the target of report with its status,
+// action, note and moderator. Only one moderator gets to close them, the ids
+// closed are returned so they can be reopened when the action fails.
+func (r *ReportRepositoryImpl) Resolve(ctx context.Context, report *entity.Report) ([]uuid.UUID, error) {
+
+	ctx, span := helper.StartSpan(ctx, "ReportRepository.Resolve")
+	defer span.End()
+
+	ids := []uuid.UUID{}
+
+	query := `
+	UPDATE reports
+	SET status = $1, action = $2, note = $3, resolved_by = $4, resolved_at = now()
+	WHERE target_type = $5 AND target_id = $6 AND status = 'OPEN'
+	RETURNING id`
+
+	err := r.DB.SelectContext(ctx, &ids, query, report.Status, report.Action, report.Note, report.ResolvedBy,
+		report.TargetType, report.TargetId)
+
+	if err != nil {
+		log.Printf("failed to query resolve report, err : %v\n", err)
+		return ids, helper.NewInternal()
+	}
+
+	if len(ids) == 0 {
+		return ids, helper.NewBadRequest("the report was already resolved")
+	}
+
+	return ids, nil
+}
+
+// Reopen puts reports closed by Resolve back in the queue
+func (r *ReportRepositoryImpl) Reopen(ctx context.Context, ids []uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "ReportRepository.Reopen")
+	defer span.End()
+
+	query := `
+	UPDATE reports
+	SET status = 'OPEN', action = NULL, note = '', resolved_by = NULL, resolved_at = NULL
+	WHERE id = ANY($1) AND status <> 'OPEN'`
+
+	if _, err := r.DB.ExecContext(ctx, query, pq.Array(ids)); err != nil {
+		log.Printf("failed to query reopen report, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
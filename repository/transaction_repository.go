@@ -10,21 +10,27 @@ import (
 	"github.com/RuhullahReza/SecondHand/model/web"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type TransactionRepository interface {
-	Create(ctx context.Context, transaction *entity.Transaction) error
+	Create(ctx context.Context, transaction *entity.Transaction, note string, reserveUntil time.Time) (bool, error)
 	GetTransactionById(ctx context.Context, id uuid.UUID) (*entity.Transaction, error)
 	GetTransactionDetail(ctx context.Context, id uuid.UUID) ([]web.TransactionDetailResponse, error)
 	GetOfferByProduct(ctx context.Context, id uuid.UUID) ([]web.Offer, error)
 	GetOfferByBuyer(ctx context.Context, buyer_id uuid.UUID, seller_id uuid.UUID) ([]web.OfferWithProduct, error)
 	GetMyTransaction(ctx context.Context, buyer_id uuid.UUID) ([]web.OfferWithAccount, error)
 	GetOfferByAccount(ctx context.Context, seller_id uuid.UUID) ([]web.OfferWithAccount, error)
-	UpdatePrice(ctx context.Context, price int64, id uuid.UUID, buyer_id uuid.UUID) error
+	Propose(ctx context.Context, proposal *entity.OfferProposal, accept bool, reserveUntil time.Time) error
+	GetNegotiation(ctx context.Context, transactionId uuid.UUID) ([]web.NegotiationEntry, error)
 	CheckStatus(ctx context.Context, transactionId uuid.UUID) (bool, error)
-	SetStatus(ctx context.Context, status bool, id uuid.UUID, sellerId uuid.UUID) error
+	SetStatus(ctx context.Context, status bool, id uuid.UUID, sellerId uuid.UUID, reserveUntil time.Time) error
+	AcceptCounter(ctx context.Context, id uuid.UUID, buyerId uuid.UUID, reserveUntil time.Time) error
 	DeleteOne(ctx context.Context, id uuid.UUID) error
 	DeleteOnSold(ctx context.Context, product_id uuid.UUID) error
+	ExportByAccount(ctx context.Context, accountId uuid.UUID) ([]web.ExportOffer, error)
+	ExpireOffers(ctx context.Context, before time.Time) (int64, error)
+	RemindSellers(ctx context.Context, before time.Time) (int64, error)
 }
 
 type TransactionRepositoryImpl struct {
@@ -37,7 +43,26 @@ func NewTransactionRepository(db *sqlx.DB) TransactionRepository {
 	}
 }
 
-func (r *TransactionRepositoryImpl) Create(ctx context.Context, transaction *entity.Transaction) error {
+// Create makes an offer, or changes the price of the buyer's open offer on the
+// same product, and records the price in the negotiation history. An offer
+// marked accepted also reserves the product until reserveUntil. It reports
+// whether a new offer was made.
+func (r *TransactionRepositoryImpl) Create(ctx context.Context, transaction *entity.Transaction, note string, reserveUntil time.Time) (bool, error) {
+
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.Create")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin create transaction, err : %v\n", err)
+		return false, helper.NewInternal()
+	}
+	defer tx.Rollback()
+
+	result := struct {
+		Id 		uuid.UUID 	`db:"id"`
+		Created bool 		`db:"created"`
+	}{}
 
 	query := `
 	INSERT INTO 
@@ -45,23 +70,69 @@ func (r *TransactionRepositoryImpl) Create(ctx context.Context, transaction *ent
 		(buyer_id, seller_id, product_id, price_offer) 
 	VALUES 
 		($1, $2, $3, $4)
+	ON CONFLICT 
+		(product_id, buyer_id) WHERE deleted = FALSE
+	DO UPDATE SET 
+		price_offer = EXCLUDED.price_offer, updated_at = now()
+	WHERE 
+		transactions.accepted = FALSE
+	RETURNING 
+		id, (xmax = 0) as created
 	`
-	_, err := r.DB.ExecContext(ctx, query, transaction.BuyerId, transaction.SellerId, transaction.ProductId, transaction.PriceOffer)
+	err = tx.GetContext(ctx, &result, query, transaction.BuyerId, transaction.SellerId, transaction.ProductId, transaction.PriceOffer)
 
 	if err != nil {
+		// the buyer's offer on this product exists and was accepted already
+		if err.Error() == "sql: no rows in result set" {
+			return false, helper.NewConflict("accepted offer", transaction.ProductId.String())
+		}
+
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "check_violation" {
+			return false, helper.NewBadRequest("price offer must be greater than zero")
+		}
+
 		log.Printf("failed to query create transaction, err : %v\n", err)
-		return helper.NewInternal()
+		return false, helper.NewInternal()
 	}
 
-	return nil
+	transaction.Id = result.Id
+
+	err = insertProposal(ctx, tx, &entity.OfferProposal{
+		TransactionId: result.Id,
+		ProposedBy: transaction.BuyerId,
+		Role: entity.ProposalBuyer,
+		Amount: transaction.PriceOffer,
+		Note: note,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if transaction.Accepted {
+		err = acceptAndReserve(ctx, tx, result.Id, transaction.ProductId, reserveUntil)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit create transaction, err : %v\n", err)
+		return false, helper.NewInternal()
+	}
+
+	return result.Created, nil
 }
 
 func (r *TransactionRepositoryImpl) GetTransactionById(ctx context.Context, id uuid.UUID) (*entity.Transaction, error) {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.GetTransactionById")
+	defer span.End()
+
 	transaction := &entity.Transaction{}
 
 	query := `
-	SELECT *
+	SELECT 
+		id, seller_id, buyer_id, product_id, price_offer, accepted, created_at, updated_at, deleted, expired_at, reminded_at
 	FROM 
 		transactions
 	WHERE 
@@ -83,6 +154,9 @@ func (r *TransactionRepositoryImpl) GetTransactionById(ctx context.Context, id u
 
 func (r *TransactionRepositoryImpl) GetTransactionDetail(ctx context.Context, id uuid.UUID) ([]web.TransactionDetailResponse, error) {
 
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.GetTransactionDetail")
+	defer span.End()
+
 	transactions := []web.TransactionDetailResponse{}
 
 	query := `
@@ -129,6 +203,9 @@ func (r *TransactionRepositoryImpl) GetTransactionDetail(ctx context.Context, id
 
 func (r *TransactionRepositoryImpl) GetOfferByProduct(ctx context.Context, id uuid.UUID) ([]web.Offer, error) {
 
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.GetOfferByProduct")
+	defer span.End()
+
 	offers := []web.Offer{}
 
 	query := `
@@ -169,6 +246,9 @@ func (r *TransactionRepositoryImpl) GetOfferByProduct(ctx context.Context, id uu
 
 func (r *TransactionRepositoryImpl) GetOfferByBuyer(ctx context.Context, buyer_id uuid.UUID, seller_id uuid.UUID) ([]web.OfferWithProduct, error) {
 
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.GetOfferByBuyer")
+	defer span.End()
+
 	offers := []web.OfferWithProduct{}
 
 	query := `
@@ -204,6 +284,9 @@ func (r *TransactionRepositoryImpl) GetOfferByBuyer(ctx context.Context, buyer_i
 
 func (r *TransactionRepositoryImpl) GetOfferByAccount(ctx context.Context, seller_id uuid.UUID) ([]web.OfferWithAccount, error) {
 
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.GetOfferByAccount")
+	defer span.End()
+
 	offers := []web.OfferWithAccount{}
 
 	query := `
@@ -246,6 +329,9 @@ func (r *TransactionRepositoryImpl) GetOfferByAccount(ctx context.Context, selle
 
 func (r *TransactionRepositoryImpl) GetMyTransaction(ctx context.Context, buyer_id uuid.UUID) ([]web.OfferWithAccount, error) {
 
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.GetMyTransaction")
+	defer span.End()
+
 	offers := []web.OfferWithAccount{}
 
 	query := `
@@ -286,40 +372,167 @@ func (r *TransactionRepositoryImpl) GetMyTransaction(ctx context.Context, buyer_
 	return offers, nil
 }
 
-func (r *TransactionRepositoryImpl) UpdatePrice(ctx context.Context, price int64, id uuid.UUID, buyer_id uuid.UUID) error {
+// Propose sets the price of an open offer and records it in the negotiation
+// history. With accept the offer is accepted and reserves the product until
+// reserveUntil too.
+func (r *TransactionRepositoryImpl) Propose(ctx context.Context, proposal *entity.OfferProposal, accept bool, reserveUntil time.Time) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.Propose")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin propose price, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
 	query := `
 	UPDATE 
 		transactions 
 	SET 
 		price_offer = $1, updated_at = $2
 	WHERE 
-		id = $3 AND buyer_id = $4 AND accepted = FALSE AND deleted = FALSE
+		id = $3 AND accepted = FALSE AND deleted = FALSE
+	RETURNING 
+		product_id
 	`
 
-	result, err := r.DB.ExecContext(ctx, query, price, time.Now(), id, buyer_id)
+	productId := uuid.Nil
+	err = tx.GetContext(ctx, &productId, query, proposal.Amount, time.Now(), proposal.TransactionId)
 
 	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewNotFound("id", proposal.TransactionId.String())
+		}
+
+		if err, ok := err.(*pq.Error); ok && err.Code.Name() == "check_violation" {
+			return helper.NewBadRequest("price offer must be greater than zero")
+		}
+
 		log.Printf("failed to query update transaction, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
-	row, err := result.RowsAffected()
+	err = insertProposal(ctx, tx, proposal)
+	if err != nil {
+		return err
+	}
+
+	if accept {
+		err = acceptAndReserve(ctx, tx, proposal.TransactionId, productId, reserveUntil)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit propose price, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
 
+func insertProposal(ctx context.Context, tx *sqlx.Tx, proposal *entity.OfferProposal) error {
+
+	query := `
+	INSERT INTO 
+		offer_proposals 
+		(transaction_id, proposed_by, role, amount, note) 
+	VALUES 
+		($1, $2, $3, $4, $5)
+	`
+
+	_, err := tx.ExecContext(ctx, query, proposal.TransactionId, proposal.ProposedBy, proposal.Role, proposal.Amount, proposal.Note)
+	if err != nil {
+		log.Printf("failed to query record offer proposal, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// acceptAndReserve accepts an offer and holds its product for the buyer until
+// reserveUntil, unless another accepted offer holds it already
+func acceptAndReserve(ctx context.Context, tx *sqlx.Tx, transactionId uuid.UUID, productId uuid.UUID, reserveUntil time.Time) error {
+
+	query := "UPDATE transactions SET accepted = TRUE WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, query, transactionId); err != nil {
+		log.Printf("failed to query accept transaction, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return reserve(ctx, tx, transactionId, productId, reserveUntil)
+}
+
+// reserve holds a product for the buyer of an accepted offer until
+// reserveUntil and opens the order of the offer, unless another accepted offer
+// holds the product already
+func reserve(ctx context.Context, tx *sqlx.Tx, transactionId uuid.UUID, productId uuid.UUID, reserveUntil time.Time) error {
+
+	query := `
+	UPDATE 
+		products 
+	SET 
+		reserved_for = $1, reserved_until = $2, updated_at = now()
+	WHERE 
+		id = $3 AND reserved_for IS NULL AND sold = FALSE AND deleted = FALSE
+	`
+
+	result, err := tx.ExecContext(ctx, query, transactionId, reserveUntil, productId)
 	if err != nil {
-		log.Printf("failed to get rows affected when update transaction, err : %v\n", err)
+		log.Printf("failed to query reserve product, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when reserve product, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
 	if row == 0 {
-		return helper.NewNotFound("id", id.String())
+		return helper.NewBadRequest("this product is reserved for another buyer")
 	}
 
-	return nil
+	return createOrder(ctx, tx, transactionId)
+}
+
+// GetNegotiation lists every price proposed on an offer, oldest first
+func (r *TransactionRepositoryImpl) GetNegotiation(ctx context.Context, transactionId uuid.UUID) ([]web.NegotiationEntry, error) {
+
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.GetNegotiation")
+	defer span.End()
+
+	entries := []web.NegotiationEntry{}
+
+	query := `
+	SELECT 
+		o.proposed_by, p.name, o.role, o.amount, o.note, o.created_at
+	FROM 
+		offer_proposals o
+	JOIN 
+		profiles p ON p.id = o.proposed_by
+	WHERE 
+		o.transaction_id = $1
+	ORDER BY 
+		o.created_at, o.id
+	`
+
+	if err := r.DB.SelectContext(ctx, &entries, query, transactionId); err != nil {
+		log.Printf("failed to query get negotiation, err : %v\n", err)
+		return entries, helper.NewInternal()
+	}
+
+	return entries, nil
 }
 
 func (r *TransactionRepositoryImpl) CheckStatus(ctx context.Context, transactionId uuid.UUID) (bool, error) {
 
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.CheckStatus")
+	defer span.End()
+
 	transaction := &entity.Transaction{}
 
 	query := `
@@ -344,8 +557,22 @@ func (r *TransactionRepositoryImpl) CheckStatus(ctx context.Context, transaction
 	return transaction.Accepted, nil
 }
 
-func (r *TransactionRepositoryImpl) SetStatus(ctx context.Context, status bool, id uuid.UUID, sellerId uuid.UUID) error {
+// SetStatus accepts an offer and reserves its product until reserveUntil, or
+// takes the acceptance back, cancels its unpaid order and makes the product
+// available again. An offer whose latest price is the seller's counter waits
+// for the buyer to accept it.
+func (r *TransactionRepositoryImpl) SetStatus(ctx context.Context, status bool, id uuid.UUID, sellerId uuid.UUID, reserveUntil time.Time) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.SetStatus")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("failed to begin update transaction status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+	defer tx.Rollback()
+
 	query := `
 	UPDATE 
 		transactions 
@@ -353,31 +580,143 @@ func (r *TransactionRepositoryImpl) SetStatus(ctx context.Context, status bool,
 		accepted = $1
 	WHERE 
 		id = $2 AND seller_id = $3 AND deleted = FALSE
+	RETURNING 
+		product_id
 	`
 
-	result, err := r.DB.ExecContext(ctx, query, status, id, sellerId)
+	productId := uuid.Nil
+	err = tx.GetContext(ctx, &productId, query, status, id, sellerId)
 
 	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewNotFound("id", id.String())
+		}
+
 		log.Printf("failed to query update transaction, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
-	row, err := result.RowsAffected()
+	if status {
+		role, err := lastProposer(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if role == entity.ProposalSeller {
+			return helper.NewBadRequest("the buyer has to accept your counter-offer")
+		}
+
+		err = reserve(ctx, tx, id, productId, reserveUntil)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = cancelOpenOrder(ctx, tx, id, "the seller took the acceptance back")
+		if err != nil {
+			return err
+		}
+
+		query = `
+		UPDATE 
+			products 
+		SET 
+			reserved_for = NULL, reserved_until = NULL, updated_at = now()
+		WHERE 
+			id = $1 AND reserved_for = $2
+		`
+
+		if _, err := tx.ExecContext(ctx, query, productId, id); err != nil {
+			log.Printf("failed to query release reservation, err : %v\n", err)
+			return helper.NewInternal()
+		}
+	}
 
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit update transaction status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	return nil
+}
+
+// AcceptCounter lets the buyer accept the seller's counter-offer, which is the
+// latest price of the offer, reserving the product until reserveUntil
+func (r *TransactionRepositoryImpl) AcceptCounter(ctx context.Context, id uuid.UUID, buyerId uuid.UUID, reserveUntil time.Time) error {
+
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.AcceptCounter")
+	defer span.End()
+
+	tx, err := r.DB.BeginTxx(ctx, nil)
 	if err != nil {
-		log.Printf("failed to get rows affected when update transaction, err : %v\n", err)
+		log.Printf("failed to begin accept counter-offer, err : %v\n", err)
 		return helper.NewInternal()
 	}
+	defer tx.Rollback()
 
-	if row == 0 {
-		return helper.NewNotFound("id", id.String())
+	query := `
+	UPDATE 
+		transactions 
+	SET 
+		accepted = TRUE, updated_at = now()
+	WHERE 
+		id = $1 AND buyer_id = $2 AND accepted = FALSE AND deleted = FALSE
+	RETURNING 
+		product_id
+	`
+
+	productId := uuid.Nil
+	err = tx.GetContext(ctx, &productId, query, id, buyerId)
+
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return helper.NewNotFound("id", id.String())
+		}
+
+		log.Printf("failed to query accept counter-offer, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	role, err := lastProposer(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+
+	if role != entity.ProposalSeller {
+		return helper.NewBadRequest("there is no counter-offer to accept")
+	}
+
+	err = reserve(ctx, tx, id, productId, reserveUntil)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("failed to commit accept counter-offer, err : %v\n", err)
+		return helper.NewInternal()
 	}
 
 	return nil
 }
 
+// lastProposer tells which side proposed the current price of an offer
+func lastProposer(ctx context.Context, tx *sqlx.Tx, transactionId uuid.UUID) (string, error) {
+
+	role := ""
+
+	query := "SELECT role FROM offer_proposals WHERE transaction_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1"
+	if err := tx.GetContext(ctx, &role, query, transactionId); err != nil && err.Error() != "sql: no rows in result set" {
+		log.Printf("failed to query last proposal, err : %v\n", err)
+		return role, helper.NewInternal()
+	}
+
+	return role, nil
+}
+
 func (r *TransactionRepositoryImpl) DeleteOne(ctx context.Context, id uuid.UUID) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.DeleteOne")
+	defer span.End()
+
 	query := `
 	UPDATE 
 		transactions 
@@ -410,6 +749,9 @@ func (r *TransactionRepositoryImpl) DeleteOne(ctx context.Context, id uuid.UUID)
 
 func (r *TransactionRepositoryImpl) DeleteOnSold(ctx context.Context, product_id uuid.UUID) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.DeleteOnSold")
+	defer span.End()
+
 	query := `
 	UPDATE 
 		transactions 
@@ -438,4 +780,134 @@ func (r *TransactionRepositoryImpl) DeleteOnSold(ctx context.Context, product_id
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ExportByAccount lists the offers the account made as buyer or received as
+// seller, withdrawn ones included
+func (r *TransactionRepositoryImpl) ExportByAccount(ctx context.Context, accountId uuid.UUID) ([]web.ExportOffer, error) {
+
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.ExportByAccount")
+	defer span.End()
+
+	offers := []web.ExportOffer{}
+
+	query := `
+	SELECT 
+		t.id, 
+		CASE WHEN t.buyer_id = $1 THEN 'buyer' ELSE 'seller' END as role,
+		p.id as product_id, p.name as product_name,
+		u.id as counterparty_id, u.name as counterparty_name,
+		t.price_offer, COALESCE(t.accepted, FALSE) as accepted, COALESCE(t.deleted, FALSE) as deleted, 
+		t.created_at, t.updated_at
+	FROM
+		transactions t
+	JOIN 
+		products p ON t.product_id = p.id
+	JOIN
+		profiles u ON u.id = CASE WHEN t.buyer_id = $1 THEN t.seller_id ELSE t.buyer_id END
+	WHERE
+		t.buyer_id = $1 OR t.seller_id = $1
+	ORDER BY 
+		t.created_at
+	`
+
+	if err := r.DB.SelectContext(ctx, &offers, query, accountId); err != nil {
+		log.Printf("failed to query export offers, err : %v\n", err)
+		return offers, helper.NewInternal()
+	}
+
+	return offers, nil
+}
+
+// ExpireOffers withdraws the open offers without a new price since before and
+// lets their buyers know. It returns how many offers expired.
+func (r *TransactionRepositoryImpl) ExpireOffers(ctx context.Context, before time.Time) (int64, error) {
+
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.ExpireOffers")
+	defer span.End()
+
+	query := `
+	WITH expired AS (
+		UPDATE 
+			transactions 
+		SET 
+			deleted = TRUE, expired_at = now()
+		WHERE 
+			accepted = FALSE AND deleted = FALSE AND updated_at < $1
+		RETURNING 
+			buyer_id, product_id
+	)
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	SELECT 
+		expired.buyer_id, 'OFFER_EXPIRED', 'your offer on ' || products.name || ' expired without an answer'
+	FROM 
+		expired
+	JOIN 
+		products ON products.id = expired.product_id
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, before)
+	if err != nil {
+		log.Printf("failed to query expire offers, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when expire offers, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	return row, nil
+}
+
+// RemindSellers notifies sellers of offers whose latest price, proposed by
+// the buyer before the given time, is still unanswered. An offer is reminded
+// of once per price. It returns how many sellers were reminded.
+func (r *TransactionRepositoryImpl) RemindSellers(ctx context.Context, before time.Time) (int64, error) {
+
+	ctx, span := helper.StartSpan(ctx, "TransactionRepository.RemindSellers")
+	defer span.End()
+
+	query := `
+	WITH due AS (
+		UPDATE 
+			transactions t
+		SET 
+			reminded_at = now()
+		WHERE 
+			t.accepted = FALSE AND t.deleted = FALSE AND t.updated_at < $1
+			AND (t.reminded_at IS NULL OR t.reminded_at < t.updated_at)
+			AND (
+				SELECT o.role FROM offer_proposals o 
+				WHERE o.transaction_id = t.id 
+				ORDER BY o.created_at DESC, o.id DESC LIMIT 1
+			) = 'BUYER'
+		RETURNING 
+			t.seller_id
+	)
+	INSERT INTO 
+		notifications (account_id, kind, message)
+	SELECT 
+		seller_id, 'OFFER_REMINDER', 'offers waiting for your answer: ' || COUNT(*)
+	FROM 
+		due
+	GROUP BY 
+		seller_id
+	`
+
+	result, err := r.DB.ExecContext(ctx, query, before)
+	if err != nil {
+		log.Printf("failed to query remind sellers, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	row, err := result.RowsAffected()
+	if err != nil {
+		log.Printf("failed to get rows affected when remind sellers, err : %v\n", err)
+		return 0, helper.NewInternal()
+	}
+
+	return row, nil
+}
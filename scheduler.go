@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/RuhullahReza/SecondHand/service"
+	"github.com/RuhullahReza/SecondHand/util/config"
+	"github.com/jmoiron/sqlx"
+)
+
+func newJobService(db *sqlx.DB) service.JobService {
+	return service.NewJobService(
+		repository.NewJobRepository(db),
+		repository.NewTransactionRepository(db),
+		repository.NewProductRepository(db),
+		repository.NewDisputeRepository(db),
+		repository.NewAuctionRepository(db),
+		config.JobInterval(),
+		config.OfferTTL(),
+		config.OfferReminderAfter(),
+		config.StaleListingAfter(),
+		config.ReservationWindow(),
+	)
+}
+
+// schedule runs the background jobs until ctx is done. The returned channel
+// is closed once the job running at that moment has finished.
+func schedule(ctx context.Context, db *sqlx.DB) <-chan struct{} {
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		newJobService(db).Start(ctx)
+	}()
+
+	return done
+}
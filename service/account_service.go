@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/google/uuid"
+	"github.com/leebenson/conform"
+)
+
+type AccountService interface {
+	Export(ctx context.Context, id uuid.UUID, res *web.AccountExport) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CheckStatus(ctx context.Context, id uuid.UUID) error
+	SetStatus(ctx context.Context, req web.AccountStatusRequest) error
+	GetStatus(ctx context.Context, id uuid.UUID, res *web.AccountStatusResponse) error
+}
+
+type AccountServiceImpl struct {
+	AccountRepository 		repository.AccountRepository
+	ProfileRepository 		repository.ProfileRepository
+	ProductRepository 		repository.ProductRepository
+	TransactionRepository 	repository.TransactionRepository
+	ImageRepository 		repository.ImageRepository
+}
+
+func NewAccountService(
+	accountRepository repository.AccountRepository,
+	profileRepository repository.ProfileRepository,
+	productRepository repository.ProductRepository,
+	transactionRepository repository.TransactionRepository,
+	imageRepository repository.ImageRepository,
+	) AccountService {
+	return &AccountServiceImpl{
+		AccountRepository: accountRepository,
+		ProfileRepository: profileRepository,
+		ProductRepository: productRepository,
+		TransactionRepository: transactionRepository,
+		ImageRepository: imageRepository,
+	}
+}
+
+// Export gathers everything stored about the account, unmasked since the
+// owner is asking
+func (service *AccountServiceImpl) Export(ctx context.Context, id uuid.UUID, res *web.AccountExport) error {
+
+	ctx, span := helper.StartSpan(ctx, "AccountService.Export")
+	defer span.End()
+
+	account, err := service.AccountRepository.FindById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	profile, err := service.ProfileRepository.GetProfileById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	products, err := service.ProductRepository.ExportByAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	offers, err := service.TransactionRepository.ExportByAccount(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	res.Account = web.ExportAccount{
+		Id: account.Id,
+		Email: account.Email,
+		Role: account.Role,
+		CreatedAt: account.CreatedAt,
+	}
+
+	res.Profile = web.GetProfileResponse{
+		Id: profile.Id,
+		Name: profile.Name,
+		City: profile.City,
+		Address: profile.Address,
+		PhoneNumber: profile.PhoneNumber,
+		ImageUrl: profile.ImageUrl,
+		Latitude: profile.Latitude,
+		Longitude: profile.Longitude,
+		PhoneVisibility: profile.PhoneVisibility,
+		AddressVisibility: profile.AddressVisibility,
+	}
+
+	res.Products = products
+	res.Offers = offers
+	res.ExportedAt = time.Now()
+
+	return nil
+}
+
+// Delete closes the account, then removes its uploaded images from storage.
+// The account is gone once the database commits, so an image that fails to
+// delete is only logged by the image repository.
+func (service *AccountServiceImpl) Delete(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "AccountService.Delete")
+	defer span.End()
+
+	images, err := service.AccountRepository.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		service.ImageRepository.Delete(ctx, image)
+	}
+
+	return nil
+}
+
+// CheckStatus is what middleware.Auth runs on every request, so a suspension,
+// ban or deletion takes effect before the token expires
+func (service *AccountServiceImpl) CheckStatus(ctx context.Context, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "AccountService.CheckStatus")
+	defer span.End()
+
+	account, err := service.AccountRepository.GetStatus(ctx, id)
+	if err != nil {
+		if helper.Status(err) == http.StatusNotFound {
+			return helper.NewAuthorization("invalid token")
+		}
+		return err
+	}
+
+	if account.DeletedAt.Valid {
+		return helper.NewAuthorization("invalid token")
+	}
+
+	return accountStanding(account)
+}
+
+func (service *AccountServiceImpl) SetStatus(ctx context.Context, req web.AccountStatusRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "AccountService.SetStatus")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service set account status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if req.AccountId == req.AdminId {
+		return helper.NewBadRequest("you cannot change the status of your own account")
+	}
+
+	change := &entity.AccountStatusChange{
+		AccountId: req.AccountId,
+		Status: req.Status,
+		Reason: req.Reason,
+		AdminId: req.AdminId,
+	}
+
+	if req.Status == entity.AccountSuspended {
+		if !req.Until.After(time.Now()) {
+			return helper.NewBadRequest("a suspension must end in the future")
+		}
+
+		change.SuspendedUntil = sql.NullTime{Time: *req.Until, Valid: true}
+	}
+
+	err = service.AccountRepository.SetStatus(ctx, change)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *AccountServiceImpl) GetStatus(ctx context.Context, id uuid.UUID, res *web.AccountStatusResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "AccountService.GetStatus")
+	defer span.End()
+
+	account, err := service.AccountRepository.GetStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	history, err := service.AccountRepository.GetStatusHistory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	res.Status = entity.AccountActive
+	res.History = history
+
+	if accountStanding(account) != nil {
+		res.Status = account.Status
+		res.Reason = account.StatusReason.String
+
+		if account.SuspendedUntil.Valid {
+			res.SuspendedUntil = &account.SuspendedUntil.Time
+		}
+	}
+
+	return nil
+}
+
+// accountStanding refuses banned accounts and accounts whose suspension has
+// not lapsed yet
+func accountStanding(account *entity.Account) error {
+
+	switch account.Status {
+	case entity.AccountBanned:
+		return helper.NewAccountBanned(account.StatusReason.String)
+	case entity.AccountSuspended:
+		if account.SuspendedUntil.Time.After(time.Now()) {
+			return helper.NewAccountSuspended(account.SuspendedUntil.Time, account.StatusReason.String)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/google/uuid"
+)
+
+type AuctionService interface {
+	Create(ctx context.Context, req web.AuctionRequest, res *web.AuctionResponse) error
+	GetOpen(ctx context.Context, page web.PageRequest, res *[]web.AuctionResponse, meta *helper.Meta) error
+	GetById(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.AuctionResponse) error
+	Bid(ctx context.Context, req web.BidRequest, res *web.AuctionResponse) error
+	Cancel(ctx context.Context, payload helper.Payload, id uuid.UUID) error
+}
+
+type AuctionServiceImpl struct {
+	AuctionRepository 	repository.AuctionRepository
+	ProductRepository 	repository.ProductRepository
+	ProfileRepository 	repository.ProfileRepository
+	DisputeRepository 	repository.DisputeRepository
+	Extension 			time.Duration
+}
+
+func NewAuctionService(
+	auctionRepository repository.AuctionRepository,
+	productRepository repository.ProductRepository,
+	profileRepository repository.ProfileRepository,
+	disputeRepository repository.DisputeRepository,
+	extension time.Duration,
+	) AuctionService {
+	return &AuctionServiceImpl{
+		AuctionRepository: auctionRepository,
+		ProductRepository: productRepository,
+		ProfileRepository: profileRepository,
+		DisputeRepository: disputeRepository,
+		Extension: extension,
+	}
+}
+
+// Create auctions a product of the seller that could take offers right now.
+// No offers are taken on it until the auction ends.
+func (service *AuctionServiceImpl) Create(ctx context.Context, req web.AuctionRequest, res *web.AuctionResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionService.Create")
+	defer span.End()
+
+	err := service.ProductRepository.CheckOwner(ctx, req.SellerId, req.ProductId)
+	if err != nil {
+		return err
+	}
+
+	if !req.EndsAt.After(time.Now()) {
+		return helper.NewBadRequest("the auction must end in the future")
+	}
+
+	if req.ReservePrice != nil && *req.ReservePrice < req.StartPrice {
+		return helper.NewBadRequest("the reserve price cannot be below the start price")
+	}
+
+	product, err := service.ProductRepository.GetState(ctx, req.ProductId)
+	if err != nil {
+		return err
+	}
+
+	err = checkOfferable(product)
+	if err != nil {
+		return err
+	}
+
+	err = checkDispute(ctx, service.DisputeRepository, req.ProductId)
+	if err != nil {
+		return err
+	}
+
+	auction := &entity.Auction{
+		ProductId: req.ProductId,
+		SellerId: req.SellerId,
+		StartPrice: req.StartPrice,
+		MinIncrement: req.MinIncrement,
+		EndsAt: req.EndsAt,
+	}
+
+	if req.ReservePrice != nil {
+		auction.ReservePrice = sql.NullInt64{Int64: *req.ReservePrice, Valid: true}
+	}
+
+	err = service.AuctionRepository.Create(ctx, auction)
+	if err != nil {
+		return err
+	}
+
+	*res, err = service.AuctionRepository.GetOne(ctx, auction.Id)
+
+	return err
+}
+
+// GetOpen lists the running auctions, the one ending first first
+func (service *AuctionServiceImpl) GetOpen(ctx context.Context, page web.PageRequest, res *[]web.AuctionResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionService.GetOpen")
+	defer span.End()
+
+	auctions, total, err := service.AuctionRepository.GetOpen(ctx, page)
+	if err != nil {
+		return err
+	}
+
+	for i := range auctions {
+		auctions[i].ReservePrice = nil
+	}
+
+	*res = auctions
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+// GetById shows an auction and its bids, the reserve price only to its seller
+// and admins
+func (service *AuctionServiceImpl) GetById(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.AuctionResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionService.GetById")
+	defer span.End()
+
+	auction, err := service.AuctionRepository.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if payload.Role != "ADMIN" && payload.UserId != auction.SellerId {
+		auction.ReservePrice = nil
+	}
+
+	auction.Bids, err = service.AuctionRepository.GetBids(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	*res = auction
+
+	return nil
+}
+
+// Bid places a bid on a running auction. A bid close to the end gives the
+// other bidders Extension to answer it.
+func (service *AuctionServiceImpl) Bid(ctx context.Context, req web.BidRequest, res *web.AuctionResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionService.Bid")
+	defer span.End()
+
+	validProfile, err := service.ProfileRepository.CheckProfile(ctx, req.BidderId)
+	if err != nil {
+		return err
+	}
+
+	if !validProfile {
+		return helper.NewBadRequest("complete your profile first")
+	}
+
+	bid := &entity.Bid{
+		AuctionId: req.AuctionId,
+		BidderId: req.BidderId,
+		Amount: req.Amount,
+	}
+
+	err = service.AuctionRepository.PlaceBid(ctx, bid, time.Now(), service.Extension)
+	if err != nil {
+		return err
+	}
+
+	*res, err = service.AuctionRepository.GetOne(ctx, req.AuctionId)
+	res.ReservePrice = nil
+
+	return err
+}
+
+// Cancel lets the seller or an admin call off an auction nobody bid on yet
+func (service *AuctionServiceImpl) Cancel(ctx context.Context, payload helper.Payload, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "AuctionService.Cancel")
+	defer span.End()
+
+	auction, err := service.AuctionRepository.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if payload.Role != "ADMIN" && payload.UserId != auction.SellerId {
+		return helper.NewNotFound("auction id", id.String())
+	}
+
+	return service.AuctionRepository.Cancel(ctx, id)
+}
+
+// checkAuction keeps a product as it is while it is being auctioned
+func checkAuction(ctx context.Context, products repository.ProductRepository, productId uuid.UUID) error {
+
+	product, err := products.GetState(ctx, productId)
+	if err != nil {
+		return err
+	}
+
+	if product.Auctioned {
+		return helper.NewBadRequest("this product is being auctioned")
+	}
+
+	return nil
+}
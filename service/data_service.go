@@ -5,19 +5,27 @@ import (
 	"log"
 
 	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
 	"github.com/RuhullahReza/SecondHand/model/web"
 	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/RuhullahReza/SecondHand/util"
 	"github.com/google/uuid"
 	"github.com/leebenson/conform"
 )
 
 type DataService interface {
-	CreateCity(ctx context.Context, req web.CreateDataRequest) error
-	DeleteCity(ctx context.Context, id uuid.UUID) error
-	GetAllCity(ctx context.Context, res *[]web.DataResponse) error
-	CreateCategory(ctx context.Context, req web.CreateDataRequest) error
-	GetAllCategory(ctx context.Context, res *[]web.DataResponse) error
-	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	CreateCity(ctx context.Context, req web.CreateCityRequest) error
+	DeleteCity(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error
+	RenameCity(ctx context.Context, id uuid.UUID, req web.CreateDataRequest) error
+	CityUsage(ctx context.Context, id uuid.UUID, res *web.CityUsageResponse) error
+	GetAllCity(ctx context.Context, province string, res *[]web.CityResponse) error
+	GetAllProvince(ctx context.Context, res *[]web.DataResponse) error
+	CreateCategory(ctx context.Context, req web.CreateCategoryRequest) error
+	GetAllCategory(ctx context.Context, res *[]web.CategoryResponse) error
+	DeleteCategory(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error
+	RenameCategory(ctx context.Context, req web.RenameCategoryRequest) error
+	SetCategoryApproval(ctx context.Context, req web.CategoryApprovalRequest) error
+	CategoryUsage(ctx context.Context, id uuid.UUID, res *web.CategoryUsageResponse) error
 }
 
 type DataServiceImpl struct {
@@ -30,7 +38,10 @@ func NewDataService(repo repository.DataRepository) DataService {
 	}
 }
 
-func (service *DataServiceImpl) CreateCity(ctx context.Context, req web.CreateDataRequest) error {
+func (service *DataServiceImpl) CreateCity(ctx context.Context, req web.CreateCityRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataService.CreateCity")
+	defer span.End()
 
 	err := conform.Strings(&req)
 	if err != nil {
@@ -38,27 +49,96 @@ func (service *DataServiceImpl) CreateCity(ctx context.Context, req web.CreateDa
 		return helper.NewInternal()
 	}
 
-	err = service.DataRepository.CreateCity(ctx, req.Name)
+	city := &entity.City{
+		Name: req.Name,
+		ProvinceId: uuid.NullUUID{UUID: req.ProvinceId, Valid: true},
+	}
+
+	err = service.DataRepository.CreateCity(ctx, city)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *DataServiceImpl) DeleteCity(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataService.DeleteCity")
+	defer span.End()
+
+	err := service.DataRepository.DeleteCity(ctx, id, reassignTo)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *DataServiceImpl) RenameCity(ctx context.Context, id uuid.UUID, req web.CreateDataRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataService.RenameCity")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service rename city, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	err = service.DataRepository.RenameCity(ctx, id, req.Name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *DataServiceImpl) CityUsage(ctx context.Context, id uuid.UUID, res *web.CityUsageResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataService.CityUsage")
+	defer span.End()
+
+	usage, err := service.DataRepository.CityUsage(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	*res = usage
+
 	return nil
 }
 
-func (service *DataServiceImpl) DeleteCity(ctx context.Context, id uuid.UUID) error {
+func (service *DataServiceImpl) GetAllCity(ctx context.Context, province string, res *[]web.CityResponse) error {
+	
+	ctx, span := helper.StartSpan(ctx, "DataService.GetAllCity")
+	defer span.End()
+
+	provinceId := uuid.NullUUID{}
+	if province != "" {
+		found, err := service.DataRepository.FindProvince(ctx, province)
+		if err != nil {
+			return err
+		}
+		provinceId = uuid.NullUUID{UUID: found.Id, Valid: true}
+	}
 
-	err := service.DataRepository.DeleteCity(ctx, id)
+	data, err := service.DataRepository.GetAllCity(ctx, provinceId)
 	if err != nil {
 		return err
 	}
 
+	*res = data
+
 	return nil
 }
 
-func (service *DataServiceImpl) GetAllCity(ctx context.Context, res *[]web.DataResponse) error {
+func (service *DataServiceImpl) GetAllProvince(ctx context.Context, res *[]web.DataResponse) error {
 	
-	data, err := service.DataRepository.GetAllCity(ctx)
+	ctx, span := helper.StartSpan(ctx, "DataService.GetAllProvince")
+	defer span.End()
+
+	data, err := service.DataRepository.GetAllProvince(ctx)
 	if err != nil {
 		return err
 	}
@@ -68,15 +148,38 @@ func (service *DataServiceImpl) GetAllCity(ctx context.Context, res *[]web.DataR
 	return nil
 }
 
-func (service *DataServiceImpl) CreateCategory(ctx context.Context, req web.CreateDataRequest) error {
+func (service *DataServiceImpl) CreateCategory(ctx context.Context, req web.CreateCategoryRequest) error {
 	
+	ctx, span := helper.StartSpan(ctx, "DataService.CreateCategory")
+	defer span.End()
+
 	err := conform.Strings(&req)
 	if err != nil {
 		log.Printf("error while sanitize on service create category, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
-	err = service.DataRepository.CreateCategory(ctx, req.Name)
+	slug := req.Slug
+	if slug == "" {
+		slug = req.Name
+	}
+
+	category := &entity.Category{
+		Name: req.Name,
+		Slug: util.Slugify(slug),
+		SortOrder: req.SortOrder,
+		RequiresApproval: req.RequiresApproval,
+	}
+
+	if category.Slug == "" {
+		return helper.NewBadRequest("category slug cannot be empty")
+	}
+
+	if req.ParentId != nil {
+		category.ParentId = uuid.NullUUID{UUID: *req.ParentId, Valid: true}
+	}
+
+	err = service.DataRepository.CreateCategory(ctx, category)
 	if err != nil {
 		return err
 	}
@@ -84,8 +187,11 @@ func (service *DataServiceImpl) CreateCategory(ctx context.Context, req web.Crea
 	return nil
 }
 
-func (service *DataServiceImpl) GetAllCategory(ctx context.Context, res *[]web.DataResponse) error {
+func (service *DataServiceImpl) GetAllCategory(ctx context.Context, res *[]web.CategoryResponse) error {
 	
+	ctx, span := helper.StartSpan(ctx, "DataService.GetAllCategory")
+	defer span.End()
+
 	data, err := service.DataRepository.GetAllCategory(ctx)
 	if err != nil {
 		return err
@@ -96,12 +202,69 @@ func (service *DataServiceImpl) GetAllCategory(ctx context.Context, res *[]web.D
 	return nil
 }
 
-func (service *DataServiceImpl) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+func (service *DataServiceImpl) DeleteCategory(ctx context.Context, id uuid.UUID, reassignTo *uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataService.DeleteCategory")
+	defer span.End()
+
+	err := service.DataRepository.DeleteCategory(ctx, id, reassignTo)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *DataServiceImpl) RenameCategory(ctx context.Context, req web.RenameCategoryRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataService.RenameCategory")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service rename category, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if req.Slug != "" {
+		req.Slug = util.Slugify(req.Slug)
+		if req.Slug == "" {
+			return helper.NewBadRequest("category slug cannot be empty")
+		}
+	}
+
+	err = service.DataRepository.RenameCategory(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *DataServiceImpl) SetCategoryApproval(ctx context.Context, req web.CategoryApprovalRequest) error {
 
-	err := service.DataRepository.DeleteCategory(ctx, id)
+	ctx, span := helper.StartSpan(ctx, "DataService.SetCategoryApproval")
+	defer span.End()
+
+	err := service.DataRepository.SetCategoryApproval(ctx, req)
 	if err != nil {
 		return err
 	}
 
+	return nil
+}
+
+func (service *DataServiceImpl) CategoryUsage(ctx context.Context, id uuid.UUID, res *web.CategoryUsageResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "DataService.CategoryUsage")
+	defer span.End()
+
+	usage, err := service.DataRepository.CategoryUsage(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	*res = usage
+
 	return nil
 }
\ No newline at end of file
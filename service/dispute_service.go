@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"mime/multipart"
+	"time"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/google/uuid"
+	"github.com/leebenson/conform"
+)
+
+type DisputeService interface {
+	Open(ctx context.Context, req web.DisputeRequest, res *web.DisputeResponse) error
+	GetMine(ctx context.Context, accountId uuid.UUID, filter web.DisputeFilter, page web.PageRequest, res *[]web.DisputeResponse, meta *helper.Meta) error
+	GetById(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.DisputeResponse) error
+	AddMessage(ctx context.Context, payload helper.Payload, req web.DisputeMessageRequest) error
+	AddEvidence(ctx context.Context, payload helper.Payload, id uuid.UUID, imageFileHeader *multipart.FileHeader) error
+	GetQueue(ctx context.Context, filter web.DisputeFilter, page web.PageRequest, res *[]web.DisputeResponse, meta *helper.Meta) error
+	Resolve(ctx context.Context, req web.ResolveDisputeRequest) error
+}
+
+type DisputeServiceImpl struct {
+	DisputeRepository 		repository.DisputeRepository
+	TransactionRepository 	repository.TransactionRepository
+	OrderRepository 		repository.OrderRepository
+	PaymentRepository 		repository.PaymentRepository
+	ImageRepository 		repository.ImageRepository
+	Gateway 				util.PaymentGateway
+	ResponseWindow 			time.Duration
+}
+
+func NewDisputeService(
+	disputeRepository repository.DisputeRepository,
+	transactionRepository repository.TransactionRepository,
+	orderRepository repository.OrderRepository,
+	paymentRepository repository.PaymentRepository,
+	imageRepository repository.ImageRepository,
+	gateway util.PaymentGateway,
+	responseWindow time.Duration,
+	) DisputeService {
+	return &DisputeServiceImpl{
+		DisputeRepository: disputeRepository,
+		TransactionRepository: transactionRepository,
+		OrderRepository: orderRepository,
+		PaymentRepository: paymentRepository,
+		ImageRepository: imageRepository,
+		Gateway: gateway,
+		ResponseWindow: responseWindow,
+	}
+}
+
+// Open lets the buyer of an accepted offer dispute its order once it is paid
+// and until it is completed. The seller has ResponseWindow to answer.
+func (service *DisputeServiceImpl) Open(ctx context.Context, req web.DisputeRequest, res *web.DisputeResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeService.Open")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service open dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	transaction, err := service.TransactionRepository.GetTransactionById(ctx, req.TransactionId)
+	if err != nil {
+		return err
+	}
+
+	switch req.BuyerId {
+	case transaction.BuyerId:
+	case transaction.SellerId:
+		return helper.NewAuthorization("only the buyer can open a dispute")
+	default:
+		return helper.NewNotFound("transaction id", req.TransactionId.String())
+	}
+
+	if !transaction.Accepted {
+		return helper.NewBadRequest("only an accepted offer can be disputed")
+	}
+
+	order, found, err := service.OrderRepository.GetByTransaction(ctx, transaction.Id)
+	if err != nil {
+		return err
+	}
+
+	if !found || !contains([]string{entity.OrderPaid, entity.OrderShipped, entity.OrderDelivered}, order.Status) {
+		return helper.NewBadRequest("only a paid order that is not completed can be disputed")
+	}
+
+	dispute := &entity.Dispute{
+		TransactionId: transaction.Id,
+		OrderId: order.Id,
+		ProductId: order.ProductId,
+		BuyerId: order.BuyerId,
+		SellerId: order.SellerId,
+		Reason: req.Reason,
+		Details: req.Details,
+		Awaiting: sql.NullString{String: entity.ProposalSeller, Valid: true},
+		RespondBy: sql.NullTime{Time: time.Now().Add(service.ResponseWindow), Valid: true},
+	}
+
+	err = service.DisputeRepository.Create(ctx, dispute)
+	if err != nil {
+		return err
+	}
+
+	*res, err = service.DisputeRepository.GetOne(ctx, dispute.Id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (service *DisputeServiceImpl) GetMine(ctx context.Context, accountId uuid.UUID, filter web.DisputeFilter, page web.PageRequest, res *[]web.DisputeResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeService.GetMine")
+	defer span.End()
+
+	disputes, total, err := service.DisputeRepository.GetByAccount(ctx, accountId, filter, page)
+	if err != nil {
+		return err
+	}
+
+	*res = disputes
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+// GetById shows a dispute and its messages to its buyer, its seller and admins
+func (service *DisputeServiceImpl) GetById(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.DisputeResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeService.GetById")
+	defer span.End()
+
+	dispute, err := service.DisputeRepository.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if payload.Role != "ADMIN" && payload.UserId != dispute.BuyerId && payload.UserId != dispute.SellerId {
+		return helper.NewNotFound("dispute id", id.String())
+	}
+
+	dispute.Messages, err = service.DisputeRepository.GetMessages(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	*res = dispute
+
+	return nil
+}
+
+// AddMessage adds to the message trail of a dispute. An answer of the party
+// the dispute waits for gives the other party ResponseWindow to answer.
+func (service *DisputeServiceImpl) AddMessage(ctx context.Context, payload helper.Payload, req web.DisputeMessageRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeService.AddMessage")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service add dispute message, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	party, err := service.party(ctx, payload, req.DisputeId)
+	if err != nil {
+		return err
+	}
+
+	message := &entity.DisputeMessage{
+		DisputeId: req.DisputeId,
+		AuthorId: req.AuthorId,
+		Body: req.Body,
+	}
+
+	return service.DisputeRepository.AddMessage(ctx, message, party, time.Now().Add(service.ResponseWindow))
+}
+
+// AddEvidence uploads an image to the message trail of a dispute
+func (service *DisputeServiceImpl) AddEvidence(ctx context.Context, payload helper.Payload, id uuid.UUID, imageFileHeader *multipart.FileHeader) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeService.AddEvidence")
+	defer span.End()
+
+	party, err := service.party(ctx, payload, id)
+	if err != nil {
+		return err
+	}
+
+	imageFile, err := imageFileHeader.Open()
+	if err != nil {
+		log.Printf("error while open image on service add dispute evidence, err: %v\n", err)
+		return helper.NewInternal()
+	}
+
+	path, err := service.ImageRepository.Upload(ctx, imageFile, "secondHand-go/dispute")
+	if err != nil {
+		return err
+	}
+
+	message := &entity.DisputeMessage{
+		DisputeId: id,
+		AuthorId: payload.UserId,
+		ImageUrl: sql.NullString{String: path, Valid: true},
+	}
+
+	return service.DisputeRepository.AddMessage(ctx, message, party, time.Now().Add(service.ResponseWindow))
+}
+
+// party tells whether the account of payload is the buyer or the seller of an
+// unresolved dispute, empty for an admin
+func (service *DisputeServiceImpl) party(ctx context.Context, payload helper.Payload, id uuid.UUID) (string, error) {
+
+	dispute, err := service.DisputeRepository.GetById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	party := ""
+	switch {
+	case payload.UserId == dispute.BuyerId:
+		party = entity.ProposalBuyer
+	case payload.UserId == dispute.SellerId:
+		party = entity.ProposalSeller
+	case payload.Role != "ADMIN":
+		return "", helper.NewNotFound("dispute id", id.String())
+	}
+
+	if dispute.Status == entity.DisputeResolved {
+		return "", helper.NewBadRequest("the dispute was already resolved")
+	}
+
+	return party, nil
+}
+
+func (service *DisputeServiceImpl) GetQueue(ctx context.Context, filter web.DisputeFilter, page web.PageRequest, res *[]web.DisputeResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeService.GetQueue")
+	defer span.End()
+
+	disputes, total, err := service.DisputeRepository.GetQueue(ctx, filter, page)
+	if err != nil {
+		return err
+	}
+
+	*res = disputes
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+// Resolve settles a dispute. A refund gives the buyer the money held for the
+// order back and cancels it, a partial refund captures the price less the
+// refund for the seller and completes the order, and a reject lets the order
+// go on where it stopped. Money paid outside the app is left to the parties.
+// The dispute is claimed before the payment provider is asked to move the
+// money, and escalated again when the provider fails.
+func (service *DisputeServiceImpl) Resolve(ctx context.Context, req web.ResolveDisputeRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "DisputeService.Resolve")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service resolve dispute, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	dispute, err := service.DisputeRepository.GetById(ctx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	switch dispute.Status {
+	case entity.DisputeResolved:
+		return helper.NewBadRequest("the dispute was already resolved")
+	case entity.DisputeResolving:
+		return helper.NewBadRequest("the dispute is already being resolved")
+	}
+
+	order, err := service.OrderRepository.GetById(ctx, dispute.OrderId)
+	if err != nil {
+		return err
+	}
+
+	if req.Decision == entity.DisputePartialRefund && (req.RefundAmount <= 0 || req.RefundAmount >= order.Price) {
+		return helper.NewBadRequest("a partial refund must be more than zero and less than the price")
+	}
+
+	payment, paid, err := service.PaymentRepository.GetOpenByOrder(ctx, order.Id)
+	if err != nil {
+		return err
+	}
+
+	if !paid || payment.Status != entity.PaymentHeld {
+		payment = nil
+	}
+
+	// a delivered product is with the buyer, refunding it needs money still
+	// held in the app to move back
+	if payment == nil && order.Status == entity.OrderDelivered && req.Decision != entity.DisputeReject {
+		return helper.NewBadRequest("a delivered order whose money is not held in the app cannot be refunded")
+	}
+
+	err = service.DisputeRepository.Claim(ctx, dispute.Id)
+	if err != nil {
+		return err
+	}
+
+	from := order.Status
+	switch req.Decision {
+	case entity.DisputeRefund:
+		if payment != nil {
+			if _, err := service.Gateway.Refund(ctx, payment.ChargeId); err != nil {
+				log.Printf("failed to refund charge %s, err : %v\n", payment.ChargeId, err)
+				return service.unclaim(ctx, dispute.Id)
+			}
+
+			payment.Status = entity.PaymentRefunded
+		}
+
+		order.Status = entity.OrderCancelled
+		order.CancelReason = sql.NullString{String: "refunded after a dispute", Valid: true}
+	case entity.DisputePartialRefund:
+		if payment != nil {
+			captured := payment.Amount - req.RefundAmount
+			if _, err := service.Gateway.Capture(ctx, payment.ChargeId, captured); err != nil {
+				log.Printf("failed to capture charge %s, err : %v\n", payment.ChargeId, err)
+				return service.unclaim(ctx, dispute.Id)
+			}
+
+			// the platform keeps its share of what the seller gets
+			payment.Fee = payment.Fee * captured / payment.Amount
+			payment.RefundedAmount = req.RefundAmount
+			payment.Status = entity.PaymentReleased
+		}
+
+		order.Status = entity.OrderCompleted
+		dispute.RefundAmount = sql.NullInt64{Int64: req.RefundAmount, Valid: true}
+	default:
+		payment = nil
+	}
+
+	dispute.Resolution = sql.NullString{String: req.Decision, Valid: true}
+	dispute.ResolutionNote = req.Note
+	dispute.ResolvedBy = uuid.NullUUID{UUID: req.AdminId, Valid: true}
+
+	// once the provider moved the money a failure here leaves the dispute
+	// RESOLVING, to be reconciled with the provider rather than retried
+	err = service.DisputeRepository.Resolve(ctx, dispute, order, from, payment)
+	if err != nil {
+		log.Printf("failed to record resolution of claimed dispute %s, err : %v\n", dispute.Id, err)
+	}
+
+	return err
+}
+
+// unclaim escalates a claimed dispute again after the payment provider failed
+func (service *DisputeServiceImpl) unclaim(ctx context.Context, id uuid.UUID) error {
+
+	if err := service.DisputeRepository.Unclaim(ctx, id); err != nil {
+		return err
+	}
+
+	return helper.NewServiceUnavailable()
+}
+
+// checkDispute keeps a product, its accepted offer and its order as they are
+// while a dispute about them is open
+func checkDispute(ctx context.Context, disputes repository.DisputeRepository, productId uuid.UUID) error {
+
+	open, err := disputes.CheckOpen(ctx, productId)
+	if err != nil {
+		return err
+	}
+
+	if open {
+		return helper.NewBadRequest("this product has an open dispute")
+	}
+
+	return nil
+}
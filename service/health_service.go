@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+)
+
+type HealthService interface {
+	Readiness(ctx context.Context, res *web.ReadinessResponse)
+}
+
+type HealthServiceImpl struct {
+	HealthRepository	repository.HealthRepository
+	ImageRepository		repository.ImageRepository
+	MigrationVersion	uint
+}
+
+func NewHealthService(
+	healthRepository repository.HealthRepository,
+	imageRepository repository.ImageRepository,
+	migrationVersion uint,
+	) HealthService {
+	return &HealthServiceImpl{
+		HealthRepository: healthRepository,
+		ImageRepository: imageRepository,
+		MigrationVersion: migrationVersion,
+	}
+}
+
+func (service *HealthServiceImpl) Readiness(ctx context.Context, res *web.ReadinessResponse) {
+
+	ctx, span := helper.StartSpan(ctx, "HealthService.Readiness")
+	defer span.End()
+
+	res.Ready = true
+	res.Database = web.HealthCheck{Status: "up"}
+	res.Storage = web.HealthCheck{Status: "up"}
+	res.Migration = web.MigrationHealthCheck{Status: "up", Expected: service.MigrationVersion}
+
+	if err := service.HealthRepository.Ping(ctx); err != nil {
+		res.Ready = false
+		res.Database = web.HealthCheck{Status: "down", Message: err.Error()}
+	}
+
+	if err := service.ImageRepository.Ping(ctx); err != nil {
+		res.Ready = false
+		res.Storage = web.HealthCheck{Status: "down", Message: err.Error()}
+	}
+
+	if res.Database.Status != "up" {
+		res.Migration.Status = "unknown"
+		return
+	}
+
+	version, dirty, err := service.HealthRepository.MigrationVersion(ctx)
+	if err != nil {
+		res.Ready = false
+		res.Migration.Status = "down"
+		res.Migration.Message = err.Error()
+		return
+	}
+
+	res.Migration.Current = version
+	res.Migration.Dirty = dirty
+
+	if dirty {
+		res.Ready = false
+		res.Migration.Status = "down"
+		res.Migration.Message = fmt.Sprintf("migration version %d is dirty", version)
+		return
+	}
+
+	if version < service.MigrationVersion {
+		res.Ready = false
+		res.Migration.Status = "down"
+		res.Migration.Message = fmt.Sprintf("database is at version %d, expected %d", version, service.MigrationVersion)
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+)
+
+type JobService interface {
+	Start(ctx context.Context)
+	GetRuns(ctx context.Context, filter web.JobRunFilter, page web.PageRequest, res *[]web.JobRunResponse, meta *helper.Meta) error
+}
+
+type JobServiceImpl struct {
+	JobRepository 			repository.JobRepository
+	TransactionRepository 	repository.TransactionRepository
+	ProductRepository 		repository.ProductRepository
+	DisputeRepository 		repository.DisputeRepository
+	AuctionRepository 		repository.AuctionRepository
+	Interval 				time.Duration
+	OfferTTL 				time.Duration
+	ReminderAfter 			time.Duration
+	StaleListingAfter 		time.Duration
+	ReservationWindow 		time.Duration
+}
+
+func NewJobService(
+	jobRepository repository.JobRepository,
+	transactionRepository repository.TransactionRepository,
+	productRepository repository.ProductRepository,
+	disputeRepository repository.DisputeRepository,
+	auctionRepository repository.AuctionRepository,
+	interval time.Duration,
+	offerTTL time.Duration,
+	reminderAfter time.Duration,
+	staleListingAfter time.Duration,
+	reservationWindow time.Duration,
+	) JobService {
+	return &JobServiceImpl{
+		JobRepository: jobRepository,
+		TransactionRepository: transactionRepository,
+		ProductRepository: productRepository,
+		DisputeRepository: disputeRepository,
+		AuctionRepository: auctionRepository,
+		Interval: interval,
+		OfferTTL: offerTTL,
+		ReminderAfter: reminderAfter,
+		StaleListingAfter: staleListingAfter,
+		ReservationWindow: reservationWindow,
+	}
+}
+
+type job struct {
+	name string
+	run  func(ctx context.Context) (int64, error)
+}
+
+func (service *JobServiceImpl) jobs() []job {
+	return []job{
+		{entity.JobExpireOffers, func(ctx context.Context) (int64, error) {
+			return service.TransactionRepository.ExpireOffers(ctx, time.Now().Add(-service.OfferTTL))
+		}},
+		{entity.JobRemindSellers, func(ctx context.Context) (int64, error) {
+			return service.TransactionRepository.RemindSellers(ctx, time.Now().Add(-service.ReminderAfter))
+		}},
+		{entity.JobUnpublishStale, func(ctx context.Context) (int64, error) {
+			return service.ProductRepository.UnpublishStale(ctx, time.Now().Add(-service.StaleListingAfter))
+		}},
+		{entity.JobEndReservations, func(ctx context.Context) (int64, error) {
+			return service.ProductRepository.EndReservations(ctx, time.Now())
+		}},
+		{entity.JobEscalateDisputes, func(ctx context.Context) (int64, error) {
+			return service.DisputeRepository.EscalateOverdue(ctx, time.Now())
+		}},
+		{entity.JobCloseAuctions, func(ctx context.Context) (int64, error) {
+			now := time.Now()
+			return service.AuctionRepository.CloseEnded(ctx, now, now.Add(service.ReservationWindow))
+		}},
+	}
+}
+
+// Start runs every job right away and then each Interval until ctx is done.
+// Every replica runs the scheduler, the advisory lock of a job keeps them
+// from running it at the same time.
+func (service *JobServiceImpl) Start(ctx context.Context) {
+
+	ticker := time.NewTicker(service.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, job := range service.jobs() {
+			if ctx.Err() != nil {
+				return
+			}
+
+			service.runJob(ctx, job)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runJob runs job and records the run, unless another replica is running it
+func (service *JobServiceImpl) runJob(ctx context.Context, job job) {
+
+	ctx, span := helper.StartSpan(ctx, "JobService."+job.name)
+	defer span.End()
+
+	release, locked, err := service.JobRepository.TryLock(ctx, job.name)
+	if err != nil || !locked {
+		return
+	}
+	defer release()
+
+	id, err := service.JobRepository.StartRun(ctx, job.name)
+	if err != nil {
+		return
+	}
+
+	affected, jobErr := job.run(ctx)
+	if jobErr != nil {
+		log.Printf("job %s failed, err : %v\n", job.name, jobErr)
+	}
+
+	// record the outcome even when the run was cut short by a shutdown
+	err = service.JobRepository.FinishRun(context.Background(), id, affected, jobErr)
+	if err != nil {
+		return
+	}
+}
+
+func (service *JobServiceImpl) GetRuns(ctx context.Context, filter web.JobRunFilter, page web.PageRequest, res *[]web.JobRunResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "JobService.GetRuns")
+	defer span.End()
+
+	runs, total, err := service.JobRepository.GetRuns(ctx, filter, page)
+	if err != nil {
+		return err
+	}
+
+	*res = runs
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
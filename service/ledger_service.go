@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/google/uuid"
+	"github.com/leebenson/conform"
+)
+
+type LedgerService interface {
+	GetWallet(ctx context.Context, sellerId uuid.UUID, res *web.WalletResponse) error
+	GetStatement(ctx context.Context, sellerId uuid.UUID, page web.PageRequest, res *[]web.StatementLine, meta *helper.Meta) error
+	RequestPayout(ctx context.Context, req web.PayoutRequest, res *web.PayoutResponse) error
+	GetMyPayouts(ctx context.Context, sellerId uuid.UUID, page web.PageRequest, res *[]web.PayoutResponse, meta *helper.Meta) error
+	GetPayouts(ctx context.Context, filter web.PayoutFilter, page web.PageRequest, res *[]web.PayoutResponse, meta *helper.Meta) error
+	ReviewPayout(ctx context.Context, req web.PayoutReviewRequest) error
+}
+
+type LedgerServiceImpl struct {
+	LedgerRepository repository.LedgerRepository
+}
+
+func NewLedgerService(ledgerRepository repository.LedgerRepository) LedgerService {
+	return &LedgerServiceImpl{
+		LedgerRepository: ledgerRepository,
+	}
+}
+
+func (service *LedgerServiceImpl) GetWallet(ctx context.Context, sellerId uuid.UUID, res *web.WalletResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerService.GetWallet")
+	defer span.End()
+
+	wallet, err := service.LedgerRepository.GetWallet(ctx, sellerId)
+	if err != nil {
+		return err
+	}
+
+	*res = wallet
+
+	return nil
+}
+
+func (service *LedgerServiceImpl) GetStatement(ctx context.Context, sellerId uuid.UUID, page web.PageRequest, res *[]web.StatementLine, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerService.GetStatement")
+	defer span.End()
+
+	lines, total, err := service.LedgerRepository.GetStatement(ctx, sellerId, page)
+	if err != nil {
+		return err
+	}
+
+	*res = lines
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+// RequestPayout sets aside part of the seller's balance until an admin
+// reviews the payout
+func (service *LedgerServiceImpl) RequestPayout(ctx context.Context, req web.PayoutRequest, res *web.PayoutResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerService.RequestPayout")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service request payout, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	payout := &entity.Payout{
+		SellerId: req.SellerId,
+		Amount: req.Amount,
+		Destination: req.Destination,
+	}
+
+	err = service.LedgerRepository.RequestPayout(ctx, payout)
+	if err != nil {
+		return err
+	}
+
+	*res = web.PayoutResponse{
+		Id: payout.Id,
+		SellerId: payout.SellerId,
+		Amount: payout.Amount,
+		Destination: payout.Destination,
+		Status: payout.Status,
+		CreatedAt: payout.CreatedAt,
+	}
+
+	return nil
+}
+
+func (service *LedgerServiceImpl) GetMyPayouts(ctx context.Context, sellerId uuid.UUID, page web.PageRequest, res *[]web.PayoutResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerService.GetMyPayouts")
+	defer span.End()
+
+	payouts, total, err := service.LedgerRepository.GetPayoutsBySeller(ctx, sellerId, page)
+	if err != nil {
+		return err
+	}
+
+	*res = payouts
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+func (service *LedgerServiceImpl) GetPayouts(ctx context.Context, filter web.PayoutFilter, page web.PageRequest, res *[]web.PayoutResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerService.GetPayouts")
+	defer span.End()
+
+	payouts, total, err := service.LedgerRepository.GetPayouts(ctx, filter, page)
+	if err != nil {
+		return err
+	}
+
+	*res = payouts
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+func (service *LedgerServiceImpl) ReviewPayout(ctx context.Context, req web.PayoutReviewRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "LedgerService.ReviewPayout")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service review payout, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	status := entity.PayoutApproved
+	if req.Decision == "REJECT" {
+		status = entity.PayoutRejected
+	}
+
+	return service.LedgerRepository.ReviewPayout(ctx, req, status)
+}
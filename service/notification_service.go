@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/google/uuid"
+)
+
+type NotificationService interface {
+	GetByAccount(ctx context.Context, accountId uuid.UUID, page web.PageRequest, res *[]web.NotificationResponse, meta *helper.Meta) error
+}
+
+type NotificationServiceImpl struct {
+	NotificationRepository repository.NotificationRepository
+}
+
+func NewNotificationService(notificationRepository repository.NotificationRepository) NotificationService {
+	return &NotificationServiceImpl{
+		NotificationRepository: notificationRepository,
+	}
+}
+
+func (service *NotificationServiceImpl) GetByAccount(ctx context.Context, accountId uuid.UUID, page web.PageRequest, res *[]web.NotificationResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "NotificationService.GetByAccount")
+	defer span.End()
+
+	notifications, total, err := service.NotificationRepository.GetByAccount(ctx, accountId, page)
+	if err != nil {
+		return err
+	}
+
+	*res = notifications
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/google/uuid"
+	"github.com/leebenson/conform"
+)
+
+type OrderService interface {
+	GetMine(ctx context.Context, accountId uuid.UUID, filter web.OrderFilter, page web.PageRequest, res *[]web.OrderResponse, meta *helper.Meta) error
+	GetById(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.OrderResponse) error
+	UpdateStatus(ctx context.Context, req web.OrderStatusRequest) error
+}
+
+type OrderServiceImpl struct {
+	OrderRepository 	repository.OrderRepository
+	PaymentRepository 	repository.PaymentRepository
+	DisputeRepository 	repository.DisputeRepository
+	Gateway 			util.PaymentGateway
+}
+
+func NewOrderService(
+	orderRepository repository.OrderRepository,
+	paymentRepository repository.PaymentRepository,
+	disputeRepository repository.DisputeRepository,
+	gateway util.PaymentGateway,
+	) OrderService {
+	return &OrderServiceImpl{
+		OrderRepository: orderRepository,
+		PaymentRepository: paymentRepository,
+		DisputeRepository: disputeRepository,
+		Gateway: gateway,
+	}
+}
+
+// orderStep is how an order reaches a status: from which statuses and by
+// which party, both parties when by is empty
+type orderStep struct {
+	from []string
+	by   string
+}
+
+var orderSteps = map[string]orderStep{
+	entity.OrderPaid:      {from: []string{entity.OrderAwaitingPayment}, by: entity.ProposalSeller},
+	entity.OrderShipped:   {from: []string{entity.OrderPaid}, by: entity.ProposalSeller},
+	entity.OrderDelivered: {from: []string{entity.OrderShipped}, by: entity.ProposalBuyer},
+	entity.OrderCompleted: {from: []string{entity.OrderDelivered}},
+	entity.OrderCancelled: {from: []string{entity.OrderAwaitingPayment, entity.OrderPaid}},
+}
+
+func (service *OrderServiceImpl) GetMine(ctx context.Context, accountId uuid.UUID, filter web.OrderFilter, page web.PageRequest, res *[]web.OrderResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "OrderService.GetMine")
+	defer span.End()
+
+	orders, total, err := service.OrderRepository.GetByAccount(ctx, accountId, filter, page)
+	if err != nil {
+		return err
+	}
+
+	*res = orders
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+// GetById shows an order and its payments to its buyer, its seller and admins
+func (service *OrderServiceImpl) GetById(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.OrderResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "OrderService.GetById")
+	defer span.End()
+
+	order, err := service.OrderRepository.GetOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if payload.Role != "ADMIN" && payload.UserId != order.BuyerId && payload.UserId != order.SellerId {
+		return helper.NewNotFound("order id", id.String())
+	}
+
+	order.Payments, err = service.PaymentRepository.GetByOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	*res = order
+
+	return nil
+}
+
+// UpdateStatus moves an order one step forward. The seller confirms a payment
+// made outside the app and ships, the buyer confirms the delivery, and either
+// of them completes the order after delivery or cancels it before shipping.
+// Money paid in the app stays held through the delivery, so the buyer can
+// still dispute it, and is released to the seller on completion or refunded on
+// cancel.
+func (service *OrderServiceImpl) UpdateStatus(ctx context.Context, req web.OrderStatusRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "OrderService.UpdateStatus")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service update order status, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	order, err := service.OrderRepository.GetById(ctx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	party, notify := "", uuid.Nil
+	switch req.AccountId {
+	case order.BuyerId:
+		party, notify = entity.ProposalBuyer, order.SellerId
+	case order.SellerId:
+		party, notify = entity.ProposalSeller, order.BuyerId
+	default:
+		return helper.NewNotFound("order id", req.Id.String())
+	}
+
+	err = checkDispute(ctx, service.DisputeRepository, order.ProductId)
+	if err != nil {
+		return err
+	}
+
+	step, ok := orderSteps[req.Status]
+	if !ok || !contains(step.from, order.Status) {
+		return helper.NewBadRequest("the order cannot move to this status from its current status")
+	}
+
+	switch {
+	case step.by == entity.ProposalSeller && party != step.by:
+		return helper.NewAuthorization("only the seller can move the order to this status")
+	case step.by == entity.ProposalBuyer && party != step.by:
+		return helper.NewAuthorization("only the buyer can move the order to this status")
+	}
+
+	payment, paying, err := service.PaymentRepository.GetOpenByOrder(ctx, order.Id)
+	if err != nil {
+		return err
+	}
+
+	paymentStatus := ""
+	if paying {
+		if req.Status == entity.OrderPaid {
+			return helper.NewBadRequest("this order is being paid in the app")
+		}
+
+		paymentStatus, err = service.settle(ctx, order, payment, req.Status)
+		if err != nil {
+			return err
+		}
+	}
+
+	from := order.Status
+	order.Status = req.Status
+	if req.Status == entity.OrderShipped {
+		order.Carrier = sql.NullString{String: req.Carrier, Valid: true}
+		order.TrackingNumber = sql.NullString{String: req.TrackingNumber, Valid: true}
+	}
+	if req.Status == entity.OrderCancelled {
+		order.CancelReason = sql.NullString{String: req.Reason, Valid: true}
+	}
+
+	return service.OrderRepository.Move(ctx, order, from, notify, payment, paymentStatus)
+}
+
+// settle gets the payment provider to release the held money of an order to
+// the seller on completion or to refund it on cancel, and returns the status the
+// payment reaches with the order. The order is claimed first so the money is
+// moved once, a pending charge is just cancelled with its order.
+func (service *OrderServiceImpl) settle(ctx context.Context, order *entity.Order, payment *entity.Payment, status string) (string, error) {
+
+	switch {
+	case payment.Status == entity.PaymentHeld && status == entity.OrderCompleted:
+		if err := service.OrderRepository.Claim(ctx, order.Id, order.Status, status); err != nil {
+			return "", err
+		}
+
+		if _, err := service.Gateway.Capture(ctx, payment.ChargeId, payment.Amount); err != nil {
+			log.Printf("failed to capture charge %s, err : %v\n", payment.ChargeId, err)
+			return "", service.unclaim(ctx, order.Id)
+		}
+
+		return entity.PaymentReleased, nil
+	case payment.Status == entity.PaymentHeld && status == entity.OrderCancelled:
+		if err := service.OrderRepository.Claim(ctx, order.Id, order.Status, status); err != nil {
+			return "", err
+		}
+
+		if _, err := service.Gateway.Refund(ctx, payment.ChargeId); err != nil {
+			log.Printf("failed to refund charge %s, err : %v\n", payment.ChargeId, err)
+			return "", service.unclaim(ctx, order.Id)
+		}
+
+		return entity.PaymentRefunded, nil
+	case payment.Status == entity.PaymentPending && status == entity.OrderCancelled:
+		return entity.PaymentCancelled, nil
+	}
+
+	return "", nil
+}
+
+// unclaim frees a claimed order after the payment provider failed
+func (service *OrderServiceImpl) unclaim(ctx context.Context, id uuid.UUID) error {
+
+	if err := service.OrderRepository.Unclaim(ctx, id); err != nil {
+		return err
+	}
+
+	return helper.NewServiceUnavailable()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/google/uuid"
+)
+
+type PaymentService interface {
+	Pay(ctx context.Context, payload helper.Payload, orderId uuid.UUID, res *web.PaymentResponse) error
+	HandleWebhook(ctx context.Context, body []byte, signature string) error
+}
+
+type PaymentServiceImpl struct {
+	PaymentRepository 	repository.PaymentRepository
+	OrderRepository 	repository.OrderRepository
+	Gateway 			util.PaymentGateway
+	WebhookSecret 		[]byte
+	FeePercent 			int64
+}
+
+func NewPaymentService(
+	paymentRepository repository.PaymentRepository,
+	orderRepository repository.OrderRepository,
+	gateway util.PaymentGateway,
+	webhookSecret []byte,
+	feePercent int64,
+	) PaymentService {
+	return &PaymentServiceImpl{
+		PaymentRepository: paymentRepository,
+		OrderRepository: orderRepository,
+		Gateway: gateway,
+		WebhookSecret: webhookSecret,
+		FeePercent: feePercent,
+	}
+}
+
+// Pay charges the buyer for an order awaiting payment. The platform keeps
+// FeePercent of the price when the money is released to the seller. Paying
+// again while the charge is pending returns the same charge.
+func (service *PaymentServiceImpl) Pay(ctx context.Context, payload helper.Payload, orderId uuid.UUID, res *web.PaymentResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "PaymentService.Pay")
+	defer span.End()
+
+	order, err := service.OrderRepository.GetById(ctx, orderId)
+	if err != nil {
+		return err
+	}
+
+	if order.BuyerId != payload.UserId {
+		return helper.NewNotFound("order id", orderId.String())
+	}
+
+	if order.Status != entity.OrderAwaitingPayment {
+		return helper.NewBadRequest("only an order awaiting payment can be paid")
+	}
+
+	payment, found, err := service.PaymentRepository.GetOpenByOrder(ctx, orderId)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		charge, err := service.Gateway.CreateCharge(ctx, orderId.String(), order.Price)
+		if err != nil {
+			log.Printf("failed to create charge on %s, err : %v\n", service.Gateway.Name(), err)
+			return helper.NewServiceUnavailable()
+		}
+
+		payment = &entity.Payment{
+			TransactionId: order.TransactionId,
+			OrderId: orderId,
+			Provider: service.Gateway.Name(),
+			ChargeId: charge.Id,
+			Amount: order.Price,
+			Fee: order.Price * service.FeePercent / 100,
+			PaymentUrl: charge.PaymentUrl,
+		}
+
+		err = service.PaymentRepository.Create(ctx, payment)
+		if err != nil {
+			return err
+		}
+	}
+
+	*res = web.PaymentResponse{
+		Id: payment.Id,
+		TransactionId: payment.TransactionId,
+		OrderId: payment.OrderId,
+		Provider: payment.Provider,
+		ChargeId: payment.ChargeId,
+		Amount: payment.Amount,
+		Status: payment.Status,
+		PaymentUrl: payment.PaymentUrl,
+		CreatedAt: payment.CreatedAt,
+	}
+
+	return nil
+}
+
+// HandleWebhook applies an event the provider signed with the webhook secret.
+// Providers deliver events again until they are acknowledged, an event seen
+// before is acknowledged without doing anything.
+func (service *PaymentServiceImpl) HandleWebhook(ctx context.Context, body []byte, signature string) error {
+
+	ctx, span := helper.StartSpan(ctx, "PaymentService.HandleWebhook")
+	defer span.End()
+
+	if !util.VerifyWebhook(service.WebhookSecret, body, signature) {
+		return helper.NewAuthorization("invalid signature")
+	}
+
+	var event web.PaymentEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.Id == "" || event.Type == "" || event.ChargeId == "" {
+		return helper.NewBadRequest("invalid payment event")
+	}
+
+	payment, _, err := service.PaymentRepository.HandleEvent(ctx, service.Gateway.Name(), event)
+	if err != nil {
+		return err
+	}
+
+	// the order was cancelled while the buyer was paying, the money goes back.
+	// The provider delivering the event again retries a refund that failed.
+	if event.Type == entity.EventChargeAuthorized && payment.Status == entity.PaymentCancelled {
+		if _, err := service.Gateway.Refund(ctx, payment.ChargeId); err != nil {
+			log.Printf("failed to refund charge %s of a cancelled order, err : %v\n", payment.ChargeId, err)
+			return helper.NewServiceUnavailable()
+		}
+
+		return service.PaymentRepository.SetStatus(ctx, payment.Id, entity.PaymentRefunded, entity.PaymentCancelled)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/RuhullahReza/SecondHand/util"
+	"github.com/google/uuid"
+)
+
+// maskContact hides the phone number and address of profile from viewer
+// according to the profile's privacy settings. The owner and admins always see
+// both, ACCEPTED_BUYERS additionally reveals them to counterparties of an
+// accepted offer. Masked phone numbers keep a few digits, addresses are
+// emptied.
+func maskContact(ctx context.Context, repo repository.ProfileRepository, profile *entity.Profile, viewer helper.Payload) error {
+
+	if viewer.UserId == profile.Id || viewer.Role == "ADMIN" {
+		return nil
+	}
+
+	phone, err := visibleTo(ctx, repo, profile, profile.PhoneVisibility, viewer)
+	if err != nil {
+		return err
+	}
+
+	if !phone {
+		profile.PhoneNumber = util.MaskPhone(profile.PhoneNumber)
+	}
+
+	address, err := visibleTo(ctx, repo, profile, profile.AddressVisibility, viewer)
+	if err != nil {
+		return err
+	}
+
+	if !address {
+		profile.Address = ""
+	}
+
+	return nil
+}
+
+func visibleTo(ctx context.Context, repo repository.ProfileRepository, profile *entity.Profile, visibility string, viewer helper.Payload) (bool, error) {
+
+	switch visibility {
+	case entity.VisibilityPublic:
+		return true, nil
+	case entity.VisibilityAcceptedBuyers:
+		if viewer.UserId == uuid.Nil {
+			return false, nil
+		}
+		return repo.HasAcceptedOffer(ctx, profile.Id, viewer.UserId)
+	default:
+		return false, nil
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"mime/multipart"
 	"time"
@@ -10,14 +11,15 @@ import (
 	"github.com/RuhullahReza/SecondHand/model/entity"
 	"github.com/RuhullahReza/SecondHand/model/web"
 	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/RuhullahReza/SecondHand/util"
 	"github.com/google/uuid"
 	"github.com/leebenson/conform"
 )
 
 type ProductService interface {
 	CreateProduct(ctx context.Context, req web.CreateProductRequest, userId uuid.UUID) error
-	GetAllProduct(ctx context.Context, res *[]web.ProductResponse) error
-	GetByCategory(ctx context.Context, category string, res *[]web.ProductResponse) error
+	GetAllProduct(ctx context.Context, filter web.ProductFilter, page web.PageRequest, res *[]web.ProductResponse, meta *helper.Meta) error
+	GetByCategory(ctx context.Context, category string, page web.PageRequest, res *[]web.ProductResponse, meta *helper.Meta) error
 	GetByAccount(ctx context.Context, id uuid.UUID, status bool, published bool, res *[]web.ProductResponse) error
 	GetProductById(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.ProductDetailResponse) error 
 	UpdateProduct(ctx context.Context, req web.UpdateProductRequest) error
@@ -25,15 +27,27 @@ type ProductService interface {
 	SetThumbnail(ctx context.Context, accountId uuid.UUID, productId uuid.UUID, imageId uuid.UUID) error
 	DeleteProduct(ctx context.Context, payload helper.Payload, id uuid.UUID) error
 	DeleteImageProduct(ctx context.Context, accountId uuid.UUID, productId uuid.UUID, imageId uuid.UUID) error 
-	UpdatePublished(ctx context.Context, payload helper.Payload, id uuid.UUID, res *bool) error
+	UpdatePublished(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.PublishResponse) error
+	GetPendingReview(ctx context.Context, page web.PageRequest, res *[]web.PendingProductResponse, meta *helper.Meta) error
+	Review(ctx context.Context, req web.ProductReviewRequest) error
 	UpdateSold(ctx context.Context, payload helper.Payload, id uuid.UUID, res *bool) error
+	ReleaseReservation(ctx context.Context, payload helper.Payload, id uuid.UUID) error
 }
 
+// radius in km for GET /product?near=lat,lng
+const (
+	defaultNearRadius = 10
+	maxNearRadius     = 100
+)
+
 type ProductServiceImpl struct {
 	ProductRepository 	repository.ProductRepository
 	ProfileRepository 	repository.ProfileRepository 
 	DataRepository	  	repository.DataRepository
 	ImageRepository		repository.ImageRepository
+	ReportRepository	repository.ReportRepository
+	DisputeRepository	repository.DisputeRepository
+	ReportThreshold		int
 }
 
 func NewProductService(
@@ -41,17 +55,26 @@ func NewProductService(
 	profileRepository repository.ProfileRepository, 
 	dataRepository repository.DataRepository,
 	imageRepository	repository.ImageRepository,
+	reportRepository repository.ReportRepository,
+	disputeRepository repository.DisputeRepository,
+	reportThreshold int,
 	) ProductService {
 	return &ProductServiceImpl{
 		ProductRepository: productRepository,
 		ProfileRepository: profileRepository,
 		DataRepository: dataRepository,
 		ImageRepository: imageRepository,
+		ReportRepository: reportRepository,
+		DisputeRepository: disputeRepository,
+		ReportThreshold: reportThreshold,
 	}
 }
 
 func (service *ProductServiceImpl) CreateProduct(ctx context.Context, req web.CreateProductRequest, userId uuid.UUID) error {
 	
+	ctx, span := helper.StartSpan(ctx, "ProductService.CreateProduct")
+	defer span.End()
+
 	validProfile, err := service.ProfileRepository.CheckProfile(ctx,userId)
 	if err != nil {
 		return err
@@ -67,7 +90,7 @@ func (service *ProductServiceImpl) CreateProduct(ctx context.Context, req web.Cr
 		return helper.NewInternal()
 	}
 
-	err = service.DataRepository.CheckCategory(ctx, req.Category)
+	category, err := service.DataRepository.FindCategory(ctx, req.Category)
 	if err != nil {
 		return err
 	}
@@ -76,8 +99,9 @@ func (service *ProductServiceImpl) CreateProduct(ctx context.Context, req web.Cr
 		AccountId: userId,
 		Name: req.Name,
 		Price: req.Price,
-		Category: req.Category,
+		CategoryId: category.Id,
 		Description: req.Description,
+		ApprovalStatus: initialApproval(category),
 	}
 
 	err = service.ProductRepository.Create(ctx, product)
@@ -90,6 +114,9 @@ func (service *ProductServiceImpl) CreateProduct(ctx context.Context, req web.Cr
 
 func (service *ProductServiceImpl) GetProductById(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.ProductDetailResponse) error {
 	
+	ctx, span := helper.StartSpan(ctx, "ProductService.GetProductById")
+	defer span.End()
+
 	isOwner, err := service.ProductRepository.IsOwner(ctx, payload.UserId, id)
 	if err != nil {
 		return err
@@ -124,32 +151,79 @@ func (service *ProductServiceImpl) GetProductById(ctx context.Context, payload h
 	return nil
 }
 
-func (service *ProductServiceImpl) GetAllProduct(ctx context.Context, res *[]web.ProductResponse) error {
+func (service *ProductServiceImpl) GetAllProduct(ctx context.Context, filter web.ProductFilter, page web.PageRequest, res *[]web.ProductResponse, meta *helper.Meta) error {
 	
-	data, err := service.ProductRepository.GetAll(ctx)
+	ctx, span := helper.StartSpan(ctx, "ProductService.GetAllProduct")
+	defer span.End()
+
+	if filter.Province != "" {
+		province, err := service.DataRepository.FindProvince(ctx, filter.Province)
+		if err != nil {
+			return err
+		}
+		filter.ProvinceId = uuid.NullUUID{UUID: province.Id, Valid: true}
+	}
+
+	if filter.City != "" {
+		city, err := service.DataRepository.FindCity(ctx, filter.City)
+		if err != nil {
+			return err
+		}
+		filter.CityId = uuid.NullUUID{UUID: city.Id, Valid: true}
+	}
+
+	if filter.Near != "" {
+		lat, lng, err := util.ParseCoordinates(filter.Near)
+		if err != nil {
+			return helper.NewBadRequest(err.Error())
+		}
+		filter.Latitude, filter.Longitude = &lat, &lng
+
+		if filter.Radius <= 0 {
+			filter.Radius = defaultNearRadius
+		}
+		if filter.Radius > maxNearRadius {
+			filter.Radius = maxNearRadius
+		}
+	}
+
+	data, total, err := service.ProductRepository.GetAll(ctx, filter, page)
 	if err != nil {
 		return err
 	}
 
 	*res = data
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
 
 	return nil
 }
 
-func (service *ProductServiceImpl) GetByCategory(ctx context.Context, category string, res *[]web.ProductResponse) error {
+func (service *ProductServiceImpl) GetByCategory(ctx context.Context, category string, page web.PageRequest, res *[]web.ProductResponse, meta *helper.Meta) error {
 	
-	data, err := service.ProductRepository.GetByCategory(ctx, category)
+	ctx, span := helper.StartSpan(ctx, "ProductService.GetByCategory")
+	defer span.End()
+
+	found, err := service.DataRepository.FindCategory(ctx, category)
+	if err != nil {
+		return err
+	}
+
+	data, total, err := service.ProductRepository.GetByCategory(ctx, found.Id, page)
 	if err != nil {
 		return err
 	}
 
 	*res = data
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
 
 	return nil
 }
 
 func (service *ProductServiceImpl) GetByAccount(ctx context.Context, id uuid.UUID, status bool, published bool, res *[]web.ProductResponse) error {
 	
+	ctx, span := helper.StartSpan(ctx, "ProductService.GetByAccount")
+	defer span.End()
+
 	data, err := service.ProductRepository.GetByAccount(ctx, id, status, published)
 	if err != nil {
 		return err
@@ -162,18 +236,35 @@ func (service *ProductServiceImpl) GetByAccount(ctx context.Context, id uuid.UUI
 
 func (service *ProductServiceImpl) UpdateProduct(ctx context.Context, req web.UpdateProductRequest) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductService.UpdateProduct")
+	defer span.End()
+
 	err := service.ProductRepository.CheckOwner(ctx, req.AccountId, req.Id)
 	if err != nil {
 		return err
 	}
 
+	err = checkDispute(ctx, service.DisputeRepository, req.Id)
+	if err != nil {
+		return err
+	}
+
+	err = checkAuction(ctx, service.ProductRepository, req.Id)
+	if err != nil {
+		return err
+	}
+
 	err = conform.Strings(&req)
 	if err != nil {
 		log.Printf("error while sanitize on service update product, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
-	err = service.DataRepository.CheckCategory(ctx, req.Category)
+	if req.MinPrice != nil && req.AutoAcceptPrice != nil && *req.AutoAcceptPrice < *req.MinPrice {
+		return helper.NewBadRequest("the auto-accept price cannot be below the minimum price")
+	}
+
+	category, err := service.DataRepository.FindCategory(ctx, req.Category)
 	if err != nil {
 		return err
 	}
@@ -182,9 +273,18 @@ func (service *ProductServiceImpl) UpdateProduct(ctx context.Context, req web.Up
 		Id: req.Id,
 		Name: req.Name,
 		Price: req.Price,
-		Category: req.Category,
+		CategoryId: category.Id,
 		UpdatedAt: time.Now(),
 		Description: req.Description,
+		ApprovalStatus: initialApproval(category),
+	}
+
+	if req.MinPrice != nil {
+		product.MinPrice = sql.NullInt64{Int64: *req.MinPrice, Valid: true}
+	}
+
+	if req.AutoAcceptPrice != nil {
+		product.AutoAcceptPrice = sql.NullInt64{Int64: *req.AutoAcceptPrice, Valid: true}
 	}
 
 	err = service.ProductRepository.Update(ctx, product)
@@ -197,11 +297,24 @@ func (service *ProductServiceImpl) UpdateProduct(ctx context.Context, req web.Up
 
 func (service *ProductServiceImpl) AddProductImage(ctx context.Context, payload helper.Payload, id uuid.UUID, imageFileHeader *multipart.FileHeader) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductService.AddProductImage")
+	defer span.End()
+
 	err := service.ProductRepository.CheckOwner(ctx, payload.UserId, id)
 	if err != nil {
 		return err
 	}
 
+	err = checkDispute(ctx, service.DisputeRepository, id)
+	if err != nil {
+		return err
+	}
+
+	err = checkAuction(ctx, service.ProductRepository, id)
+	if err != nil {
+		return err
+	}
+
 	imageFile, err := imageFileHeader.Open()
 	if err != nil {
 		log.Printf("error while open image on service add product image, err: %v\n", err)
@@ -247,11 +360,24 @@ func (service *ProductServiceImpl) AddProductImage(ctx context.Context, payload
 
 func (service *ProductServiceImpl) SetThumbnail(ctx context.Context, accountId uuid.UUID, productId uuid.UUID, imageId uuid.UUID) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductService.SetThumbnail")
+	defer span.End()
+
 	image, err := service.ImageRepository.GetPathById(ctx, imageId)
 	if err != nil {
 		return err
 	}
 
+	err = checkDispute(ctx, service.DisputeRepository, productId)
+	if err != nil {
+		return err
+	}
+
+	err = checkAuction(ctx, service.ProductRepository, productId)
+	if err != nil {
+		return err
+	}
+
 	updatedProduct := &entity.Product{
 		Id: productId,
 		AccountId: accountId,
@@ -269,11 +395,24 @@ func (service *ProductServiceImpl) SetThumbnail(ctx context.Context, accountId u
 
 func (service *ProductServiceImpl) DeleteImageProduct(ctx context.Context, accountId uuid.UUID, productId uuid.UUID, imageId uuid.UUID) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductService.DeleteImageProduct")
+	defer span.End()
+
 	err := service.ProductRepository.CheckOwner(ctx, accountId, productId)
 	if err != nil {
 		return err
 	}
 
+	err = checkDispute(ctx, service.DisputeRepository, productId)
+	if err != nil {
+		return err
+	}
+
+	err = checkAuction(ctx, service.ProductRepository, productId)
+	if err != nil {
+		return err
+	}
+
 	image, err := service.ImageRepository.GetPathById(ctx, imageId)
 	if err != nil {
 		return err
@@ -303,14 +442,28 @@ func (service *ProductServiceImpl) DeleteImageProduct(ctx context.Context, accou
 
 func (service *ProductServiceImpl) DeleteProduct(ctx context.Context, payload helper.Payload, id uuid.UUID) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductService.DeleteProduct")
+	defer span.End()
+
 	if payload.Role != "ADMIN" {
 		err := service.ProductRepository.CheckOwner(ctx, payload.UserId, id)
 		if err != nil {
 			return err
 		}
+
+		// admins may still take an auctioned product down, it then ends unsold
+		err = checkAuction(ctx, service.ProductRepository, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := checkDispute(ctx, service.DisputeRepository, id)
+	if err != nil {
+		return err
 	}
 	
-	err := service.ProductRepository.Delete(ctx, id)
+	err = service.ProductRepository.Delete(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -318,7 +471,10 @@ func (service *ProductServiceImpl) DeleteProduct(ctx context.Context, payload he
 	return nil
 }
 
-func (service *ProductServiceImpl) UpdatePublished(ctx context.Context, payload helper.Payload, id uuid.UUID, res *bool) error {
+func (service *ProductServiceImpl) UpdatePublished(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.PublishResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProductService.UpdatePublished")
+	defer span.End()
 
 	if payload.Role != "ADMIN" {
 		err := service.ProductRepository.CheckOwner(ctx, payload.UserId, id)
@@ -327,6 +483,16 @@ func (service *ProductServiceImpl) UpdatePublished(ctx context.Context, payload
 		}
 	}
 
+	err := checkDispute(ctx, service.DisputeRepository, id)
+	if err != nil {
+		return err
+	}
+
+	err = checkAuction(ctx, service.ProductRepository, id)
+	if err != nil {
+		return err
+	}
+
 	hasThumbnail, err := service.ProductRepository.CheckThumbnail(ctx, id)
 	if err != nil {
 		return err
@@ -341,18 +507,105 @@ func (service *ProductServiceImpl) UpdatePublished(ctx context.Context, payload
 		return err
 	}
 
+	if !status && payload.Role != "ADMIN" {
+		err = service.checkUnderReview(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	approval, requiresApproval, err := service.ProductRepository.GetApproval(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// a listing that was not approved yet goes to the review queue instead
+	if !status && requiresApproval && approval != entity.ApprovalApproved {
+		err = service.ProductRepository.SubmitForReview(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		*res = web.PublishResponse{Published: false, ApprovalStatus: entity.ApprovalPending}
+		return nil
+	}
+
 	err = service.ProductRepository.Publish(ctx, id, !status)
 	if err != nil {
 		return err
 	}
 
-	*res = !status
+	if !status {
+		approval = entity.ApprovalApproved
+	}
+
+	*res = web.PublishResponse{Published: !status, ApprovalStatus: approval}
+
+	return nil
+}
+
+func (service *ProductServiceImpl) GetPendingReview(ctx context.Context, page web.PageRequest, res *[]web.PendingProductResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProductService.GetPendingReview")
+	defer span.End()
+
+	products, total, err := service.ProductRepository.GetPendingReview(ctx, page)
+	if err != nil {
+		return err
+	}
+
+	*res = products
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+func (service *ProductServiceImpl) Review(ctx context.Context, req web.ProductReviewRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProductService.Review")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service review product, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	_, _, err = service.ProductRepository.GetApproval(ctx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	status := entity.ApprovalApproved
+	if req.Decision == "REJECT" {
+		status = entity.ApprovalRejected
+	} else {
+		req.Reason = ""
+	}
+
+	err = service.ProductRepository.Review(ctx, req, status)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
+// initialApproval is the review state of a new or edited listing, edits in a
+// category that requires approval have to be reviewed again
+func initialApproval(category *entity.Category) string {
+	if category.RequiresApproval {
+		return entity.ApprovalDraft
+	}
+
+	return entity.ApprovalApproved
+}
+
 func (service *ProductServiceImpl) UpdateSold(ctx context.Context, payload helper.Payload, id uuid.UUID, res *bool) error {
 
+	ctx, span := helper.StartSpan(ctx, "ProductService.UpdateSold")
+	defer span.End()
+
 	if payload.Role != "ADMIN" {
 		err := service.ProductRepository.CheckOwner(ctx, payload.UserId, id)
 		if err != nil {
@@ -360,6 +613,16 @@ func (service *ProductServiceImpl) UpdateSold(ctx context.Context, payload helpe
 		}
 	}
 
+	err := checkDispute(ctx, service.DisputeRepository, id)
+	if err != nil {
+		return err
+	}
+
+	err = checkAuction(ctx, service.ProductRepository, id)
+	if err != nil {
+		return err
+	}
+
 	status, err := service.ProductRepository.CheckSold(ctx, id)
 	if err != nil {
 		return err
@@ -373,4 +636,51 @@ func (service *ProductServiceImpl) UpdateSold(ctx context.Context, payload helpe
 	*res = !status
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ReleaseReservation makes a reserved product available to every buyer again
+// when its sale will not go through
+func (service *ProductServiceImpl) ReleaseReservation(ctx context.Context, payload helper.Payload, id uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "ProductService.ReleaseReservation")
+	defer span.End()
+
+	if payload.Role != "ADMIN" {
+		err := service.ProductRepository.CheckOwner(ctx, payload.UserId, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := checkDispute(ctx, service.DisputeRepository, id)
+	if err != nil {
+		return err
+	}
+
+	return service.ProductRepository.ReleaseReservation(ctx, id)
+}
+
+// checkUnderReview keeps owners from publishing a listing a moderator hid or
+// one that is still reported by ReportThreshold people
+func (service *ProductServiceImpl) checkUnderReview(ctx context.Context, id uuid.UUID) error {
+
+	hidden, err := service.ProductRepository.CheckHidden(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if hidden {
+		return helper.NewBadRequest("this product was hidden by a moderator")
+	}
+
+	reports, err := service.ReportRepository.CountOpen(ctx, entity.ReportProduct, id)
+	if err != nil {
+		return err
+	}
+
+	if reports >= service.ReportThreshold {
+		return helper.NewBadRequest("this product is under review after being reported")
+	}
+
+	return nil
+}
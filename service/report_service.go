@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/RuhullahReza/SecondHand/model/web"
+	"github.com/RuhullahReza/SecondHand/repository"
+	"github.com/google/uuid"
+	"github.com/leebenson/conform"
+)
+
+type ReportService interface {
+	Create(ctx context.Context, req web.ReportRequest) error
+	GetQueue(ctx context.Context, filter web.ReportFilter, page web.PageRequest, res *[]web.ReportResponse, meta *helper.Meta) error
+	Resolve(ctx context.Context, req web.ResolveReportRequest) error
+}
+
+type ReportServiceImpl struct {
+	ReportRepository 		repository.ReportRepository
+	ProductRepository 		repository.ProductRepository
+	ProfileRepository 		repository.ProfileRepository
+	TransactionRepository 	repository.TransactionRepository
+	AccountRepository 		repository.AccountRepository
+	Threshold 				int
+	SLA 					time.Duration
+}
+
+func NewReportService(
+	reportRepository repository.ReportRepository,
+	productRepository repository.ProductRepository,
+	profileRepository repository.ProfileRepository,
+	transactionRepository repository.TransactionRepository,
+	accountRepository repository.AccountRepository,
+	threshold int,
+	sla time.Duration,
+	) ReportService {
+	return &ReportServiceImpl{
+		ReportRepository: reportRepository,
+		ProductRepository: productRepository,
+		ProfileRepository: profileRepository,
+		TransactionRepository: transactionRepository,
+		AccountRepository: accountRepository,
+		Threshold: threshold,
+		SLA: sla,
+	}
+}
+
+// Create files a report due within the SLA. A product reported by Threshold
+// people is unpublished right away, before any moderator looks at it.
+func (service *ReportServiceImpl) Create(ctx context.Context, req web.ReportRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "ReportService.Create")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service create report, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	reported, err := service.reportedAccount(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if reported == req.ReporterId {
+		return helper.NewBadRequest("you cannot report yourself")
+	}
+
+	report := &entity.Report{
+		ReporterId: req.ReporterId,
+		TargetType: req.TargetType,
+		TargetId: req.TargetId,
+		ReportedAccountId: reported,
+		Reason: req.Reason,
+		Details: req.Details,
+		DueAt: time.Now().Add(service.SLA),
+	}
+
+	err = service.ReportRepository.Create(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	if req.TargetType != entity.ReportProduct {
+		return nil
+	}
+
+	reports, err := service.ReportRepository.CountOpen(ctx, entity.ReportProduct, req.TargetId)
+	if err != nil {
+		return err
+	}
+
+	if reports < service.Threshold {
+		return nil
+	}
+
+	published, err := service.ProductRepository.CheckPublished(ctx, req.TargetId)
+	if err != nil {
+		return err
+	}
+
+	if published {
+		err = service.ProductRepository.Publish(ctx, req.TargetId, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportedAccount finds who a report is against: the seller of a product, the
+// owner of a profile or the other party of a transaction
+func (service *ReportServiceImpl) reportedAccount(ctx context.Context, req web.ReportRequest) (uuid.UUID, error) {
+
+	switch req.TargetType {
+	case entity.ReportProduct:
+		return service.ProductRepository.GetOwnerId(ctx, req.TargetId)
+
+	case entity.ReportProfile:
+		_, err := service.ProfileRepository.GetNameById(ctx, req.TargetId)
+		return req.TargetId, err
+
+	default:
+		transaction, err := service.TransactionRepository.GetTransactionById(ctx, req.TargetId)
+		if err != nil {
+			return uuid.Nil, err
+		}
+
+		switch req.ReporterId {
+		case transaction.BuyerId:
+			return transaction.SellerId, nil
+		case transaction.SellerId:
+			return transaction.BuyerId, nil
+		}
+
+		return uuid.Nil, helper.NewBadRequest("only the buyer and seller can report a transaction")
+	}
+}
+
+func (service *ReportServiceImpl) GetQueue(ctx context.Context, filter web.ReportFilter, page web.PageRequest, res *[]web.ReportResponse, meta *helper.Meta) error {
+
+	ctx, span := helper.StartSpan(ctx, "ReportService.GetQueue")
+	defer span.End()
+
+	reports, total, err := service.ReportRepository.GetQueue(ctx, filter, page)
+	if err != nil {
+		return err
+	}
+
+	*res = reports
+	*meta = helper.Meta{Page: page.Number(), PerPage: page.Limit(), Total: total}
+
+	return nil
+}
+
+// Resolve closes every open report on the same target with the moderator's
+// action, then applies it. Closing first keeps a second moderator from acting
+// on the same reports, they are reopened when the action fails.
+func (service *ReportServiceImpl) Resolve(ctx context.Context, req web.ResolveReportRequest) error {
+
+	ctx, span := helper.StartSpan(ctx, "ReportService.Resolve")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service resolve report, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	report, err := service.ReportRepository.GetById(ctx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if report.Status != entity.ReportOpen {
+		return helper.NewBadRequest("the report was already resolved")
+	}
+
+	report.Status = entity.ReportActioned
+	report.Action = sql.NullString{String: req.Action, Valid: true}
+	report.Note = req.Note
+	report.ResolvedBy = uuid.NullUUID{UUID: req.AdminId, Valid: true}
+
+	switch req.Action {
+	case entity.ReportDismiss:
+		report.Status = entity.ReportDismissed
+
+	case entity.ReportHideListing:
+		if report.TargetType != entity.ReportProduct {
+			return helper.NewBadRequest("only a product report can hide a listing")
+		}
+
+	case entity.ReportSuspendUser:
+		if !req.Until.After(time.Now()) {
+			return helper.NewBadRequest("a suspension must end in the future")
+		}
+
+		if report.ReportedAccountId == req.AdminId {
+			return helper.NewBadRequest("you cannot change the status of your own account")
+		}
+	}
+
+	ids, err := service.ReportRepository.Resolve(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	switch req.Action {
+	case entity.ReportHideListing:
+		err = service.ProductRepository.Hide(ctx, report.TargetId)
+
+	case entity.ReportSuspendUser:
+		reason := req.Note
+		if reason == "" {
+			reason = "reported for " + report.Reason
+		}
+
+		err = service.AccountRepository.SetStatus(ctx, &entity.AccountStatusChange{
+			AccountId: report.ReportedAccountId,
+			Status: entity.AccountSuspended,
+			SuspendedUntil: sql.NullTime{Time: *req.Until, Valid: true},
+			Reason: reason,
+			AdminId: req.AdminId,
+		})
+	}
+
+	if err != nil {
+		if reopenErr := service.ReportRepository.Reopen(ctx, ids); reopenErr != nil {
+			return reopenErr
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -2,44 +2,61 @@ package service
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/RuhullahReza/SecondHand/helper"
 	"github.com/RuhullahReza/SecondHand/model/entity"
 	"github.com/RuhullahReza/SecondHand/model/web"
 	"github.com/RuhullahReza/SecondHand/repository"
 	"github.com/google/uuid"
+	"github.com/leebenson/conform"
 )
 
 type TransactionService interface {
-	Create(ctx context.Context, req web.TransactionRequest) error
+	Create(ctx context.Context, req web.TransactionRequest, res *web.OfferResponse) error
 	GetTransactionDetail(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.TransactionDetailResponse) error
 	GetOfferByProduct(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.OfferByProduct) error
 	GetOfferByBuyer(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.OfferByBuyer) error
 	GetMyTransaction(ctx context.Context, id uuid.UUID, res *[]web.OfferWithAccount) error
 	GetOfferByAccount(ctx context.Context, id uuid.UUID, res *[]web.OfferWithAccount) error
-	UpdatePriceOffer(ctx context.Context, req web.TransactionUpdateRequest) error
+	UpdatePriceOffer(ctx context.Context, req web.TransactionUpdateRequest, res *web.OfferResponse) error
+	CounterOffer(ctx context.Context, req web.CounterOfferRequest) error
 	UpdateStatus(ctx context.Context, productId uuid.UUID, sellerId uuid.UUID, res *bool) error
+	AcceptCounter(ctx context.Context, id uuid.UUID, buyerId uuid.UUID) error
 }
 
 type TransactionServiceImpl struct {
 	ProductRepository		repository.ProductRepository
 	ProfileRepository		repository.ProfileRepository
 	TransactionRepository 	repository.TransactionRepository
+	DisputeRepository 		repository.DisputeRepository
+	ReservationWindow 		time.Duration
 }
 
 func NewTransactionSerive(
 	productRepository repository.ProductRepository, 
 	profileRepository repository.ProfileRepository,
 	transactionRepository repository.TransactionRepository,
+	disputeRepository repository.DisputeRepository,
+	reservationWindow time.Duration,
 	) TransactionService {
 	return &TransactionServiceImpl{
 		ProductRepository: productRepository,
 		ProfileRepository: profileRepository,
 		TransactionRepository: transactionRepository,
+		DisputeRepository: disputeRepository,
+		ReservationWindow: reservationWindow,
 	}
 }
 
-func (service *TransactionServiceImpl) Create(ctx context.Context, req web.TransactionRequest) error {
+// Create makes an offer on a published product. A buyer has at most one open
+// offer per product, offering again changes its price. The seller's pricing
+// rules decline or accept the offer right away.
+func (service *TransactionServiceImpl) Create(ctx context.Context, req web.TransactionRequest, res *web.OfferResponse) error {
+
+	ctx, span := helper.StartSpan(ctx, "TransactionService.Create")
+	defer span.End()
 
 	validProfile, err := service.ProfileRepository.CheckProfile(ctx, req.BuyerId)
 	if err != nil {
@@ -49,33 +66,91 @@ func (service *TransactionServiceImpl) Create(ctx context.Context, req web.Trans
 	if !validProfile {
 		return helper.NewBadRequest("complete your profile first")
 	}
+
+	err = conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service create transaction, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	if req.Price <= 0 {
+		return helper.NewBadRequest("price offer must be greater than zero")
+	}
 	
-	sellerId, err := service.ProductRepository.GetOwnerId(ctx, req.ProductId)
+	product, err := service.ProductRepository.GetState(ctx, req.ProductId)
 	if err != nil {
 		return err
 	}
 
-	if sellerId == req.BuyerId {
+	if product.AccountId == req.BuyerId {
 		return helper.NewBadRequest("you cannot buy your own product")
 	}
 
+	err = checkOfferable(product)
+	if err != nil {
+		return err
+	}
+
+	accept, err := applyPricing(product, req.Price)
+	if err != nil {
+		return err
+	}
+
 	newTransaction := &entity.Transaction{
-		SellerId: sellerId,
+		SellerId: product.AccountId,
 		BuyerId: req.BuyerId,
 		ProductId: req.ProductId,
 		PriceOffer: req.Price,
+		Accepted: accept,
 	}
 
-	err = service.TransactionRepository.Create(ctx, newTransaction)
+	created, err := service.TransactionRepository.Create(ctx, newTransaction, req.Note, time.Now().Add(service.ReservationWindow))
 	if err != nil {
 		return err
 	}
 
+	*res = web.OfferResponse{TransactionId: newTransaction.Id, Created: created, Accepted: accept}
+
+	return nil
+}
+
+// checkOfferable refuses offers on products that are deleted, sold, not
+// published, reserved or auctioned
+func checkOfferable(product *entity.Product) error {
+
+	switch {
+	case product.Deleted:
+		return helper.NewBadRequest("this product was deleted")
+	case product.Sold:
+		return helper.NewBadRequest("this product is already sold")
+	case !product.Published:
+		return helper.NewBadRequest("this product is not published")
+	case product.ReservedFor.Valid:
+		return helper.NewBadRequest("this product is reserved for another buyer")
+	case product.Auctioned:
+		return helper.NewBadRequest("this product is being auctioned")
+	}
+
 	return nil
 }
 
+// applyPricing declines a buyer's price below the seller's minimum, without
+// telling what the minimum is, and reports whether the price reaches the
+// seller's auto-accept price
+func applyPricing(product *entity.Product, price int64) (bool, error) {
+
+	if product.MinPrice.Valid && price < product.MinPrice.Int64 {
+		return false, helper.NewBadRequest("thank you for your offer, but the seller cannot accept this price. Please try a higher offer")
+	}
+
+	return product.AutoAcceptPrice.Valid && price >= product.AutoAcceptPrice.Int64, nil
+}
+
 func (service *TransactionServiceImpl) GetTransactionDetail(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.TransactionDetailResponse) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionService.GetTransactionDetail")
+	defer span.End()
+
 	transactions, err := service.TransactionRepository.GetTransactionDetail(ctx, id)
 	if err != nil {
 		return err
@@ -91,11 +166,44 @@ func (service *TransactionServiceImpl) GetTransactionDetail(ctx context.Context,
 
 	*res = transactions[0]
 
+	buyer, err := service.ProfileRepository.GetProfileById(ctx, res.BuyerId)
+	if err != nil {
+		return err
+	}
+
+	err = maskContact(ctx, service.ProfileRepository, buyer, payload)
+	if err != nil {
+		return err
+	}
+
+	seller, err := service.ProfileRepository.GetProfileById(ctx, res.SellerId)
+	if err != nil {
+		return err
+	}
+
+	err = maskContact(ctx, service.ProfileRepository, seller, payload)
+	if err != nil {
+		return err
+	}
+
+	res.BuyerPhoneNumber, res.BuyerAddress = buyer.PhoneNumber, buyer.Address
+	res.SellerPhoneNumber, res.SellerAddress = seller.PhoneNumber, seller.Address
+
+	negotiation, err := service.TransactionRepository.GetNegotiation(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	res.Negotiation = negotiation
+
 	return nil
 }
 
 func (service *TransactionServiceImpl) GetOfferByProduct(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.OfferByProduct) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionService.GetOfferByProduct")
+	defer span.End()
+
 	products, err := service.ProductRepository.GetProduct(ctx, id)
 	if err != nil {
 		return err
@@ -122,6 +230,9 @@ func (service *TransactionServiceImpl) GetOfferByProduct(ctx context.Context, pa
 
 func (service *TransactionServiceImpl) GetOfferByBuyer(ctx context.Context, payload helper.Payload, id uuid.UUID, res *web.OfferByBuyer) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionService.GetOfferByBuyer")
+	defer span.End()
+
 	profile, err := service.ProfileRepository.GetProfileById(ctx, id)
 	if err != nil {
 		return err
@@ -144,6 +255,9 @@ func (service *TransactionServiceImpl) GetOfferByBuyer(ctx context.Context, payl
 
 func (service *TransactionServiceImpl) GetOfferByAccount(ctx context.Context, id uuid.UUID, res *[]web.OfferWithAccount) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionService.GetOfferByAccount")
+	defer span.End()
+
 	transactions, err := service.TransactionRepository.GetOfferByAccount(ctx, id)
 	if err != nil {
 		return err
@@ -156,6 +270,9 @@ func (service *TransactionServiceImpl) GetOfferByAccount(ctx context.Context, id
 
 func (service *TransactionServiceImpl) GetMyTransaction(ctx context.Context, id uuid.UUID, res *[]web.OfferWithAccount) error {
 	
+	ctx, span := helper.StartSpan(ctx, "TransactionService.GetMyTransaction")
+	defer span.End()
+
 	transactions, err := service.TransactionRepository.GetMyTransaction(ctx, id)
 	if err != nil {
 		return err
@@ -166,25 +283,141 @@ func (service *TransactionServiceImpl) GetMyTransaction(ctx context.Context, id
 	return nil
 }
 
-func (service *TransactionServiceImpl) UpdatePriceOffer(ctx context.Context, req web.TransactionUpdateRequest) error {
+func (service *TransactionServiceImpl) UpdatePriceOffer(ctx context.Context, req web.TransactionUpdateRequest, res *web.OfferResponse) error {
 	
-	err := service.TransactionRepository.UpdatePrice(ctx, req.Price, req.TransactionId, req.BuyerId)
+	ctx, span := helper.StartSpan(ctx, "TransactionService.UpdatePriceOffer")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service update price offer, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	accepted, err := service.propose(ctx, &entity.OfferProposal{
+		TransactionId: req.TransactionId,
+		ProposedBy: req.BuyerId,
+		Role: entity.ProposalBuyer,
+		Amount: req.Price,
+		Note: req.Note,
+	})
 	if err != nil {
 		return err
 	}
 
+	*res = web.OfferResponse{TransactionId: req.TransactionId, Accepted: accepted}
+
 	return nil
 }
 
+// CounterOffer lets the seller answer an open offer with another price
+func (service *TransactionServiceImpl) CounterOffer(ctx context.Context, req web.CounterOfferRequest) error {
+	
+	ctx, span := helper.StartSpan(ctx, "TransactionService.CounterOffer")
+	defer span.End()
+
+	err := conform.Strings(&req)
+	if err != nil {
+		log.Printf("error while sanitize on service counter offer, err : %v\n", err)
+		return helper.NewInternal()
+	}
+
+	_, err = service.propose(ctx, &entity.OfferProposal{
+		TransactionId: req.TransactionId,
+		ProposedBy: req.SellerId,
+		Role: entity.ProposalSeller,
+		Amount: req.Price,
+		Note: req.Note,
+	})
+
+	return err
+}
+
+// propose records a new price on an open offer from the side in proposal.Role,
+// as long as the product can still receive offers. A buyer's price goes
+// through the seller's pricing rules, it reports whether it was accepted.
+func (service *TransactionServiceImpl) propose(ctx context.Context, proposal *entity.OfferProposal) (bool, error) {
+
+	if proposal.Amount <= 0 {
+		return false, helper.NewBadRequest("price offer must be greater than zero")
+	}
+
+	transaction, err := service.TransactionRepository.GetTransactionById(ctx, proposal.TransactionId)
+	if err != nil {
+		return false, err
+	}
+
+	party := transaction.BuyerId
+	if proposal.Role == entity.ProposalSeller {
+		party = transaction.SellerId
+	}
+
+	if party != proposal.ProposedBy {
+		return false, helper.NewNotFound("id", proposal.TransactionId.String())
+	}
+
+	if transaction.Accepted {
+		return false, helper.NewBadRequest("an accepted offer cannot be changed")
+	}
+
+	product, err := service.ProductRepository.GetState(ctx, transaction.ProductId)
+	if err != nil {
+		return false, err
+	}
+
+	err = checkOfferable(product)
+	if err != nil {
+		return false, err
+	}
+
+	accept := false
+	if proposal.Role == entity.ProposalBuyer {
+		accept, err = applyPricing(product, proposal.Amount)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = service.TransactionRepository.Propose(ctx, proposal, accept, time.Now().Add(service.ReservationWindow))
+	if err != nil {
+		return false, err
+	}
+
+	return accept, nil
+}
+
+// UpdateStatus accepts an open offer, which reserves the product for the
+// buyer for ReservationWindow, or takes an acceptance back. The seller's own
+// counter-offer is accepted by the buyer with AcceptCounter.
 func (service *TransactionServiceImpl) UpdateStatus(ctx context.Context, productId uuid.UUID, sellerId uuid.UUID, res *bool) error {
 	
-	accepted, err := service.TransactionRepository.CheckStatus(ctx, productId)
+	ctx, span := helper.StartSpan(ctx, "TransactionService.UpdateStatus")
+	defer span.End()
+
+	transaction, err := service.TransactionRepository.GetTransactionById(ctx, productId)
 	if err != nil {
 		return err
 	}
 
-	*res = !accepted
-	err = service.TransactionRepository.SetStatus(ctx, !accepted, productId, sellerId)
+	err = checkDispute(ctx, service.DisputeRepository, transaction.ProductId)
+	if err != nil {
+		return err
+	}
+
+	if !transaction.Accepted {
+		product, err := service.ProductRepository.GetState(ctx, transaction.ProductId)
+		if err != nil {
+			return err
+		}
+
+		err = checkOfferable(product)
+		if err != nil {
+			return err
+		}
+	}
+
+	*res = !transaction.Accepted
+	err = service.TransactionRepository.SetStatus(ctx, !transaction.Accepted, productId, sellerId, time.Now().Add(service.ReservationWindow))
 	if err != nil {
 		return err
 	}
@@ -192,8 +425,49 @@ func (service *TransactionServiceImpl) UpdateStatus(ctx context.Context, product
 	return nil
 }
 
+// AcceptCounter lets the buyer take the seller's counter-offer, which reserves
+// the product for the buyer for ReservationWindow
+func (service *TransactionServiceImpl) AcceptCounter(ctx context.Context, id uuid.UUID, buyerId uuid.UUID) error {
+
+	ctx, span := helper.StartSpan(ctx, "TransactionService.AcceptCounter")
+	defer span.End()
+
+	transaction, err := service.TransactionRepository.GetTransactionById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if transaction.BuyerId != buyerId {
+		return helper.NewNotFound("id", id.String())
+	}
+
+	if transaction.Accepted {
+		return helper.NewBadRequest("an accepted offer cannot be changed")
+	}
+
+	err = checkDispute(ctx, service.DisputeRepository, transaction.ProductId)
+	if err != nil {
+		return err
+	}
+
+	product, err := service.ProductRepository.GetState(ctx, transaction.ProductId)
+	if err != nil {
+		return err
+	}
+
+	err = checkOfferable(product)
+	if err != nil {
+		return err
+	}
+
+	return service.TransactionRepository.AcceptCounter(ctx, id, buyerId, time.Now().Add(service.ReservationWindow))
+}
+
 func (service *TransactionServiceImpl) DeleteTransaction(ctx context.Context, payload helper.Payload, product_id uuid.UUID) error {
 
+	ctx, span := helper.StartSpan(ctx, "TransactionService.DeleteTransaction")
+	defer span.End()
+
 	transaction, err := service.TransactionRepository.GetTransactionById(ctx, product_id)
 	if err != nil {
 		return err
@@ -203,6 +477,11 @@ func (service *TransactionServiceImpl) DeleteTransaction(ctx context.Context, pa
 		return helper.NewAuthorization("only buyer, seller, and admin can access")
 	}
 
+	err = checkDispute(ctx, service.DisputeRepository, transaction.ProductId)
+	if err != nil {
+		return err
+	}
+
 	err = service.TransactionRepository.DeleteOne(ctx, product_id)
 	if err != nil {
 		return err
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/RuhullahReza/SecondHand/helper"
+	"github.com/RuhullahReza/SecondHand/model/entity"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPricing(t *testing.T) {
+	declined := helper.NewBadRequest("thank you for your offer, but the seller cannot accept this price. Please try a higher offer")
+	minPrice := sql.NullInt64{Int64: 80000, Valid: true}
+	autoAccept := sql.NullInt64{Int64: 95000, Valid: true}
+
+	tests := []struct {
+		name       string
+		minPrice   sql.NullInt64
+		autoAccept sql.NullInt64
+		price      int64
+		accepted   bool
+		err        error
+	}{
+		{"no thresholds", sql.NullInt64{}, sql.NullInt64{}, 1000, false, nil},
+		{"below the minimum", minPrice, sql.NullInt64{}, 79999, false, declined},
+		{"at the minimum", minPrice, sql.NullInt64{}, 80000, false, nil},
+		{"above the minimum", minPrice, sql.NullInt64{}, 90000, false, nil},
+		{"below the auto-accept price", sql.NullInt64{}, autoAccept, 94999, false, nil},
+		{"at the auto-accept price", sql.NullInt64{}, autoAccept, 95000, true, nil},
+		{"above the auto-accept price", sql.NullInt64{}, autoAccept, 100000, true, nil},
+		{"between both", minPrice, autoAccept, 90000, false, nil},
+		{"below both", minPrice, autoAccept, 50000, false, declined},
+		{"above both", minPrice, autoAccept, 100000, true, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			product := &entity.Product{MinPrice: test.minPrice, AutoAcceptPrice: test.autoAccept}
+
+			accepted, err := applyPricing(product, test.price)
+			if test.err != nil {
+				require.EqualError(t, err, test.err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.accepted, accepted)
+		})
+	}
+}
@@ -18,7 +18,8 @@ import (
 type UserService interface {
 	Register(ctx context.Context, req web.CreateUserRequest, role string) error
 	Login(ctx context.Context, req web.LoginRequest, res *web.LoginResponse) error
-	GetProfile(ctx context.Context, res *web.GetProfileResponse, id uuid.UUID) error
+	GetProfile(ctx context.Context, res *web.GetProfileResponse, id uuid.UUID, viewer helper.Payload) error
+	UpdatePrivacy(ctx context.Context, settings web.PrivacySettings) error
 	UpdateProfile(ctx context.Context, req web.UpdateProfileRequest) error
 	UpdateImage(ctx context.Context, id uuid.UUID, imageFileHeader *multipart.FileHeader) error
 }
@@ -46,6 +47,9 @@ func NewUserService(
 
 func (service *UserServiceImpl) Register(ctx context.Context, req web.CreateUserRequest, role string) error  {
 
+	ctx, span := helper.StartSpan(ctx, "UserService.Register")
+	defer span.End()
+
 	err := conform.Strings(&req)
 	if err != nil {
 		log.Printf("error while sanitize on service register, err : %v\n", err)
@@ -64,7 +68,7 @@ func (service *UserServiceImpl) Register(ctx context.Context, req web.CreateUser
 		Role: role,
 	}
 
-	newAccount, err := service.AccountRepository.Create(context.Background(),account)
+	newAccount, err := service.AccountRepository.Create(ctx, account)
 	if err != nil {
 		return err
 	}
@@ -84,6 +88,9 @@ func (service *UserServiceImpl) Register(ctx context.Context, req web.CreateUser
 
 func (service *UserServiceImpl) Login(ctx context.Context, req web.LoginRequest, res *web.LoginResponse) error {
 
+	ctx, span := helper.StartSpan(ctx, "UserService.Login")
+	defer span.End()
+
 	err := conform.Strings(&req)
 	if err != nil {
 		log.Printf("error while sanitize on service login, err : %v\n", err)
@@ -107,6 +114,11 @@ func (service *UserServiceImpl) Login(ctx context.Context, req web.LoginRequest,
 		return helper.NewAuthorization("Invalid email and password combination")
 	}
 
+	err = accountStanding(foundUser)
+	if err != nil {
+		return err
+	}
+
 	foundProfile, err := service.ProfileRepository.GetNameById(ctx, foundUser.Id)
 
 	if err != nil {
@@ -125,7 +137,13 @@ func (service *UserServiceImpl) Login(ctx context.Context, req web.LoginRequest,
 	return nil
 }
 
-func (service *UserServiceImpl) GetProfile(ctx context.Context, res *web.GetProfileResponse, id uuid.UUID) error {
+// GetProfile loads the profile of id as seen by viewer, a zero Payload when
+// anonymous. Other people only see the location rounded to
+// util.CoordinatePrecision and the contact details their privacy settings allow.
+func (service *UserServiceImpl) GetProfile(ctx context.Context, res *web.GetProfileResponse, id uuid.UUID, viewer helper.Payload) error {
+
+	ctx, span := helper.StartSpan(ctx, "UserService.GetProfile")
+	defer span.End()
 
 	profile, err := service.ProfileRepository.GetProfileById(ctx, id)
 
@@ -133,35 +151,74 @@ func (service *UserServiceImpl) GetProfile(ctx context.Context, res *web.GetProf
 		return err
 	}
 
+	err = maskContact(ctx, service.ProfileRepository, profile, viewer)
+	if err != nil {
+		return err
+	}
+
 	res.Id = id
 	res.Name = profile.Name
 	res.City = profile.City
 	res.Address = profile.Address
 	res.PhoneNumber = profile.PhoneNumber
 	res.ImageUrl = profile.ImageUrl
+	res.Latitude = profile.Latitude
+	res.Longitude = profile.Longitude
+
+	if viewer.UserId != id && profile.Latitude != nil && profile.Longitude != nil {
+		lat, lng := util.RoundCoordinate(*profile.Latitude), util.RoundCoordinate(*profile.Longitude)
+		res.Latitude, res.Longitude = &lat, &lng
+	}
+
+	if viewer.UserId == id {
+		res.PhoneVisibility = profile.PhoneVisibility
+		res.AddressVisibility = profile.AddressVisibility
+	}
+
+	return nil
+}
+
+func (service *UserServiceImpl) UpdatePrivacy(ctx context.Context, settings web.PrivacySettings) error {
+
+	ctx, span := helper.StartSpan(ctx, "UserService.UpdatePrivacy")
+	defer span.End()
+
+	err := service.ProfileRepository.UpdatePrivacy(ctx, settings)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func (service *UserServiceImpl) UpdateProfile(ctx context.Context, req web.UpdateProfileRequest) error {
 
+	ctx, span := helper.StartSpan(ctx, "UserService.UpdateProfile")
+	defer span.End()
+
 	err := conform.Strings(&req)
 	if err != nil {
 		log.Printf("error while sanitize on service update profile, err : %v\n", err)
 		return helper.NewInternal()
 	}
 
-	err = service.DataRepository.CheckCityName(ctx, req.City)
+	city, err := service.DataRepository.FindCity(ctx, req.City)
 	if err != nil {
 		return err
 	}
 
+	if !city.ProvinceId.Valid {
+		return helper.NewBadRequest("choose a regency or city that belongs to a province")
+	}
+
 	newProfile := &entity.Profile{
 		Id: req.Id,
 		Name: req.Name,
-		City: req.City,
+		City: city.Name,
 		Address: req.Address,
 		PhoneNumber: req.PhoneNumber,
+		Latitude: req.Latitude,
+		Longitude: req.Longitude,
 		UpdatedAt: time.Now(),
 	}
 
@@ -175,6 +232,9 @@ func (service *UserServiceImpl) UpdateProfile(ctx context.Context, req web.Updat
 
 func (service *UserServiceImpl) UpdateImage(ctx context.Context, id uuid.UUID, imageFileHeader *multipart.FileHeader) error {
 
+	ctx, span := helper.StartSpan(ctx, "UserService.UpdateImage")
+	defer span.End()
+
 	profile, err := service.ProfileRepository.GetProfileImage(ctx, id)
 	if err != nil {
 		return err
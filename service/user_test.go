@@ -15,8 +15,9 @@ import (
 func TestCreate(t *testing.T) {
 	DB := db.NewPostgresConnection()
 	cld := db.NewCloudinaryConnection()
-	accountRepository := repository.NewAccountRepository(DB)
-	profileRepository := repository.NewProfileRepository(DB)
+	keyring := db.NewKeyring()
+	accountRepository := repository.NewAccountRepository(DB, keyring)
+	profileRepository := repository.NewProfileRepository(DB, keyring)
 	dataRepository := repository.NewDataRepository(DB)
 	imageRepository := repository.NewImageRepository(cld,DB)
 
@@ -35,8 +36,9 @@ func TestCreate(t *testing.T) {
 func TestFailCreate(t *testing.T) {
 	DB := db.NewPostgresConnection()
 	cld := db.NewCloudinaryConnection()
-	accountRepository := repository.NewAccountRepository(DB)
-	profileRepository := repository.NewProfileRepository(DB)
+	keyring := db.NewKeyring()
+	accountRepository := repository.NewAccountRepository(DB, keyring)
+	profileRepository := repository.NewProfileRepository(DB, keyring)
 	dataRepository := repository.NewDataRepository(DB)
 	imageRepository := repository.NewImageRepository(cld,DB)
 
@@ -56,8 +58,9 @@ func TestFailCreate(t *testing.T) {
 func TestLogin(t *testing.T) {
 	DB := db.NewPostgresConnection()
 	cld := db.NewCloudinaryConnection()
-	accountRepository := repository.NewAccountRepository(DB)
-	profileRepository := repository.NewProfileRepository(DB)
+	keyring := db.NewKeyring()
+	accountRepository := repository.NewAccountRepository(DB, keyring)
+	profileRepository := repository.NewProfileRepository(DB, keyring)
 	dataRepository := repository.NewDataRepository(DB)
 	imageRepository := repository.NewImageRepository(cld,DB)
 
@@ -77,8 +80,9 @@ func TestLogin(t *testing.T) {
 func TestFailLogin(t *testing.T) {
 	DB := db.NewPostgresConnection()
 	cld := db.NewCloudinaryConnection()
-	accountRepository := repository.NewAccountRepository(DB)
-	profileRepository := repository.NewProfileRepository(DB)
+	keyring := db.NewKeyring()
+	accountRepository := repository.NewAccountRepository(DB, keyring)
+	profileRepository := repository.NewProfileRepository(DB, keyring)
 	dataRepository := repository.NewDataRepository(DB)
 	imageRepository := repository.NewImageRepository(cld,DB)
 
@@ -99,8 +103,9 @@ func TestFailLogin(t *testing.T) {
 func TestUpdateProfile(t *testing.T) {
 	DB := db.NewPostgresConnection()
 	cld := db.NewCloudinaryConnection()
-	accountRepository := repository.NewAccountRepository(DB)
-	profileRepository := repository.NewProfileRepository(DB)
+	keyring := db.NewKeyring()
+	accountRepository := repository.NewAccountRepository(DB, keyring)
+	profileRepository := repository.NewProfileRepository(DB, keyring)
 	dataRepository := repository.NewDataRepository(DB)
 	imageRepository := repository.NewImageRepository(cld,DB)
 
@@ -112,7 +117,7 @@ func TestUpdateProfile(t *testing.T) {
 	request := &web.UpdateProfileRequest{
 		Id: id,
 		Name: "ozza Updated",
-		City: "Bandung",
+		City: "Kota Bandung",
 		Address: "alamat",
 		PhoneNumber: "08123456",
 	}
@@ -124,8 +129,9 @@ func TestUpdateProfile(t *testing.T) {
 func TestNotFoundUpdateProfile(t *testing.T) {
 	DB := db.NewPostgresConnection()
 	cld := db.NewCloudinaryConnection()
-	accountRepository := repository.NewAccountRepository(DB)
-	profileRepository := repository.NewProfileRepository(DB)
+	keyring := db.NewKeyring()
+	accountRepository := repository.NewAccountRepository(DB, keyring)
+	profileRepository := repository.NewProfileRepository(DB, keyring)
 	dataRepository := repository.NewDataRepository(DB)
 	imageRepository := repository.NewImageRepository(cld,DB)
 
@@ -137,7 +143,7 @@ func TestNotFoundUpdateProfile(t *testing.T) {
 	request := &web.UpdateProfileRequest{
 		Id: id,
 		Name: "ozza Updated",
-		City: "Bandung",
+		City: "Kota Bandung",
 		Address: "alamat",
 		PhoneNumber: "08123456",
 	}
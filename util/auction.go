@@ -0,0 +1,28 @@
+package util
+
+import (
+	"database/sql"
+	"time"
+)
+
+// MinimumBid is what the next bid on an auction has to reach, the start price
+// until someone bids and the high bid plus the increment after that
+func MinimumBid(startPrice int64, highBid sql.NullInt64, increment int64) int64 {
+
+	if !highBid.Valid {
+		return startPrice
+	}
+
+	return highBid.Int64 + increment
+}
+
+// ExtendDeadline is the end of an auction after a bid at now. A bid less than
+// extension before endsAt pushes the end to extension after the bid.
+func ExtendDeadline(endsAt time.Time, now time.Time, extension time.Duration) time.Time {
+
+	if endsAt.Sub(now) < extension {
+		return now.Add(extension)
+	}
+
+	return endsAt
+}
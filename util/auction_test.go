@@ -0,0 +1,49 @@
+package util
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinimumBid(t *testing.T) {
+	tests := []struct {
+		name    string
+		highBid sql.NullInt64
+		want    int64
+	}{
+		{"no bid yet", sql.NullInt64{}, 100000},
+		{"high bid", sql.NullInt64{Int64: 120000, Valid: true}, 125000},
+		{"high bid at the start price", sql.NullInt64{Int64: 100000, Valid: true}, 105000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, MinimumBid(100000, test.highBid, 5000))
+		})
+	}
+}
+
+func TestExtendDeadline(t *testing.T) {
+	endsAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	extension := 5 * time.Minute
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"early bid", endsAt.Add(-time.Hour), endsAt},
+		{"bid exactly extension before the end", endsAt.Add(-extension), endsAt},
+		{"late bid", endsAt.Add(-time.Minute), endsAt.Add(4 * time.Minute)},
+		{"bid at the last second", endsAt.Add(-time.Second), endsAt.Add(extension - time.Second)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, ExtendDeadline(endsAt, test.now, extension))
+		})
+	}
+}
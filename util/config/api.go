@@ -0,0 +1,16 @@
+package config
+
+import (
+	"log"
+	"os"
+	"github.com/joho/godotenv"
+)
+
+// LegacySunset is the HTTP-date after which the unversioned routes go away
+func LegacySunset() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	return os.Getenv("LEGACY_API_SUNSET")
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"log"
+	"os"
+	"github.com/joho/godotenv"
+)
+
+// EncryptionKeys lists the personal data keys as "id:base64key,id:base64key".
+// Keep retired keys here until the re-encrypt command has run.
+func EncryptionKeys() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	return os.Getenv("ENCRYPTION_KEYS")
+}
+
+// EncryptionActiveKey is the id of the key new values are written with
+func EncryptionActiveKey() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	return os.Getenv("ENCRYPTION_ACTIVE_KEY")
+}
+
+// BlindIndexKey is the base64 key behind the email lookup index. Changing it
+// invalidates every stored index.
+func BlindIndexKey() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	return os.Getenv("BLIND_INDEX_KEY")
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// JobInterval is how often the scheduler runs the background jobs
+func JobInterval() time.Duration {
+	return serverDuration("JOB_INTERVAL", 15*time.Minute)
+}
+
+// OfferTTL is how long an offer stays open without a new price from either side
+func OfferTTL() time.Duration {
+	return serverDuration("OFFER_TTL", 7*24*time.Hour)
+}
+
+// OfferReminderAfter is how long a buyer's price waits before the seller is
+// reminded to answer it
+func OfferReminderAfter() time.Duration {
+	return serverDuration("OFFER_REMINDER_AFTER", 48*time.Hour)
+}
+
+// StaleListingAfter is how long a published listing may go without changes
+// before it is unpublished, set in days with STALE_LISTING_DAYS
+func StaleListingAfter() time.Duration {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	value := os.Getenv("STALE_LISTING_DAYS")
+	if value == "" {
+		return 30 * 24 * time.Hour
+	}
+
+	days, err := strconv.Atoi(value)
+	if err != nil || days < 1 {
+		log.Fatalf("invalid STALE_LISTING_DAYS: %q", value)
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// ReservationWindow is how long a product stays reserved for the buyer of an
+// accepted offer before it is available again
+func ReservationWindow() time.Duration {
+	return serverDuration("RESERVATION_WINDOW", 72*time.Hour)
+}
+
+// DisputeResponseWindow is how long the buyer or the seller has to answer a
+// dispute before it is escalated to an admin
+func DisputeResponseWindow() time.Duration {
+	return serverDuration("DISPUTE_RESPONSE_WINDOW", 72*time.Hour)
+}
+
+// AuctionExtension is how close to its end a bid pushes an auction back, the
+// auction then ends AuctionExtension after the bid
+func AuctionExtension() time.Duration {
+	return serverDuration("AUCTION_EXTENSION", 5*time.Minute)
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// ReportThreshold is how many people must report a listing before it is
+// unpublished automatically
+func ReportThreshold() int {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	value := os.Getenv("REPORT_THRESHOLD")
+	if value == "" {
+		return 3
+	}
+
+	threshold, err := strconv.Atoi(value)
+	if err != nil || threshold < 1 {
+		log.Fatalf("invalid REPORT_THRESHOLD: %q", value)
+	}
+	return threshold
+}
+
+// ReportSLA is how long moderators have to decide on a report
+func ReportSLA() time.Duration {
+	return serverDuration("REPORT_SLA", 24*time.Hour)
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+)
+
+// PaymentProvider names the payment gateway, only "fake" exists so far
+func PaymentProvider() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	value := os.Getenv("PAYMENT_PROVIDER")
+	if value == "" {
+		return "fake"
+	}
+	return value
+}
+
+// PaymentWebhookSecret is the key payment providers sign webhooks with
+func PaymentWebhookSecret() []byte {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	return []byte(os.Getenv("PAYMENT_WEBHOOK_SECRET"))
+}
+
+// PlatformFeePercent is the share of a sale the platform keeps when the money
+// is released to the seller
+func PlatformFeePercent() int64 {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	value := os.Getenv("PLATFORM_FEE_PERCENT")
+	if value == "" {
+		return 5
+	}
+
+	percent, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || percent < 0 || percent > 100 {
+		log.Fatalf("invalid PLATFORM_FEE_PERCENT: %q", value)
+	}
+	return percent
+}
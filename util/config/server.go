@@ -0,0 +1,56 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+func ServerAddress() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	addr := os.Getenv("SERVER_ADDRESS")
+	if addr == "" {
+		return ":3000"
+	}
+	return addr
+}
+
+func ServerReadTimeout() time.Duration {
+	return serverDuration("SERVER_READ_TIMEOUT", 15*time.Second)
+}
+
+func ServerWriteTimeout() time.Duration {
+	return serverDuration("SERVER_WRITE_TIMEOUT", 30*time.Second)
+}
+
+func ServerIdleTimeout() time.Duration {
+	return serverDuration("SERVER_IDLE_TIMEOUT", 60*time.Second)
+}
+
+func ServerShutdownTimeout() time.Duration {
+	return serverDuration("SERVER_SHUTDOWN_TIMEOUT", 20*time.Second)
+}
+
+func serverDuration(key string, fallback time.Duration) time.Duration {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("invalid duration for %s: %v", key, err)
+	}
+	return duration
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"log"
+	"os"
+	"github.com/joho/godotenv"
+)
+
+func TracingExporter() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	return os.Getenv("TRACING_EXPORTER")
+}
+
+func TracingEndpoint() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	return os.Getenv("TRACING_OTLP_ENDPOINT")
+}
+
+func TracingInsecure() bool {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	return os.Getenv("TRACING_OTLP_INSECURE") == "true"
+}
+
+func TracingServiceName() string {
+	err := godotenv.Load()
+	if err != nil {
+		log.Fatal("Error loading .env file")
+	}
+
+	name := os.Getenv("TRACING_SERVICE_NAME")
+	if name == "" {
+		return "secondhand"
+	}
+	return name
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CoordinatePrecision is the number of decimals kept when a location is shown
+// to someone other than its owner, roughly one kilometre
+const CoordinatePrecision = 2
+
+// RoundCoordinate rounds a latitude or longitude to CoordinatePrecision
+func RoundCoordinate(v float64) float64 {
+	scale := math.Pow(10, CoordinatePrecision)
+	return math.Round(v*scale) / scale
+}
+
+// ParseCoordinates reads a "lat,lng" pair such as "-6.2,106.8"
+func ParseCoordinates(s string) (float64, float64, error) {
+
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("coordinates must be lat,lng")
+	}
+
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return 0, 0, errors.New("latitude must be between -90 and 90")
+	}
+
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return 0, 0, errors.New("longitude must be between -180 and 180")
+	}
+
+	return lat, lng, nil
+}
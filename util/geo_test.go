@@ -0,0 +1,63 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundCoordinate(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want float64
+	}{
+		{-6.200000, -6.2},
+		{-6.2088, -6.21},
+		{106.8456, 106.85},
+		{106.8449, 106.84},
+		{0.005, 0.01},
+		{-0.004, 0},
+		{90, 90},
+		{-180, -180},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, RoundCoordinate(test.in), "RoundCoordinate(%v)", test.in)
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lng float64
+		err      string
+	}{
+		{"-6.2,106.8", -6.2, 106.8, ""},
+		{" -6.2 , 106.8 ", -6.2, 106.8, ""},
+		{"90,180", 90, 180, ""},
+		{"-90,-180", -90, -180, ""},
+		{"90.0001,0", 0, 0, "latitude must be between -90 and 90"},
+		{"-90.0001,0", 0, 0, "latitude must be between -90 and 90"},
+		{"0,180.0001", 0, 0, "longitude must be between -180 and 180"},
+		{"0,-180.0001", 0, 0, "longitude must be between -180 and 180"},
+		{"abc,106.8", 0, 0, "latitude must be between -90 and 90"},
+		{"-6.2,", 0, 0, "longitude must be between -180 and 180"},
+		{"NaN,106.8", 0, 0, "latitude must be between -90 and 90"},
+		{"-6.2,NaN", 0, 0, "longitude must be between -180 and 180"},
+		{"-6.2", 0, 0, "coordinates must be lat,lng"},
+		{"-6.2,106.8,1", 0, 0, "coordinates must be lat,lng"},
+		{"", 0, 0, "coordinates must be lat,lng"},
+	}
+
+	for _, test := range tests {
+		lat, lng, err := ParseCoordinates(test.in)
+		if test.err != "" {
+			require.EqualError(t, err, test.err, "ParseCoordinates(%q)", test.in)
+			continue
+		}
+
+		require.NoError(t, err, "ParseCoordinates(%q)", test.in)
+		require.Equal(t, test.lat, lat)
+		require.Equal(t, test.lng, lng)
+	}
+}
@@ -0,0 +1,164 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// encryptedPrefix marks a column value written by Keyring.Encrypt, the full
+// format is "enc:<key id>:<base64 nonce and ciphertext>"
+const encryptedPrefix = "enc:"
+
+// Keyring encrypts personal data with AES-256-GCM. Values are always written
+// with the active key and can be read with any key still on the ring, which is
+// how keys rotate. The index key derives blind indexes for equality lookups.
+type Keyring struct {
+	keys     map[string]cipher.AEAD
+	active   string
+	indexKey []byte
+}
+
+// NewKeyring builds a keyring from 32 byte keys by id
+func NewKeyring(keys map[string][]byte, active string, indexKey []byte) (*Keyring, error) {
+
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("active key %q is not on the keyring", active)
+	}
+
+	if len(indexKey) < 32 {
+		return nil, errors.New("blind index key must be at least 32 bytes")
+	}
+
+	ring := &Keyring{keys: map[string]cipher.AEAD{}, active: active, indexKey: indexKey}
+
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		ring.keys[id] = aead
+	}
+
+	return ring, nil
+}
+
+// ParseKeys reads "id:base64key,id:base64key" as found in ENCRYPTION_KEYS
+func ParseKeys(s string) (map[string][]byte, error) {
+
+	keys := map[string][]byte{}
+
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("key entry %q must be id:base64key", entry)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %q is not valid base64", id)
+		}
+
+		keys[id] = key
+	}
+
+	return keys, nil
+}
+
+// Encrypt seals plain with the active key. Empty strings stay empty so
+// "not filled in" checks keep working on encrypted columns.
+func (k *Keyring) Encrypt(plain string) (string, error) {
+
+	if plain == "" {
+		return "", nil
+	}
+
+	aead := k.keys[k.active]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plain), nil)
+
+	return encryptedPrefix + k.active + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value written by Encrypt. Values without the prefix are rows
+// from before encryption and are returned unchanged.
+func (k *Keyring) Decrypt(stored string) (string, error) {
+
+	if !strings.HasPrefix(stored, encryptedPrefix) {
+		return stored, nil
+	}
+
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(stored, encryptedPrefix), ":")
+	if !ok {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	aead, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("key %q is not on the keyring", id)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
+
+// Stale reports whether stored is plaintext or sealed with a key other than
+// the active one, meaning it should be re-encrypted
+func (k *Keyring) Stale(stored string) bool {
+
+	if stored == "" {
+		return false
+	}
+
+	return !strings.HasPrefix(stored, encryptedPrefix+k.active+":")
+}
+
+// BlindIndex is a keyed hash of the normalized value, equal inputs give equal
+// indexes without storing anything that can be reversed
+func (k *Keyring) BlindIndex(value string) string {
+
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(value))))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
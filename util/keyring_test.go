@@ -0,0 +1,75 @@
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T, keys map[string][]byte, active string) *Keyring {
+	ring, err := NewKeyring(keys, active, bytes.Repeat([]byte("i"), 32))
+	require.NoError(t, err)
+	return ring
+}
+
+func TestKeyringRoundTrip(t *testing.T) {
+	ring := testKeyring(t, map[string][]byte{"k1": bytes.Repeat([]byte("a"), 32)}, "k1")
+
+	sealed, err := ring.Encrypt("08123456789")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(sealed, "enc:k1:"))
+	require.NotContains(t, sealed, "08123456789")
+
+	plain, err := ring.Decrypt(sealed)
+	require.NoError(t, err)
+	require.Equal(t, "08123456789", plain)
+
+	empty, err := ring.Encrypt("")
+	require.NoError(t, err)
+	require.Empty(t, empty)
+
+	legacy, err := ring.Decrypt("jalan merdeka")
+	require.NoError(t, err)
+	require.Equal(t, "jalan merdeka", legacy)
+}
+
+func TestKeyringRotation(t *testing.T) {
+	old := testKeyring(t, map[string][]byte{"k1": bytes.Repeat([]byte("a"), 32)}, "k1")
+	sealed, err := old.Encrypt("user@mail.com")
+	require.NoError(t, err)
+
+	rotated := testKeyring(t, map[string][]byte{
+		"k1": bytes.Repeat([]byte("a"), 32),
+		"k2": bytes.Repeat([]byte("b"), 32),
+	}, "k2")
+
+	require.True(t, rotated.Stale(sealed))
+	require.True(t, rotated.Stale("user@mail.com"))
+
+	plain, err := rotated.Decrypt(sealed)
+	require.NoError(t, err)
+	require.Equal(t, "user@mail.com", plain)
+
+	resealed, err := rotated.Encrypt(plain)
+	require.NoError(t, err)
+	require.False(t, rotated.Stale(resealed))
+}
+
+func TestBlindIndex(t *testing.T) {
+	ring := testKeyring(t, map[string][]byte{"k1": bytes.Repeat([]byte("a"), 32)}, "k1")
+
+	require.Equal(t, ring.BlindIndex("User@Mail.com "), ring.BlindIndex("user@mail.com"))
+	require.NotEqual(t, ring.BlindIndex("user@mail.com"), ring.BlindIndex("other@mail.com"))
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("k1:YWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWFhYWE=, k2:YmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmJiYmI=")
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	require.Equal(t, bytes.Repeat([]byte("a"), 32), keys["k1"])
+
+	_, err = ParseKeys("k1")
+	require.Error(t, err)
+}
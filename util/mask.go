@@ -0,0 +1,14 @@
+package util
+
+import "strings"
+
+// MaskPhone keeps the first four and last two characters of a phone number,
+// "08123456789" becomes "0812*****89"
+func MaskPhone(phone string) string {
+
+	if len(phone) <= 6 {
+		return strings.Repeat("*", len(phone))
+	}
+
+	return phone[:4] + strings.Repeat("*", len(phone)-6) + phone[len(phone)-2:]
+}
@@ -0,0 +1,25 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskPhone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"12", "**"},
+		{"123456", "******"},
+		{"1234567", "1234*67"},
+		{"08123456789", "0812*****89"},
+		{"+6281234567890", "+628********90"},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, MaskPhone(test.in), "MaskPhone(%q)", test.in)
+	}
+}
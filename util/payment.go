@@ -0,0 +1,133 @@
+package util
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Statuses of a charge at the payment provider. An authorized charge holds the
+// buyer's money until it is captured for the seller or refunded.
+const (
+	ChargePending    = "PENDING"
+	ChargeAuthorized = "AUTHORIZED"
+	ChargeCaptured   = "CAPTURED"
+	ChargeRefunded   = "REFUNDED"
+)
+
+// ErrChargeState is returned when a charge cannot be captured or refunded in
+// its current status
+var ErrChargeState = errors.New("charge cannot change from its current status")
+
+// ErrChargeAmount is returned when capturing more than a charge authorized
+var ErrChargeAmount = errors.New("charge cannot capture more than it authorized")
+
+type Charge struct {
+	Id         string
+	Status     string
+	Amount     int64
+	Captured   int64
+	PaymentUrl string
+}
+
+// PaymentGateway is a payment provider. The buyer pays a charge at PaymentUrl,
+// the provider then reports the authorization to the webhook. Capturing less
+// than the authorized amount gives the rest back to the buyer.
+type PaymentGateway interface {
+	Name() string
+	CreateCharge(ctx context.Context, reference string, amount int64) (Charge, error)
+	Capture(ctx context.Context, chargeId string, amount int64) (Charge, error)
+	Refund(ctx context.Context, chargeId string) (Charge, error)
+}
+
+// FakeGateway keeps charges in memory for local testing. Nobody pays at the
+// fake PaymentUrl, post a signed charge.authorized event to the webhook
+// instead. The fake does not hear of webhooks, so it captures and refunds
+// pending charges too.
+type FakeGateway struct {
+	mu      sync.Mutex
+	charges map[string]*Charge
+}
+
+func NewFakeGateway() *FakeGateway {
+	return &FakeGateway{charges: map[string]*Charge{}}
+}
+
+func (g *FakeGateway) Name() string {
+	return "fake"
+}
+
+func (g *FakeGateway) CreateCharge(ctx context.Context, reference string, amount int64) (Charge, error) {
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := "fake_" + uuid.NewString()
+	charge := &Charge{Id: id, Status: ChargePending, Amount: amount, PaymentUrl: "https://pay.example.com/fake/" + id + "?reference=" + reference}
+	g.charges[id] = charge
+
+	return *charge, nil
+}
+
+// Capture takes the held money for the seller. Charges created before a
+// restart are unknown to the fake and treated as authorized.
+func (g *FakeGateway) Capture(ctx context.Context, chargeId string, amount int64) (Charge, error) {
+	return g.move(chargeId, ChargeCaptured, amount)
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, chargeId string) (Charge, error) {
+	return g.move(chargeId, ChargeRefunded, 0)
+}
+
+func (g *FakeGateway) move(chargeId string, status string, captured int64) (Charge, error) {
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[chargeId]
+	if !ok {
+		charge = &Charge{Id: chargeId, Status: ChargeAuthorized, Amount: captured}
+		g.charges[chargeId] = charge
+	}
+
+	if charge.Status != ChargeAuthorized && charge.Status != ChargePending {
+		return *charge, ErrChargeState
+	}
+
+	if captured < 0 || captured > charge.Amount {
+		return *charge, ErrChargeAmount
+	}
+
+	charge.Status = status
+	charge.Captured = captured
+
+	return *charge, nil
+}
+
+// SignWebhook is the hex HMAC-SHA256 of a webhook body, sent by the provider
+// in the X-Signature header
+func SignWebhook(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook tells whether signature is the signature of body, in constant
+// time
+func VerifyWebhook(secret []byte, body []byte, signature string) bool {
+
+	given, err := hex.DecodeString(signature)
+	if err != nil || len(secret) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return hmac.Equal(given, mac.Sum(nil))
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := []byte(`{"id":"evt_1","type":"charge.authorized","charge_id":"fake_1"}`)
+
+	signature := SignWebhook(secret, body)
+	require.True(t, VerifyWebhook(secret, body, signature))
+
+	require.False(t, VerifyWebhook([]byte("other-secret"), body, signature))
+	require.False(t, VerifyWebhook(secret, []byte(`{"id":"evt_2"}`), signature))
+	require.False(t, VerifyWebhook(secret, body, "not-hex"))
+	require.False(t, VerifyWebhook(nil, body, SignWebhook(nil, body)))
+}
+
+func TestFakeGateway(t *testing.T) {
+	ctx := context.Background()
+	gateway := NewFakeGateway()
+
+	charge, err := gateway.CreateCharge(ctx, "order-1", 150000)
+	require.NoError(t, err)
+	require.Equal(t, ChargePending, charge.Status)
+	require.Equal(t, int64(150000), charge.Amount)
+	require.NotEmpty(t, charge.PaymentUrl)
+
+	_, err = gateway.Capture(ctx, charge.Id, 150001)
+	require.ErrorIs(t, err, ErrChargeAmount)
+
+	captured, err := gateway.Capture(ctx, charge.Id, 100000)
+	require.NoError(t, err)
+	require.Equal(t, ChargeCaptured, captured.Status)
+	require.Equal(t, int64(100000), captured.Captured)
+
+	_, err = gateway.Refund(ctx, charge.Id)
+	require.ErrorIs(t, err, ErrChargeState)
+
+	refunded, err := gateway.Refund(ctx, "fake_from_before_restart")
+	require.NoError(t, err)
+	require.Equal(t, ChargeRefunded, refunded.Status)
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Slugify lower-cases s and joins its letters and digits with single dashes,
+// "Elektronik & Gadget" becomes "elektronik-gadget"
+func Slugify(s string) string {
+
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(strings.TrimSpace(s)) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Elektronik & Gadget", "elektronik-gadget"},
+		{"Hobi -- & -- Koleksi!!", "hobi-koleksi"},
+		{"  Buku  ", "buku"},
+		{"--Mainan Anak--", "mainan-anak"},
+		{"!!!Olahraga???", "olahraga"},
+		{"Kamera 35mm", "kamera-35mm"},
+		{"Café Crème", "caf-cr-me"},
+		{"Ñandú", "and"},
+		{"日本", ""},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, Slugify(test.in), "Slugify(%q)", test.in)
+	}
+}